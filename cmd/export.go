@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/vamshi1997/pismo-assessment/internal/boot"
+	"github.com/vamshi1997/pismo-assessment/internal/export"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+)
+
+// runExport writes transactions to a file or to stdout, e.g.
+//
+//	main export -format ofx -account 1 -from 2025-02-01 -to 2025-02-28 -out february.ofx
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	formatName := flags.String("format", string(export.CSV), "csv, ndjson or ofx")
+	accountID := flags.Uint("account", 0, "only export the transactions of this account")
+	from := flags.String("from", "", "only export transactions from this date (YYYY-MM-DD or RFC 3339)")
+	to := flags.String("to", "", "only export transactions up to this date (YYYY-MM-DD or RFC 3339)")
+	outPath := flags.String("out", "", "file to write to, stdout when empty")
+	batchSize := flags.Int("batch", 1000, "transactions read from the database at a time")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+	if *batchSize < 1 {
+		return fmt.Errorf("batch should be positive")
+	}
+
+	filter := repo.ExportFilter{AccountID: *accountID}
+	if *from != "" {
+		if filter.FromEventDate, err = model.ParseEventDateBound(*from, false); err != nil {
+			return fmt.Errorf("invalid from %q: %w", *from, err)
+		}
+	}
+	if *to != "" {
+		if filter.ToEventDate, err = model.ParseEventDateBound(*to, true); err != nil {
+			return fmt.Errorf("invalid to %q: %w", *to, err)
+		}
+	}
+
+	var out io.Writer = os.Stdout
+	if *outPath != "" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	writer, err := export.NewWriter(format, out)
+	if err != nil {
+		return err
+	}
+
+	// an export only reads, so it never migrates the database and refuses to run on an outdated schema
+	boot.InitConfig()
+	boot.InitTracing()
+	defer boot.ShutdownTracing(context.Background())
+
+	db, err := boot.ConnectDb()
+	if err != nil {
+		return err
+	}
+	defer boot.CloseDB()
+	if err := boot.RequireCurrentSchema(db); err != nil {
+		return fmt.Errorf("%w, run the pending migrations with: main migrate up", err)
+	}
+
+	written, err := export.Export(context.Background(), repo.NewRepository(db), filter, writer, *batchSize)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("transactions exported", "count", written, "format", format)
+	return nil
+}
@@ -1,13 +1,207 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"github.com/vamshi1997/pismo-assessment/internal/accrual"
 	"github.com/vamshi1997/pismo-assessment/internal/boot"
+	"github.com/vamshi1997/pismo-assessment/internal/events"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
 	"github.com/vamshi1997/pismo-assessment/internal/router"
+	"github.com/vamshi1997/pismo-assessment/internal/statement"
+	"github.com/vamshi1997/pismo-assessment/internal/webhook"
+	"io"
 	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 )
 
 func main() {
-	log.Println("Starting Go Web Application")
+	configFile := flag.String("config", "", "config file, default.toml in /app/configs or ./configs when empty (env "+boot.ConfigFileEnv+")")
+	flag.Parse()
+	boot.SetConfigFile(*configFile)
+
+	args := flag.Args()
+	if len(args) > 0 && args[0] == "export" {
+		if err := runExport(args[1:]); err != nil {
+			log.Fatalln("Export failed:", err)
+		}
+		return
+	}
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := runMigrate(args[1:]); err != nil {
+			log.Fatalln("Migration failed:", err)
+		}
+		return
+	}
+	if len(args) > 0 {
+		log.Fatalf("Unknown command %q, expected export or migrate", args[0])
+	}
+
+	slog.Info("starting Go web application")
 	boot.InitApp()
-	router.InitiateRouter(boot.GetDB())
+
+	stop := make(chan struct{})
+	var workers sync.WaitGroup
+	startEventRelay(stop, &workers)
+	startWebhookWorker(stop, &workers)
+	startStatementGenerator(stop, &workers)
+	startAccrualEngine(stop, &workers)
+
+	app := boot.GetConfig().AppConfig
+	server := router.NewServer(boot.GetDB(), app.Server, app.Metrics)
+	if err := serve(server, app.Server.ShutdownTimeout, stop, &workers); err != nil {
+		log.Fatalln("Server failed:", err)
+	}
+}
+
+// serve runs the HTTP server until SIGINT or SIGTERM, then shuts down gracefully: in-flight requests
+// are drained, the background workers are stopped, the remaining spans are exported and the database
+// pool is closed. Requests and workers which are not done within the shutdown timeout are abandoned.
+// A second signal during the shutdown stops the process right away.
+func serve(server *http.Server, shutdownTimeout time.Duration, stop chan struct{}, workers *sync.WaitGroup) error {
+	signals, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	// the server is only reported as started once its address is bound. A failure to bind, e.g. because
+	// the port is in use, goes through the same shutdown as a server which stopped on its own.
+	addr := server.Addr
+	if addr == "" {
+		addr = ":http"
+	}
+	serverErr := make(chan error, 1)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		serverErr <- err
+	} else {
+		slog.Info("server started", "addr", listener.Addr().String())
+		go func() {
+			serverErr <- server.Serve(listener)
+		}()
+	}
+
+	select {
+	case <-signals.Done():
+		slog.Info("shutdown signal received, shutting down")
+	case err = <-serverErr:
+		slog.Error("error while running the server", "error", err)
+	}
+	stopSignals()
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if shutdownErr := server.Shutdown(ctx); shutdownErr != nil {
+		slog.Error("not able to drain in-flight requests before the deadline", "error", shutdownErr)
+	} else {
+		slog.Info("in-flight requests drained")
+	}
+
+	close(stop)
+	finished := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		slog.Info("background workers stopped")
+	case <-ctx.Done():
+		slog.Warn("background workers did not stop before the deadline")
+	}
+
+	if tracingErr := boot.ShutdownTracing(ctx); tracingErr != nil {
+		slog.Error("not able to export the remaining spans", "error", tracingErr)
+	}
+
+	if closeErr := boot.CloseDB(); closeErr != nil {
+		slog.Error("not able to close the database pool", "error", closeErr)
+	} else {
+		slog.Info("database pool closed, bye")
+	}
+
+	return err
+}
+
+// startEventRelay publishes the events written to the outbox in the background, to the webhook
+// subscriptions and to the configured publisher, which is closed once the relay stops
+func startEventRelay(stop <-chan struct{}, workers *sync.WaitGroup) {
+	cfg := boot.GetConfig().AppConfig.Events
+	newRepo := repo.NewRepository(boot.GetDB())
+
+	publishers := events.MultiPublisher{webhook.NewPublisher(newRepo)}
+	if cfg.Publisher != "" {
+		publisher, err := events.NewPublisher(cfg.Publisher, cfg.FilePath)
+		if err != nil {
+			slog.Error("not able to create event publisher", "publisher", cfg.Publisher, "error", err)
+			panic(err)
+		}
+		publishers = append(publishers, publisher)
+	}
+
+	relay := events.NewRelay(newRepo, publishers, cfg.PollInterval, cfg.BatchSize)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		relay.Run(stop)
+		for _, publisher := range publishers {
+			if closer, ok := publisher.(io.Closer); ok {
+				if err := closer.Close(); err != nil {
+					slog.Error("not able to close event publisher", "error", err)
+				}
+			}
+		}
+	}()
+	slog.Info("outbox relay started", "publishers", len(publishers))
+}
+
+// startWebhookWorker sends the webhook deliveries in the background
+func startWebhookWorker(stop <-chan struct{}, workers *sync.WaitGroup) {
+	cfg := boot.GetConfig().AppConfig.Webhooks
+
+	worker := webhook.NewWorker(repo.NewRepository(boot.GetDB()), webhook.WorkerConfig{
+		PollInterval: cfg.PollInterval,
+		BatchSize:    cfg.BatchSize,
+		Timeout:      cfg.Timeout,
+		MaxAttempts:  cfg.MaxAttempts,
+		BaseBackoff:  cfg.BaseBackoff,
+		MaxBackoff:   cfg.MaxBackoff,
+	})
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		worker.Run(stop)
+	}()
+	slog.Info("webhook delivery worker started")
+}
+
+// startStatementGenerator closes the billing cycles of the accounts in the background
+func startStatementGenerator(stop <-chan struct{}, workers *sync.WaitGroup) {
+	cfg := boot.GetConfig().AppConfig.Statements
+
+	generator := statement.NewGenerator(repo.NewRepository(boot.GetDB()), cfg.PollInterval, cfg.BatchSize)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		generator.Run(stop)
+	}()
+	slog.Info("statement generator started")
+}
+
+// startAccrualEngine posts interest and late fees on overdue debt in the background
+func startAccrualEngine(stop <-chan struct{}, workers *sync.WaitGroup) {
+	cfg := boot.GetConfig().AppConfig.Accruals
+
+	engine := accrual.NewEngine(repo.NewRepository(boot.GetDB()), cfg.PollInterval, cfg.BatchSize)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		engine.Run(stop)
+	}()
+	slog.Info("accrual engine started")
 }
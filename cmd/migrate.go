@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vamshi1997/pismo-assessment/internal/boot"
+	"github.com/vamshi1997/pismo-assessment/internal/migration"
+)
+
+const migrateUsage = "usage: main migrate up [-steps n] | down [-steps n] | status"
+
+// runMigrate applies, rolls back or lists the database migrations, e.g.
+//
+//	main migrate up
+//	main migrate down -steps 1
+//	main migrate status
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(migrateUsage)
+	}
+
+	command := args[0]
+	flags := flag.NewFlagSet("migrate "+command, flag.ContinueOnError)
+	var steps *int
+	switch command {
+	case "up":
+		steps = flags.Int("steps", 0, "number of migrations to apply, all pending ones when 0")
+	case "down":
+		steps = flags.Int("steps", 1, "number of migrations to roll back")
+	case "status":
+	default:
+		return fmt.Errorf("unknown migrate command %q, %s", command, migrateUsage)
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if steps != nil && *steps < 0 {
+		return fmt.Errorf("steps should not be negative")
+	}
+
+	boot.InitConfig()
+	db, err := boot.OpenDB(boot.GetConfig().AppConfig.DB)
+	if err != nil {
+		return err
+	}
+
+	migrator, err := migration.New(db)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "up":
+		if err := boot.UpgradeLegacySchema(db); err != nil {
+			return err
+		}
+		applied, err := migrator.Up(*steps)
+		if err != nil {
+			return err
+		}
+		if err := boot.SeedOperationTypes(db); err != nil {
+			return err
+		}
+		log.Printf("Applied %d migrations", len(applied))
+	case "down":
+		rolledBack, err := migrator.Down(*steps)
+		if err != nil {
+			return err
+		}
+		log.Printf("Rolled back %d migrations", len(rolledBack))
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		printMigrationStatus(statuses)
+	}
+
+	return nil
+}
+
+func printMigrationStatus(statuses []migration.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		state, appliedAt := "pending", ""
+		if status.Applied {
+			state, appliedAt = "applied", status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	w.Flush()
+}
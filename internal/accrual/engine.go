@@ -0,0 +1,130 @@
+package accrual
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/vamshi1997/pismo-assessment/internal/logging"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+	"github.com/vamshi1997/pismo-assessment/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultPollInterval = time.Hour
+	defaultBatchSize    = 100
+
+	// workerName tags the logs and spans of the engine
+	workerName = "accrual_engine"
+)
+
+// Engine posts interest and late fees on overdue debt in the background. Charges are recorded per
+// accrual day, so running the engine again for a day, or on several instances, never charges twice.
+type Engine struct {
+	repo         repo.IRepository
+	pollInterval time.Duration
+	batchSize    int
+	now          func() time.Time
+}
+
+// NewEngine creates an engine accruing the charges of the current day every pollInterval, going through
+// the overdue debts batchSize at a time. Zero values fall back to an hourly interval and batches of 100
+// debts.
+func NewEngine(repo repo.IRepository, pollInterval time.Duration, batchSize int) *Engine {
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	return &Engine{
+		repo:         repo,
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+		now:          time.Now,
+	}
+}
+
+// Run accrues the charges of the current day until stop is closed
+func (e *Engine) Run(stop <-chan struct{}) {
+	ctx := logging.WithLogger(context.Background(), slog.With("worker", workerName))
+	ticker := time.NewTicker(e.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		_, _ = e.RunOnce(ctx, e.now())
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce posts the charges due on the accrual day on every overdue debt and returns how many charges
+// were posted. A debt which can not be charged is skipped, so it does not hold back the others, and the
+// first error is returned once all debts were visited.
+func (e *Engine) RunOnce(ctx context.Context, accrualDay time.Time) (int, error) {
+	accrualDay = model.AccrualDay(accrualDay)
+
+	ctx, span := tracing.Tracer().Start(ctx, workerName+".run_once",
+		trace.WithAttributes(attribute.String("accrual_date", accrualDay.Format(model.AccrualDateLayout))))
+	defer span.End()
+
+	rates, err := e.repo.ListAccrualRates(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("error while fetching accrual rates", "error", err)
+		tracing.RecordError(span, err)
+		return 0, err
+	}
+
+	posted := 0
+	var firstErr error
+
+	for _, rate := range rates {
+		if !rate.Accrues() {
+			continue
+		}
+
+		var afterID uint
+		for {
+			ids, err := e.repo.ListOverdueDebts(ctx, rate, accrualDay, afterID, e.batchSize)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				break
+			}
+
+			for _, id := range ids {
+				charges, err := e.repo.AccrueDebt(ctx, id, rate, accrualDay)
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+					continue
+				}
+				posted += len(charges)
+			}
+
+			if len(ids) < e.batchSize {
+				break
+			}
+			afterID = ids[len(ids)-1]
+		}
+	}
+
+	if posted > 0 {
+		logging.FromContext(ctx).Info("accrual charges posted", "count", posted, "accrual_date", accrualDay.Format(model.AccrualDateLayout))
+	}
+	span.SetAttributes(attribute.Int("posted", posted))
+	if firstErr != nil {
+		tracing.RecordError(span, firstErr)
+	}
+	return posted, firstErr
+}
@@ -0,0 +1,80 @@
+package accrual
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo/mock"
+)
+
+func TestEngine_RunOnce(t *testing.T) {
+	accrualDay := time.Date(2025, 2, 10, 0, 0, 0, 0, model.IST)
+	purchaseRate := model.AccrualRate{OperationTypeID: model.NormalPurchase, InterestRateBps: 2400, LateFee: model.MustParseMoney("10")}
+	withdrawalRate := model.AccrualRate{OperationTypeID: model.Withdrawal, InterestRateBps: 3600, GraceDays: 3}
+
+	tests := []struct {
+		name           string
+		mockBehavior   func(m *mock.MockIRepository)
+		expectedPosted int
+		expectedErr    error
+	}{
+		{
+			name: "Charges Overdue Debts Of Every Rate",
+			mockBehavior: func(m *mock.MockIRepository) {
+				gomock.InOrder(
+					m.EXPECT().ListAccrualRates(gomock.Any()).Return([]model.AccrualRate{
+						purchaseRate,
+						{OperationTypeID: model.PurchaseInstallments},
+						withdrawalRate,
+					}, nil),
+					m.EXPECT().ListOverdueDebts(gomock.Any(), purchaseRate, accrualDay, uint(0), 2).Return([]uint{1, 4}, nil),
+					m.EXPECT().AccrueDebt(gomock.Any(), uint(1), purchaseRate, accrualDay).Return([]model.Transaction{{ID: 10}, {ID: 11}}, nil),
+					m.EXPECT().AccrueDebt(gomock.Any(), uint(4), purchaseRate, accrualDay).Return(nil, nil),
+					m.EXPECT().ListOverdueDebts(gomock.Any(), purchaseRate, accrualDay, uint(4), 2).Return(nil, nil),
+					m.EXPECT().ListOverdueDebts(gomock.Any(), withdrawalRate, accrualDay, uint(0), 2).Return([]uint{7}, nil),
+					m.EXPECT().AccrueDebt(gomock.Any(), uint(7), withdrawalRate, accrualDay).Return([]model.Transaction{{ID: 12}}, nil),
+				)
+			},
+			expectedPosted: 3,
+		},
+		{
+			name: "Failing Debt Does Not Stop The Others",
+			mockBehavior: func(m *mock.MockIRepository) {
+				gomock.InOrder(
+					m.EXPECT().ListAccrualRates(gomock.Any()).Return([]model.AccrualRate{purchaseRate}, nil),
+					m.EXPECT().ListOverdueDebts(gomock.Any(), purchaseRate, accrualDay, uint(0), 2).Return([]uint{1}, nil),
+					m.EXPECT().AccrueDebt(gomock.Any(), uint(1), purchaseRate, accrualDay).Return(nil, errors.New("lock wait timeout")),
+				)
+			},
+			expectedErr: errors.New("lock wait timeout"),
+		},
+		{
+			name: "Fetching Rates Fails",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().ListAccrualRates(gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			expectedErr: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+
+			// any time of the accrual day accrues for the day itself
+			posted, err := NewEngine(mockRepo, time.Minute, 2).RunOnce(context.Background(), accrualDay.Add(15*time.Hour))
+
+			assert.Equal(t, tt.expectedPosted, posted)
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
@@ -1,24 +1,54 @@
 package boot
 
 import (
+	"context"
 	"fmt"
+	"github.com/vamshi1997/pismo-assessment/internal/metrics"
+	"github.com/vamshi1997/pismo-assessment/internal/migration"
 	"github.com/vamshi1997/pismo-assessment/internal/model"
-	"log"
+	"github.com/vamshi1997/pismo-assessment/internal/tracing"
+	"log/slog"
+	"os"
 
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
 	db  *gorm.DB
 	err error
+
+	shutdownTracing = func(context.Context) error { return nil }
 )
 
 func InitApp() {
 	InitConfig()
+	InitTracing()
 	InitDb()
 }
 
+// InitTracing installs the tracer provider exporting spans as configured. The stdout exporter writes to
+// standard error like the logs, since standard output carries the output of commands such as export.
+func InitTracing() {
+	tracingCfg := GetConfig().AppConfig.Tracing
+	shutdownTracing, err = tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    tracingCfg.Exporter,
+		Endpoint:    tracingCfg.Endpoint,
+		Insecure:    tracingCfg.Insecure,
+		ServiceName: tracingCfg.ServiceName,
+		SampleRatio: tracingCfg.SampleRatio,
+	}, os.Stderr)
+	if err != nil {
+		slog.Error("not able to set up tracing", "exporter", tracingCfg.Exporter, "error", err)
+		panic(err)
+	}
+}
+
+// ShutdownTracing exports the spans which are still buffered and stops the exporter
+func ShutdownTracing(ctx context.Context) error {
+	return shutdownTracing(ctx)
+}
+
 func GetDB() *gorm.DB {
 	return db
 }
@@ -26,27 +56,112 @@ func GetDB() *gorm.DB {
 func InitDb() {
 	cfg = GetConfig()
 
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=True&loc=Local",
-		cfg.AppConfig.DB.Username,
-		cfg.AppConfig.DB.Password,
-		cfg.AppConfig.DB.Host,
-		cfg.AppConfig.DB.Port,
-		cfg.AppConfig.DB.DBName,
-		cfg.AppConfig.DB.Charset,
-	)
+	if _, err = ConnectDb(); err != nil {
+		panic(err)
+	}
+
+	if cfg.AppConfig.Migrations.AutoApply {
+		err = Migrate(db)
+		if err != nil {
+			panic(err)
+		}
+		return
+	}
 
-	db, err = gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	if cfg.AppConfig.Migrations.RequireCurrent {
+		err = RequireCurrentSchema(db)
+		if err != nil {
+			slog.Error("refusing to start, run the pending migrations with: main migrate up", "error", err)
+			panic(err)
+		}
+	}
+}
+
+// ConnectDb connects to the configured database and registers the query spans and metrics, without
+// migrating it. The connection is also returned by GetDB.
+func ConnectDb() (*gorm.DB, error) {
+	cfg = GetConfig()
+
+	db, err = OpenDB(cfg.AppConfig.DB)
 	if err != nil {
-		log.Println("Not able connect to database")
-		panic(err)
+		slog.Error("not able to connect to database", "driver", cfg.AppConfig.DB.Driver, "error", err)
+		return nil, err
+	}
+	slog.Info("application connected to database", "driver", db.Dialector.Name())
+
+	if err = db.Use(tracing.GormPlugin{}); err != nil {
+		slog.Error("not able to register the query spans", "error", err)
+		return nil, err
+	}
+	if cfg.AppConfig.Metrics.Enabled {
+		if err = db.Use(metrics.GormPlugin{}); err != nil {
+			slog.Error("not able to register the query metrics", "error", err)
+			return nil, err
+		}
+	}
+
+	return db, nil
+}
+
+// Migrate upgrades a database created without migrations, applies every pending migration and seeds
+// the default operation types
+func Migrate(db *gorm.DB) error {
+	if err := UpgradeLegacySchema(db); err != nil {
+		return err
 	}
-	log.Println("Application connected to database successfully ...")
 
-	err := db.AutoMigrate(&model.Account{}, &model.Transaction{})
+	migrator, err := migration.New(db)
 	if err != nil {
-		log.Println("Not able migrate account or transaction table")
-		panic(err)
+		slog.Error("not able to load migrations", "error", err)
+		return err
+	}
+
+	_, err = migrator.Up(0)
+	if err != nil {
+		slog.Error("not able to migrate application tables", "error", err)
+		return err
 	}
-	log.Println("migrated account table successfully ...")
+	slog.Info("database schema is up to date")
 
+	return SeedOperationTypes(db)
+}
+
+// RequireCurrentSchema fails when the database has pending migrations. It only reads the applied
+// migrations, so it can be used by commands which must not change the schema.
+func RequireCurrentSchema(db *gorm.DB) error {
+	migrator, err := migration.New(db)
+	if err != nil {
+		return err
+	}
+
+	pending, err := migrator.Pending()
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("database schema is behind by %d migrations, the first pending one is %d_%s",
+			len(pending), pending[0].Version, pending[0].Name)
+	}
+
+	return nil
+}
+
+// SeedOperationTypes adds the default operation types which do not exist yet. Existing rows are left
+// untouched, so changes made to them in the database are kept.
+func SeedOperationTypes(db *gorm.DB) error {
+	operationTypes := append([]model.OperationType(nil), model.DefaultOperationTypes...)
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&operationTypes).Error
+	if err != nil {
+		slog.Error("not able to seed operation types", "error", err)
+	}
+	return err
+}
+
+// CloseDB closes the connection pool of the database
+func CloseDB() error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
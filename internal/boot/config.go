@@ -1,52 +1,353 @@
 package boot
 
 import (
+	"errors"
 	"fmt"
 	"github.com/spf13/viper"
+	"github.com/vamshi1997/pismo-assessment/internal/logging"
+	"github.com/vamshi1997/pismo-assessment/internal/tracing"
+	"io"
 	"log"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
 )
 
 var (
-	cfg Config
+	cfg        Config
+	configFile string
 )
 
+// EnvPrefix is the prefix of the environment variables overriding the configuration. The variable of
+// a key is the key without the leading "app", upper cased with dots replaced by underscores, e.g.
+// PISMO_DB_HOST overrides app.db.host. PISMO_DB_PASSWORD_FILE reads app.db.password from a file.
+const EnvPrefix = "PISMO"
+
+// ConfigFileEnv chooses the config file when it is not given with SetConfigFile
+const ConfigFileEnv = EnvPrefix + "_CONFIG"
+
+// configSearchPaths are searched for default.toml when no config file is chosen
+var configSearchPaths = []string{"/app/configs", "configs"}
+
+// defaults are the values used for every key which is neither in the config file nor in the environment
+var defaults = map[string]interface{}{
+	"app.server.host":                "0.0.0.0",
+	"app.server.port":                8080,
+	"app.server.read_timeout":        "15s",
+	"app.server.read_header_timeout": "5s",
+	"app.server.write_timeout":       "60s",
+	"app.server.idle_timeout":        "120s",
+	"app.server.shutdown_timeout":    "30s",
+	"app.server.max_header_bytes":    1 << 20,
+	"app.server.max_body_bytes":      1 << 20,
+	"app.log.level":                  "info",
+	"app.log.format":                 logging.FormatJSON,
+	"app.db.driver":                  DriverMySQL,
+	"app.db.host":                    "localhost",
+	"app.db.username":                "",
+	"app.db.password":                "",
+	"app.db.dbname":                  "",
+	"app.db.port":                    3306,
+	"app.db.charset":                 "utf8mb4",
+	"app.db.sslmode":                 "disable",
+	"app.db.path":                    "",
+	"app.events.publisher":           "",
+	"app.events.file_path":           "",
+	"app.events.poll_interval":       "1s",
+	"app.events.batch_size":          100,
+	"app.webhooks.poll_interval":     "1s",
+	"app.webhooks.batch_size":        50,
+	"app.webhooks.timeout":           "10s",
+	"app.webhooks.max_attempts":      8,
+	"app.webhooks.base_backoff":      "10s",
+	"app.webhooks.max_backoff":       "1h",
+	"app.statements.poll_interval":   "1h",
+	"app.statements.batch_size":      100,
+	"app.accruals.poll_interval":     "1h",
+	"app.accruals.batch_size":        100,
+	"app.migrations.auto_apply":      true,
+	"app.migrations.require_current": true,
+	"app.metrics.enabled":            true,
+	"app.metrics.path":               "/metrics",
+	"app.tracing.exporter":           tracing.ExporterNone,
+	"app.tracing.endpoint":           "",
+	"app.tracing.insecure":           false,
+	"app.tracing.service_name":       "pismo",
+	"app.tracing.sample_ratio":       1.0,
+}
+
 type Config struct {
 	AppConfig App `mapstructure:"app"`
 }
 
 type App struct {
-	Server struct {
-		Host string
-		Port int
-	} `mapstructure:"server"`
-	DB struct {
-		Host     string `mapstructure:"host"`
-		Username string `mapstructure:"username"`
-		Password string `mapstructure:"password"`
-		DBName   string `mapstructure:"dbname"`
-		Port     int    `mapstructure:"port"`
-		Charset  string `mapstructure:"charset"`
-	} `mapstructure:"db"`
+	Server ServerConfig `mapstructure:"server"`
+	Log    struct {
+		// Level is debug, info, warn or error and Format is json or text
+		Level  string `mapstructure:"level"`
+		Format string `mapstructure:"format"`
+	} `mapstructure:"log"`
+	DB     DBConfig `mapstructure:"db"`
+	Events struct {
+		// Publisher is "file" or "memory", when it is empty events are only delivered to webhooks
+		Publisher    string        `mapstructure:"publisher"`
+		FilePath     string        `mapstructure:"file_path"`
+		PollInterval time.Duration `mapstructure:"poll_interval"`
+		BatchSize    int           `mapstructure:"batch_size"`
+	} `mapstructure:"events"`
+	Webhooks struct {
+		PollInterval time.Duration `mapstructure:"poll_interval"`
+		BatchSize    int           `mapstructure:"batch_size"`
+		Timeout      time.Duration `mapstructure:"timeout"`
+		MaxAttempts  int           `mapstructure:"max_attempts"`
+		BaseBackoff  time.Duration `mapstructure:"base_backoff"`
+		MaxBackoff   time.Duration `mapstructure:"max_backoff"`
+	} `mapstructure:"webhooks"`
+	Statements struct {
+		PollInterval time.Duration `mapstructure:"poll_interval"`
+		BatchSize    int           `mapstructure:"batch_size"`
+	} `mapstructure:"statements"`
+	Accruals struct {
+		PollInterval time.Duration `mapstructure:"poll_interval"`
+		BatchSize    int           `mapstructure:"batch_size"`
+	} `mapstructure:"accruals"`
+	Migrations struct {
+		// AutoApply applies pending migrations at startup, otherwise they are applied with "main migrate up"
+		AutoApply bool `mapstructure:"auto_apply"`
+		// RequireCurrent refuses to start when migrations are pending and AutoApply is off
+		RequireCurrent bool `mapstructure:"require_current"`
+	} `mapstructure:"migrations"`
+	Metrics MetricsConfig `mapstructure:"metrics"`
+	Tracing TracingConfig `mapstructure:"tracing"`
+}
+
+// TracingConfig chooses where the OpenTelemetry spans are exported, see tracing.Config
+type TracingConfig struct {
+	// Exporter is "none", "stdout" or "otlp"
+	Exporter    string  `mapstructure:"exporter"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	Insecure    bool    `mapstructure:"insecure"`
+	ServiceName string  `mapstructure:"service_name"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+// MetricsConfig tells whether the Prometheus metrics are served, and on which path
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"`
+}
+
+// ServerConfig holds the HTTP server's address, timeouts and limits. ShutdownTimeout is how long a
+// shutdown waits for in-flight requests and background workers to finish.
+type ServerConfig struct {
+	Host              string        `mapstructure:"host"`
+	Port              int           `mapstructure:"port"`
+	ReadTimeout       time.Duration `mapstructure:"read_timeout"`
+	ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout"`
+	WriteTimeout      time.Duration `mapstructure:"write_timeout"`
+	IdleTimeout       time.Duration `mapstructure:"idle_timeout"`
+	ShutdownTimeout   time.Duration `mapstructure:"shutdown_timeout"`
+	MaxHeaderBytes    int           `mapstructure:"max_header_bytes"`
+	MaxBodyBytes      int64         `mapstructure:"max_body_bytes"`
+}
+
+// DBConfig selects the database driver and how to connect to it. Driver is "mysql" (the default),
+// "postgres" or "sqlite". Host, port, credentials and database name are used by mysql and postgres,
+// Charset only by mysql and SSLMode only by postgres. Path is the SQLite database file, or ":memory:".
+type DBConfig struct {
+	Driver   string `mapstructure:"driver"`
+	Host     string `mapstructure:"host"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	DBName   string `mapstructure:"dbname"`
+	Port     int    `mapstructure:"port"`
+	Charset  string `mapstructure:"charset"`
+	SSLMode  string `mapstructure:"sslmode"`
+	Path     string `mapstructure:"path"`
+}
+
+// SetConfigFile chooses the config file read by InitConfig, e.g. from a command line flag
+func SetConfigFile(path string) {
+	configFile = path
 }
 
+// InitConfig loads the configuration and stops the application when it is not valid
 func InitConfig() {
-	viper.SetConfigName("/configs/default") // name of config file (without extension)
-	viper.SetConfigType("toml")             // REQUIRED if the config file does not have the extension in the name
-	viper.AddConfigPath("/app")             // path to look for the config file in
-	err := viper.ReadInConfig()             // Find and read the config file
-	if err != nil {                         // Handle errors reading the config file
-		panic(fmt.Errorf("fatal error config file: %w", err))
+	path := configFile
+	if path == "" {
+		path = os.Getenv(ConfigFileEnv)
+	}
+
+	loaded, err := LoadConfig(path)
+	if err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
 	}
+	cfg = loaded
+
+	// logs go to standard error, standard output carries the output of commands such as export
+	logger, _ := logging.New(os.Stderr, cfg.AppConfig.Log.Format, cfg.AppConfig.Log.Level)
+	// the log package writes through the same logger, at info level
+	slog.SetDefault(logger)
 
 	log.Println("Configs are loaded successfully ...")
+}
 
-	err = viper.Unmarshal(&cfg)
-	if err != nil {
-		log.Printf("Error unmarshaling config: %s\n", err)
-		return
+// LoadConfig layers the configuration: the defaults, then the config file, then the environment
+// variables. When path is empty default.toml is looked up in configSearchPaths and it is fine for it
+// not to exist. The configuration is validated and every problem found is returned at once.
+func LoadConfig(path string) (Config, error) {
+	v := viper.New()
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
+
+	if path != "" {
+		v.SetConfigFile(path)
+	} else {
+		v.SetConfigName("default")
+		v.SetConfigType("toml")
+		for _, searchPath := range configSearchPaths {
+			v.AddConfigPath(searchPath)
+		}
+	}
+
+	err := v.ReadInConfig()
+	var notFound viper.ConfigFileNotFoundError
+	if err != nil && (path != "" || !errors.As(err, &notFound)) {
+		return Config{}, fmt.Errorf("error reading config file: %w", err)
+	}
+	if err == nil {
+		log.Printf("Reading configs from %s ...", v.ConfigFileUsed())
+	}
+
+	if err := applyEnv(v); err != nil {
+		return Config{}, err
+	}
+
+	var loaded Config
+	if err := v.Unmarshal(&loaded); err != nil {
+		return Config{}, fmt.Errorf("error mapping config: %w", err)
+	}
+
+	return loaded, loaded.Validate()
+}
+
+// EnvName returns the environment variable overriding a key, e.g. PISMO_DB_HOST for app.db.host
+func EnvName(key string) string {
+	key = strings.TrimPrefix(key, "app.")
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// applyEnv sets every key which has an environment variable, or a secret file named by the variable
+// with a _FILE suffix. Secret files have their trailing line break removed.
+func applyEnv(v *viper.Viper) error {
+	keys := v.AllKeys()
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		name := EnvName(key)
+		value, hasValue := os.LookupEnv(name)
+		file, hasFile := os.LookupEnv(name + "_FILE")
+
+		switch {
+		case hasValue && hasFile:
+			errs = append(errs, fmt.Errorf("%s and %s_FILE are both set, only one of them can be", name, name))
+		case hasValue:
+			v.Set(key, value)
+		case hasFile:
+			content, err := os.ReadFile(file)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s_FILE: %w", name, err))
+				continue
+			}
+			v.Set(key, strings.TrimRight(string(content), "\r\n"))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid setting of the configuration
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	positive := func(key string, value interface{}) {
+		switch v := value.(type) {
+		case int:
+			check(v > 0, "%s should be positive, got %d", key, v)
+		case int64:
+			check(v > 0, "%s should be positive, got %d", key, v)
+		case time.Duration:
+			check(v > 0, "%s should be positive, got %s", key, v)
+		}
+	}
+
+	app := c.AppConfig
+	check(app.Server.Port > 0 && app.Server.Port <= 65535, "app.server.port should be between 1 and 65535, got %d", app.Server.Port)
+	positive("app.server.read_timeout", app.Server.ReadTimeout)
+	positive("app.server.read_header_timeout", app.Server.ReadHeaderTimeout)
+	positive("app.server.write_timeout", app.Server.WriteTimeout)
+	positive("app.server.idle_timeout", app.Server.IdleTimeout)
+	positive("app.server.shutdown_timeout", app.Server.ShutdownTimeout)
+	positive("app.server.max_header_bytes", app.Server.MaxHeaderBytes)
+	positive("app.server.max_body_bytes", app.Server.MaxBodyBytes)
+
+	_, err := logging.New(io.Discard, app.Log.Format, app.Log.Level)
+	check(err == nil, "app.log: %v", err)
+
+	switch app.DB.Driver {
+	case DriverMySQL, DriverPostgres:
+		check(app.DB.Host != "", "app.db.host is required for %s", app.DB.Driver)
+		check(app.DB.Port > 0 && app.DB.Port <= 65535, "app.db.port should be between 1 and 65535, got %d", app.DB.Port)
+		check(app.DB.Username != "", "app.db.username is required for %s", app.DB.Driver)
+		check(app.DB.DBName != "", "app.db.dbname is required for %s", app.DB.Driver)
+	case DriverSQLite:
+		check(app.DB.Path != "", "app.db.path is required for sqlite")
+	default:
+		check(false, "app.db.driver should be mysql, postgres or sqlite, got %q", app.DB.Driver)
+	}
+
+	switch app.Events.Publisher {
+	case "", "memory":
+	case "file":
+		check(app.Events.FilePath != "", "app.events.file_path is required for the file publisher")
+	default:
+		check(false, "app.events.publisher should be file, memory or empty, got %q", app.Events.Publisher)
+	}
+	positive("app.events.poll_interval", app.Events.PollInterval)
+	positive("app.events.batch_size", app.Events.BatchSize)
+
+	positive("app.webhooks.poll_interval", app.Webhooks.PollInterval)
+	positive("app.webhooks.batch_size", app.Webhooks.BatchSize)
+	positive("app.webhooks.timeout", app.Webhooks.Timeout)
+	positive("app.webhooks.max_attempts", app.Webhooks.MaxAttempts)
+	positive("app.webhooks.base_backoff", app.Webhooks.BaseBackoff)
+	check(app.Webhooks.MaxBackoff >= app.Webhooks.BaseBackoff, "app.webhooks.max_backoff should not be less than app.webhooks.base_backoff")
+
+	positive("app.statements.poll_interval", app.Statements.PollInterval)
+	positive("app.statements.batch_size", app.Statements.BatchSize)
+	positive("app.accruals.poll_interval", app.Accruals.PollInterval)
+	positive("app.accruals.batch_size", app.Accruals.BatchSize)
+
+	if app.Metrics.Enabled {
+		check(strings.HasPrefix(app.Metrics.Path, "/"), "app.metrics.path should start with /, got %q", app.Metrics.Path)
+	}
+
+	switch app.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP:
+	default:
+		check(false, "app.tracing.exporter should be none, stdout or otlp, got %q", app.Tracing.Exporter)
 	}
+	check(app.Tracing.SampleRatio >= 0 && app.Tracing.SampleRatio <= 1, "app.tracing.sample_ratio should be between 0 and 1, got %v", app.Tracing.SampleRatio)
 
-	log.Println("Configs are mapped properly ...")
+	return errors.Join(errs...)
 }
 
 func GetConfig() Config {
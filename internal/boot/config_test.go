@@ -0,0 +1,154 @@
+package boot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadConfig_DefaultFile(t *testing.T) {
+	loaded, err := LoadConfig("../../configs/default.toml")
+	require.NoError(t, err)
+
+	assert.Equal(t, DriverMySQL, loaded.AppConfig.DB.Driver)
+	assert.Equal(t, "mysql", loaded.AppConfig.DB.Host)
+	assert.Equal(t, 8080, loaded.AppConfig.Server.Port)
+	assert.Equal(t, time.Hour, loaded.AppConfig.Webhooks.MaxBackoff)
+	assert.True(t, loaded.AppConfig.Migrations.AutoApply)
+	assert.Equal(t, "info", loaded.AppConfig.Log.Level)
+	assert.Equal(t, "json", loaded.AppConfig.Log.Format)
+	assert.Equal(t, MetricsConfig{Enabled: true, Path: "/metrics"}, loaded.AppConfig.Metrics)
+	assert.Equal(t, TracingConfig{Exporter: "none", ServiceName: "pismo", SampleRatio: 1}, loaded.AppConfig.Tracing)
+}
+
+func TestLoadConfig_Layers(t *testing.T) {
+	path := writeFile(t, "pismo.toml", `
+[app.db]
+  driver   = "postgres"
+  host     = "db.internal"
+  username = "pismo"
+  dbname   = "pismo"
+  port     = 5432
+[app.webhooks]
+  max_attempts = 3
+`)
+	secret := writeFile(t, "db_password", "s3cret\n")
+
+	t.Setenv("PISMO_DB_HOST", "db.override")
+	t.Setenv("PISMO_DB_PASSWORD_FILE", secret)
+	t.Setenv("PISMO_SERVER_PORT", "9090")
+	t.Setenv("PISMO_STATEMENTS_POLL_INTERVAL", "15m")
+	t.Setenv("PISMO_MIGRATIONS_AUTO_APPLY", "false")
+
+	loaded, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	app := loaded.AppConfig
+	// from the environment
+	assert.Equal(t, "db.override", app.DB.Host)
+	assert.Equal(t, "s3cret", app.DB.Password)
+	assert.Equal(t, 9090, app.Server.Port)
+	assert.Equal(t, 15*time.Minute, app.Statements.PollInterval)
+	assert.False(t, app.Migrations.AutoApply)
+	// from the file
+	assert.Equal(t, DriverPostgres, app.DB.Driver)
+	assert.Equal(t, 5432, app.DB.Port)
+	assert.Equal(t, 3, app.Webhooks.MaxAttempts)
+	// from the defaults
+	assert.Equal(t, "0.0.0.0", app.Server.Host)
+	assert.Equal(t, 30*time.Second, app.Server.ShutdownTimeout)
+	assert.Equal(t, int64(1<<20), app.Server.MaxBodyBytes)
+	assert.Equal(t, "disable", app.DB.SSLMode)
+	assert.Equal(t, 50, app.Webhooks.BatchSize)
+	assert.True(t, app.Migrations.RequireCurrent)
+}
+
+func TestLoadConfig_NoFile(t *testing.T) {
+	t.Setenv("PISMO_DB_DRIVER", "sqlite")
+	t.Setenv("PISMO_DB_PATH", ":memory:")
+
+	loaded, err := LoadConfig("")
+	require.NoError(t, err)
+	assert.Equal(t, DriverSQLite, loaded.AppConfig.DB.Driver)
+	assert.Equal(t, 100, loaded.AppConfig.Accruals.BatchSize)
+}
+
+func TestLoadConfig_Errors(t *testing.T) {
+	_, err := LoadConfig(filepath.Join(t.TempDir(), "missing.toml"))
+	assert.ErrorContains(t, err, "error reading config file")
+
+	path := writeFile(t, "pismo.toml", `
+[app.db]
+  driver = "sqlite"
+  path   = "pismo.db"
+`)
+	t.Setenv("PISMO_DB_PASSWORD", "secret")
+	t.Setenv("PISMO_DB_PASSWORD_FILE", "/run/secrets/db_password")
+	t.Setenv("PISMO_DB_USERNAME_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	_, err = LoadConfig(path)
+	assert.ErrorContains(t, err, "PISMO_DB_PASSWORD and PISMO_DB_PASSWORD_FILE are both set, only one of them can be")
+	assert.ErrorContains(t, err, "PISMO_DB_USERNAME_FILE: open")
+}
+
+func TestConfig_Validate(t *testing.T) {
+	path := writeFile(t, "pismo.toml", `
+[app.server]
+  port = 70000
+[app.db]
+  driver = "mysql"
+  host   = ""
+[app.events]
+  publisher  = "file"
+  batch_size = 0
+[app.webhooks]
+  base_backoff = "1h"
+  max_backoff  = "1m"
+[app.metrics]
+  path = "metrics"
+[app.tracing]
+  sample_ratio = 1.5
+`)
+
+	_, err := LoadConfig(path)
+	require.Error(t, err)
+
+	expected := []string{
+		"app.server.port should be between 1 and 65535, got 70000",
+		"app.db.host is required for mysql",
+		"app.db.username is required for mysql",
+		"app.db.dbname is required for mysql",
+		"app.events.file_path is required for the file publisher",
+		"app.events.batch_size should be positive, got 0",
+		"app.webhooks.max_backoff should not be less than app.webhooks.base_backoff",
+		`app.metrics.path should start with /, got "metrics"`,
+		"app.tracing.sample_ratio should be between 0 and 1, got 1.5",
+	}
+	assert.Equal(t, expected, strings.Split(err.Error(), "\n"))
+
+	t.Setenv("PISMO_DB_DRIVER", "oracle")
+	t.Setenv("PISMO_EVENTS_PUBLISHER", "kafka")
+	t.Setenv("PISMO_LOG_LEVEL", "verbose")
+	t.Setenv("PISMO_TRACING_EXPORTER", "jaeger")
+	_, err = LoadConfig(path)
+	assert.ErrorContains(t, err, `app.db.driver should be mysql, postgres or sqlite, got "oracle"`)
+	assert.ErrorContains(t, err, `app.events.publisher should be file, memory or empty, got "kafka"`)
+	assert.ErrorContains(t, err, `app.log: unknown log level "verbose"`)
+	assert.ErrorContains(t, err, `app.tracing.exporter should be none, stdout or otlp, got "jaeger"`)
+}
+
+func TestEnvName(t *testing.T) {
+	assert.Equal(t, "PISMO_DB_HOST", EnvName("app.db.host"))
+	assert.Equal(t, "PISMO_WEBHOOKS_MAX_BACKOFF", EnvName("app.webhooks.max_backoff"))
+}
@@ -0,0 +1,193 @@
+package boot
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Supported database drivers
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// DSN builds the connection string of the configured driver
+func DSN(cfg DBConfig) (string, error) {
+	switch driverName(cfg) {
+	case DriverMySQL:
+		return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=True&loc=Local",
+			cfg.Username,
+			cfg.Password,
+			cfg.Host,
+			cfg.Port,
+			cfg.DBName,
+			cfg.Charset,
+		), nil
+	case DriverPostgres:
+		sslMode := cfg.SSLMode
+		if sslMode == "" {
+			sslMode = "disable"
+		}
+		return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s",
+			cfg.Host,
+			cfg.Username,
+			cfg.Password,
+			cfg.DBName,
+			cfg.Port,
+			sslMode,
+		), nil
+	case DriverSQLite:
+		if cfg.Path == "" {
+			return "", fmt.Errorf("sqlite database path is not configured")
+		}
+		// writers wait for each other instead of failing with SQLITE_BUSY, and foreign keys are enforced
+		pragmas := url.Values{}
+		pragmas.Add("_pragma", "busy_timeout(5000)")
+		pragmas.Add("_pragma", "foreign_keys(1)")
+		return cfg.Path + "?" + pragmas.Encode(), nil
+	default:
+		return "", fmt.Errorf("unsupported database driver %q", cfg.Driver)
+	}
+}
+
+// Dialector returns the gorm dialector of the configured driver
+func Dialector(cfg DBConfig) (gorm.Dialector, error) {
+	dsn, err := DSN(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch driverName(cfg) {
+	case DriverPostgres:
+		return postgres.Open(dsn), nil
+	case DriverSQLite:
+		conn, err := sql.Open(sqlite.DriverName, dsn)
+		if err != nil {
+			return nil, err
+		}
+		// SQLite allows a single writer, and every connection to ":memory:" would be a new database
+		conn.SetMaxOpenConns(1)
+		return sqlite.Dialector{Conn: &utcConnPool{db: conn}}, nil
+	default:
+		return mysql.Open(dsn), nil
+	}
+}
+
+// OpenDB connects to the configured database. Unique and foreign key violations are translated to
+// gorm.ErrDuplicatedKey and gorm.ErrForeignKeyViolated, whose messages do not carry the offending values,
+// e.g. a document number, the way the errors of the databases do, and queries are logged without them.
+func OpenDB(cfg DBConfig) (*gorm.DB, error) {
+	dialector, err := Dialector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return gorm.Open(dialector, &gorm.Config{TranslateError: true, Logger: newQueryLogger(os.Stderr)})
+}
+
+// newQueryLogger returns gorm's default logger for failed and slow queries, except that it writes the
+// SQL without its values, which may be personal data
+func newQueryLogger(out io.Writer) logger.Interface {
+	return logger.New(log.New(out, "\r\n", log.LstdFlags), logger.Config{
+		SlowThreshold:        200 * time.Millisecond,
+		LogLevel:             logger.Warn,
+		ParameterizedQueries: true,
+	})
+}
+
+func driverName(cfg DBConfig) string {
+	if cfg.Driver == "" {
+		return DriverMySQL
+	}
+	return cfg.Driver
+}
+
+// utcConnPool passes every time argument to SQLite in UTC. SQLite keeps times as text and compares them
+// as text, so the times written and the bounds they are compared with have to be in the same zone.
+type utcConnPool struct {
+	db *sql.DB
+}
+
+func (p *utcConnPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.db.PrepareContext(ctx, query)
+}
+
+func (p *utcConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return p.db.ExecContext(ctx, query, utcArgs(args)...)
+}
+
+func (p *utcConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.db.QueryContext(ctx, query, utcArgs(args)...)
+}
+
+func (p *utcConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.db.QueryRowContext(ctx, query, utcArgs(args)...)
+}
+
+func (p *utcConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	tx, err := p.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &utcTx{tx: tx}, nil
+}
+
+func (p *utcConnPool) GetDBConn() (*sql.DB, error) {
+	return p.db, nil
+}
+
+// utcTx is the transaction counterpart of utcConnPool
+type utcTx struct {
+	tx *sql.Tx
+}
+
+func (t *utcTx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return t.tx.PrepareContext(ctx, query)
+}
+
+func (t *utcTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return t.tx.ExecContext(ctx, query, utcArgs(args)...)
+}
+
+func (t *utcTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return t.tx.QueryContext(ctx, query, utcArgs(args)...)
+}
+
+func (t *utcTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return t.tx.QueryRowContext(ctx, query, utcArgs(args)...)
+}
+
+func (t *utcTx) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t *utcTx) Rollback() error {
+	return t.tx.Rollback()
+}
+
+func utcArgs(args []interface{}) []interface{} {
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case time.Time:
+			args[i] = v.UTC()
+		case *time.Time:
+			if v != nil {
+				utc := v.UTC()
+				args[i] = &utc
+			}
+		}
+	}
+	return args
+}
@@ -0,0 +1,78 @@
+package boot
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestDSN(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         DBConfig
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "MySQL Is The Default",
+			cfg:      DBConfig{Host: "mysql", Username: "user", Password: "secret", DBName: "pismo", Port: 3306, Charset: "utf8mb4"},
+			expected: "user:secret@tcp(mysql:3306)/pismo?charset=utf8mb4&parseTime=True&loc=Local",
+		},
+		{
+			name:     "Postgres",
+			cfg:      DBConfig{Driver: DriverPostgres, Host: "postgres", Username: "user", Password: "secret", DBName: "pismo", Port: 5432},
+			expected: "host=postgres user=user password=secret dbname=pismo port=5432 sslmode=disable",
+		},
+		{
+			name:     "Postgres With SSL Mode",
+			cfg:      DBConfig{Driver: DriverPostgres, Host: "postgres", Username: "user", Password: "secret", DBName: "pismo", Port: 5432, SSLMode: "require"},
+			expected: "host=postgres user=user password=secret dbname=pismo port=5432 sslmode=require",
+		},
+		{
+			name:     "SQLite",
+			cfg:      DBConfig{Driver: DriverSQLite, Path: "pismo.db"},
+			expected: "pismo.db?_pragma=busy_timeout%285000%29&_pragma=foreign_keys%281%29",
+		},
+		{
+			name:        "SQLite Without Path",
+			cfg:         DBConfig{Driver: DriverSQLite},
+			expectedErr: "sqlite database path is not configured",
+		},
+		{
+			name:        "Unsupported Driver",
+			cfg:         DBConfig{Driver: "oracle"},
+			expectedErr: `unsupported database driver "oracle"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dsn, err := DSN(tt.cfg)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, dsn)
+		})
+	}
+}
+
+func TestOpenDB_DoesNotLeakValues(t *testing.T) {
+	var out bytes.Buffer
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true, Logger: newQueryLogger(&out)})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec("CREATE TABLE accounts (id integer PRIMARY KEY, document_number varchar(255) NOT NULL UNIQUE)").Error)
+
+	require.NoError(t, db.Exec("INSERT INTO accounts (document_number) VALUES (?)", "52998224725").Error)
+	err = db.Exec("INSERT INTO accounts (document_number) VALUES (?)", "52998224725").Error
+
+	assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+	assert.NotContains(t, err.Error(), "52998224725")
+	assert.Contains(t, out.String(), "INSERT INTO accounts (document_number) VALUES (?)")
+	assert.NotContains(t, out.String(), "52998224725")
+}
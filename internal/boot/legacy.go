@@ -0,0 +1,165 @@
+package boot
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/vamshi1997/pismo-assessment/internal/migration"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// legacyColumn is a column which releases relying on AutoMigrate added to a table after creating it.
+// Definition is set when the model's own definition cannot be added to a table holding rows.
+type legacyColumn struct {
+	model      interface{}
+	name       string
+	definition string
+}
+
+// legacyColumns brings the tables of those releases to the schema of the first migration. It is frozen:
+// columns added by later migrations must not be listed here.
+var legacyColumns = []legacyColumn{
+	{model: &model.Account{}, name: "document_type"},
+	{model: &model.Account{}, name: "status"},
+	{model: &model.Account{}, name: "billing_day"},
+	{model: &model.Account{}, name: "credit_limit"},
+	{model: &model.Transaction{}, name: "reversed_transaction_id"},
+	{model: &model.Transaction{}, name: "installments"},
+	{model: &model.Transaction{}, name: "installment_number"},
+	{model: &model.Transaction{}, name: "parent_transaction_id"},
+	{model: &model.Transaction{}, name: "due_date"},
+	{model: &model.Transaction{}, name: "accrued_transaction_id"},
+	{model: &model.IdempotencyKey{}, name: "response_body"},
+	{model: &model.Statement{}, name: "interest", definition: "decimal(19,2) NOT NULL DEFAULT 0"},
+	{model: &model.Statement{}, name: "fees", definition: "decimal(19,2) NOT NULL DEFAULT 0"},
+}
+
+// UpgradeLegacySchema brings a database created by a release relying on AutoMigrate, i.e. one with
+// application tables but no applied migration, to the schema of the first migration. The first
+// migration only creates what does not exist yet, so without this step it would be recorded as applied
+// on tables missing its columns. Databases managed by migrations and empty ones are left untouched.
+func UpgradeLegacySchema(db *gorm.DB) error {
+	legacy, err := isLegacySchema(db)
+	if err != nil || !legacy {
+		return err
+	}
+	slog.Warn("database was created without migrations, upgrading it before adopting it")
+
+	if err := migrateMoneyColumns(db); err != nil {
+		slog.Error("not able to migrate money columns of transaction table", "error", err)
+		return err
+	}
+
+	for _, column := range legacyColumns {
+		if !db.Migrator().HasTable(column.model) || db.Migrator().HasColumn(column.model, column.name) {
+			continue
+		}
+
+		slog.Info("adding legacy column", "column", column.name)
+		if err := addColumn(db, column); err != nil {
+			slog.Error("not able to add legacy column", "column", column.name, "error", err)
+			return err
+		}
+	}
+
+	// MySQL declares the indexes within CREATE TABLE, so the first migration does not add the ones of
+	// the columns above to existing tables
+	for _, value := range []interface{}{&model.Account{}, &model.Transaction{}, &model.IdempotencyKey{}, &model.Statement{}} {
+		if err := createMissingIndexes(db, value); err != nil {
+			slog.Error("not able to create legacy indexes", "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isLegacySchema tells if the database has application tables but no applied migration. An empty
+// schema_migrations table does not count as applied migrations: it is left behind by an upgrade which
+// failed before its first migration, or by an older migrate command which created it on its own.
+func isLegacySchema(db *gorm.DB) (bool, error) {
+	if !db.Migrator().HasTable(&model.Transaction{}) {
+		return false, nil
+	}
+	if !db.Migrator().HasTable(&migration.SchemaMigration{}) {
+		return true, nil
+	}
+
+	var applied int64
+	if err := db.Model(&migration.SchemaMigration{}).Count(&applied).Error; err != nil {
+		return false, err
+	}
+	return applied == 0, nil
+}
+
+func addColumn(db *gorm.DB, column legacyColumn) error {
+	if column.definition == "" {
+		return db.Migrator().AddColumn(column.model, column.name)
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(column.model); err != nil {
+		return err
+	}
+	return db.Exec(fmt.Sprintf("ALTER TABLE ? ADD COLUMN ? %s", column.definition),
+		clause.Table{Name: stmt.Schema.Table}, clause.Column{Name: column.name}).Error
+}
+
+func createMissingIndexes(db *gorm.DB, value interface{}) error {
+	if !db.Migrator().HasTable(value) {
+		return nil
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(value); err != nil {
+		return err
+	}
+	for _, index := range stmt.Schema.ParseIndexes() {
+		if db.Migrator().HasIndex(value, index.Name) {
+			continue
+		}
+		if err := db.Migrator().CreateIndex(value, index.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateMoneyColumns converts amount and balance columns created as floating point by older versions
+// to DECIMAL. Existing values are first rounded to cents with the database's ROUND, which rounds half
+// away from zero just like model.Money, so no value changes again when the column type is altered.
+func migrateMoneyColumns(db *gorm.DB) error {
+	columnTypes, err := db.Migrator().ColumnTypes(&model.Transaction{})
+	if err != nil {
+		return err
+	}
+
+	for _, columnType := range columnTypes {
+		name := columnType.Name()
+		if name != "amount" && name != "balance" {
+			continue
+		}
+
+		dataType := strings.ToLower(columnType.DatabaseTypeName())
+		if dataType != "double" && dataType != "float" && dataType != "real" {
+			continue
+		}
+
+		slog.Info("migrating money column", "column", name, "from", dataType, "to", model.MoneyColumnType)
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(fmt.Sprintf("UPDATE transactions SET %s = ROUND(%s, %d)", name, name, model.MoneyScale)).Error; err != nil {
+				return err
+			}
+			return tx.Migrator().AlterColumn(&model.Transaction{}, name)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package boot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vamshi1997/pismo-assessment/internal/migration"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"gorm.io/gorm"
+)
+
+// legacySchema is what AutoMigrate created for the first release, with money stored as floating point
+const legacySchema = `
+CREATE TABLE accounts (id integer PRIMARY KEY AUTOINCREMENT, created_at datetime, updated_at datetime, deleted_at datetime, document_number varchar(255) NOT NULL);
+CREATE UNIQUE INDEX idx_accounts_document_number ON accounts (document_number);
+CREATE INDEX idx_accounts_deleted_at ON accounts (deleted_at);
+CREATE TABLE transactions (id integer PRIMARY KEY AUTOINCREMENT, created_at datetime, updated_at datetime, deleted_at datetime, account_id integer NOT NULL, amount real NOT NULL, balance real NOT NULL, operation_type_id integer NOT NULL, event_date timestamp(6) NOT NULL);
+CREATE INDEX idx_transactions_deleted_at ON transactions (deleted_at);
+INSERT INTO accounts (id, document_number) VALUES (1, '12345678900');
+INSERT INTO transactions (id, account_id, amount, balance, operation_type_id, event_date) VALUES (1, 1, -50.254, -30.1, 1, '2025-02-10 10:00:00.000000');
+`
+
+func newSQLiteDB(t *testing.T) *gorm.DB {
+	db, err := OpenDB(DBConfig{Driver: DriverSQLite, Path: ":memory:"})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func newLegacyDB(t *testing.T) *gorm.DB {
+	db := newSQLiteDB(t)
+	for _, statement := range strings.Split(strings.TrimSpace(legacySchema), ";\n") {
+		require.NoError(t, db.Exec(statement).Error)
+	}
+	return db
+}
+
+func TestMigrate_LegacySchema(t *testing.T) {
+	db := newLegacyDB(t)
+
+	require.NoError(t, Migrate(db))
+
+	var account model.Account
+	require.NoError(t, db.First(&account, 1).Error)
+	assert.Equal(t, model.AccountActive, account.Status)
+	assert.Equal(t, "CPF", account.DocumentType)
+	assert.Equal(t, uint(1), account.BillingDay)
+	assert.Nil(t, account.CreditLimit)
+
+	var transaction model.Transaction
+	require.NoError(t, db.First(&transaction, 1).Error)
+	assert.Equal(t, model.MustParseMoney("-50.25"), transaction.Amount)
+	assert.Equal(t, model.MustParseMoney("-30.10"), transaction.Balance)
+	assert.Nil(t, transaction.DueDate)
+
+	columnTypes, err := db.Migrator().ColumnTypes(&model.Transaction{})
+	require.NoError(t, err)
+	for _, columnType := range columnTypes {
+		if columnType.Name() == "amount" || columnType.Name() == "balance" {
+			assert.Equal(t, "decimal", columnType.DatabaseTypeName())
+		}
+	}
+	assert.True(t, db.Migrator().HasIndex(&model.Transaction{}, "idx_transactions_account_event_date"))
+	assert.True(t, db.Migrator().HasTable(&model.Statement{}))
+
+	migrator, err := migration.New(db)
+	require.NoError(t, err)
+	pending, err := migrator.Pending()
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	// once adopted, the database is left to the migrations
+	require.NoError(t, db.Exec("ALTER TABLE accounts DROP COLUMN billing_day").Error)
+	require.NoError(t, UpgradeLegacySchema(db))
+	assert.False(t, db.Migrator().HasColumn(&model.Account{}, "billing_day"))
+}
+
+func TestMigrate_LegacySchemaAfterStatus(t *testing.T) {
+	db := newLegacyDB(t)
+
+	// looking at the status, as main migrate status and require_current do, leaves the database alone
+	migrator, err := migration.New(db)
+	require.NoError(t, err)
+	statuses, err := migrator.Status()
+	require.NoError(t, err)
+	require.NotEmpty(t, statuses)
+	assert.False(t, statuses[0].Applied)
+	assert.False(t, db.Migrator().HasTable(&migration.SchemaMigration{}))
+
+	// the way main migrate up applies the migrations
+	require.NoError(t, UpgradeLegacySchema(db))
+	applied, err := migrator.Up(0)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(statuses))
+
+	assert.True(t, db.Migrator().HasColumn(&model.Account{}, "billing_day"))
+	var transaction model.Transaction
+	require.NoError(t, db.First(&transaction, 1).Error)
+	assert.Equal(t, model.MustParseMoney("-50.25"), transaction.Amount)
+}
+
+func TestMigrate_LegacySchemaWithEmptySchemaMigrations(t *testing.T) {
+	db := newLegacyDB(t)
+	require.NoError(t, db.Exec("CREATE TABLE schema_migrations (version bigint NOT NULL, name varchar(255) NOT NULL, applied_at timestamp NOT NULL, PRIMARY KEY (version))").Error)
+
+	require.NoError(t, Migrate(db))
+
+	var account model.Account
+	require.NoError(t, db.First(&account, 1).Error)
+	assert.Equal(t, model.AccountActive, account.Status)
+	assert.True(t, db.Migrator().HasColumn(&model.Transaction{}, "due_date"))
+}
+
+func TestUpgradeLegacySchema_EmptyDatabase(t *testing.T) {
+	db := newSQLiteDB(t)
+
+	require.NoError(t, UpgradeLegacySchema(db))
+	assert.False(t, db.Migrator().HasTable(&model.Transaction{}))
+}
+
+func TestRequireCurrentSchema(t *testing.T) {
+	db := newLegacyDB(t)
+
+	// the check main export and require_current run fails without changing the database
+	assert.ErrorContains(t, RequireCurrentSchema(db), "database schema is behind")
+	assert.False(t, db.Migrator().HasTable(&migration.SchemaMigration{}))
+	assert.False(t, db.Migrator().HasColumn(&model.Account{}, "billing_day"))
+
+	require.NoError(t, Migrate(db))
+	assert.NoError(t, RequireCurrentSchema(db))
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+	"gorm.io/gorm"
+)
+
+// accountStatusRequest is the body of a block, unblock or close request
+type accountStatusRequest struct {
+	ReasonCode string `json:"reason_code" binding:"required"`
+	ChangedBy  string `json:"changed_by" binding:"required,max=255"`
+}
+
+// BlockAccount method blocks an account, after which it only accepts credits
+func (c *Controller) BlockAccount(ctx *gin.Context) {
+	c.changeAccountStatus(ctx, model.AccountBlocked, "Account blocked successfully", "Not able to block account")
+}
+
+// UnblockAccount method makes a blocked account active again
+func (c *Controller) UnblockAccount(ctx *gin.Context) {
+	c.changeAccountStatus(ctx, model.AccountActive, "Account unblocked successfully", "Not able to unblock account")
+}
+
+// CloseAccount method closes an account which owes nothing. A closed account accepts no transaction.
+func (c *Controller) CloseAccount(ctx *gin.Context) {
+	c.changeAccountStatus(ctx, model.AccountClosed, "Account closed successfully", "Not able to close account")
+}
+
+// ListAccountStatusChanges method returns every status transition of an account, oldest first
+func (c *Controller) ListAccountStatusChanges(ctx *gin.Context) {
+	accountID, err := strconv.Atoi(ctx.Param("accountId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid accountId",
+			"msg":       "Not able to fetch status history",
+		})
+		return
+	}
+
+	accountInfo, err := c.repo.GetAccount(ctx.Request.Context(), uint(accountID))
+	if err != nil || accountInfo == nil || accountInfo.ID == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error_msg": "Account not found",
+			"msg":       "Not able to fetch status history",
+		})
+		return
+	}
+
+	changes, err := c.repo.ListAccountStatusChanges(ctx.Request.Context(), accountInfo.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to fetch status history",
+		})
+		return
+	}
+
+	items := make([]gin.H, 0, len(changes))
+	for _, change := range changes {
+		items = append(items, gin.H{
+			"id":          change.ID,
+			"from_status": change.FromStatus,
+			"to_status":   change.ToStatus,
+			"reason_code": change.ReasonCode,
+			"changed_by":  change.ChangedBy,
+			"changed_at":  change.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"account_id": accountInfo.ID,
+		"status":     accountInfo.Status,
+		"changes":    items,
+		"msg":        "Status history fetched successfully",
+	})
+}
+
+// changeAccountStatus validates the request and moves the account to the given status
+func (c *Controller) changeAccountStatus(ctx *gin.Context, status model.AccountStatus, successMsg, failureMsg string) {
+	var request accountStatusRequest
+
+	accountID, err := strconv.Atoi(ctx.Param("accountId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid accountId",
+			"msg":       failureMsg,
+		})
+		return
+	}
+
+	if err = ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Invalid request body",
+			"msg":       failureMsg,
+		})
+		return
+	}
+
+	if !model.IsValidStatusReason(request.ReasonCode) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error_msg": "Invalid reason code",
+			"msg":       failureMsg,
+		})
+		return
+	}
+
+	accountInfo, err := c.repo.ChangeAccountStatus(ctx.Request.Context(), uint(accountID), status, request.ReasonCode, request.ChangedBy)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error_msg": "Account not found",
+			"msg":       failureMsg,
+		})
+		return
+	case errors.Is(err, repo.ErrInvalidStatusTransition), errors.Is(err, repo.ErrAccountHasDebt):
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Account status can not be changed",
+			"msg":       failureMsg,
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       failureMsg,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"account_id": accountInfo.ID,
+		"status":     accountInfo.Status,
+		"msg":        successMsg,
+	})
+}
@@ -0,0 +1,232 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+	"github.com/vamshi1997/pismo-assessment/internal/repo/mock"
+	"gorm.io/gorm"
+)
+
+func TestController_ChangeAccountStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		accountID      string
+		body           string
+		handler        func(c *Controller) gin.HandlerFunc
+		mockBehavior   func(m *mock.MockIRepository)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:      "Block Account",
+			accountID: "1",
+			body:      `{"reason_code": "fraud_suspected", "changed_by": "ops@bank"}`,
+			handler:   func(c *Controller) gin.HandlerFunc { return c.BlockAccount },
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().
+					ChangeAccountStatus(gomock.Any(), uint(1), model.AccountBlocked, model.ReasonFraudSuspected, "ops@bank").
+					Return(&model.Account{ID: 1, Status: model.AccountBlocked}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"account_id": float64(1),
+				"status":     "blocked",
+				"msg":        "Account blocked successfully",
+			},
+		},
+		{
+			name:      "Unblock Account",
+			accountID: "1",
+			body:      `{"reason_code": "issue_resolved", "changed_by": "ops@bank"}`,
+			handler:   func(c *Controller) gin.HandlerFunc { return c.UnblockAccount },
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().
+					ChangeAccountStatus(gomock.Any(), uint(1), model.AccountActive, model.ReasonIssueResolved, "ops@bank").
+					Return(&model.Account{ID: 1, Status: model.AccountActive}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"status": "active",
+				"msg":    "Account unblocked successfully",
+			},
+		},
+		{
+			name:      "Unblock Active Account",
+			accountID: "1",
+			body:      `{"reason_code": "issue_resolved", "changed_by": "ops@bank"}`,
+			handler:   func(c *Controller) gin.HandlerFunc { return c.UnblockAccount },
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().
+					ChangeAccountStatus(gomock.Any(), uint(1), model.AccountActive, model.ReasonIssueResolved, "ops@bank").
+					Return(nil, repo.ErrInvalidStatusTransition)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: map[string]interface{}{
+				"error":     "account can not move to the requested status",
+				"error_msg": "Account status can not be changed",
+				"msg":       "Not able to unblock account",
+			},
+		},
+		{
+			name:      "Close Account With Debt",
+			accountID: "1",
+			body:      `{"reason_code": "customer_request", "changed_by": "support"}`,
+			handler:   func(c *Controller) gin.HandlerFunc { return c.CloseAccount },
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().
+					ChangeAccountStatus(gomock.Any(), uint(1), model.AccountClosed, model.ReasonCustomerRequest, "support").
+					Return(nil, repo.ErrAccountHasDebt)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: map[string]interface{}{
+				"error": "account with outstanding debt can not be closed",
+				"msg":   "Not able to close account",
+			},
+		},
+		{
+			name:           "Unknown Reason Code",
+			accountID:      "1",
+			body:           `{"reason_code": "because", "changed_by": "support"}`,
+			handler:        func(c *Controller) gin.HandlerFunc { return c.CloseAccount },
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Invalid reason code",
+				"msg":       "Not able to close account",
+			},
+		},
+		{
+			name:           "Missing Changed By",
+			accountID:      "1",
+			body:           `{"reason_code": "other"}`,
+			handler:        func(c *Controller) gin.HandlerFunc { return c.BlockAccount },
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Invalid request body",
+			},
+		},
+		{
+			name:           "Invalid Account ID",
+			accountID:      "abc",
+			body:           `{"reason_code": "other", "changed_by": "support"}`,
+			handler:        func(c *Controller) gin.HandlerFunc { return c.BlockAccount },
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Not valid accountId",
+			},
+		},
+		{
+			name:      "Account Not Found",
+			accountID: "7",
+			body:      `{"reason_code": "other", "changed_by": "support"}`,
+			handler:   func(c *Controller) gin.HandlerFunc { return c.BlockAccount },
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().ChangeAccountStatus(gomock.Any(), uint(7), model.AccountBlocked, model.ReasonOther, "support").
+					Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Account not found",
+			},
+		},
+		{
+			name:      "Database Error",
+			accountID: "1",
+			body:      `{"reason_code": "other", "changed_by": "support"}`,
+			handler:   func(c *Controller) gin.HandlerFunc { return c.BlockAccount },
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().ChangeAccountStatus(gomock.Any(), uint(1), model.AccountBlocked, model.ReasonOther, "support").
+					Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"error":     "database error",
+				"error_msg": "Internal Server Error",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+			controller := NewController(mockRepo)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/accounts/"+tt.accountID+"/block", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = []gin.Param{{Key: "accountId", Value: tt.accountID}}
+
+			tt.handler(controller)(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key], "mismatch in field: %s", key)
+			}
+		})
+	}
+}
+
+func TestController_ListAccountStatusChanges(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	changedAt := time.Date(2025, 2, 10, 10, 0, 0, 0, time.UTC)
+
+	mockRepo := mock.NewMockIRepository(ctrl)
+	mockRepo.EXPECT().GetAccount(gomock.Any(), uint(1)).Return(&model.Account{ID: 1, Status: model.AccountActive}, nil)
+	mockRepo.EXPECT().ListAccountStatusChanges(gomock.Any(), uint(1)).Return([]model.AccountStatusChange{
+		{ID: 1, AccountID: 1, FromStatus: model.AccountActive, ToStatus: model.AccountBlocked,
+			ReasonCode: model.ReasonLostOrStolen, ChangedBy: "support", Model: gorm.Model{CreatedAt: changedAt}},
+		{ID: 2, AccountID: 1, FromStatus: model.AccountBlocked, ToStatus: model.AccountActive,
+			ReasonCode: model.ReasonIssueResolved, ChangedBy: "ops@bank", Model: gorm.Model{CreatedAt: changedAt}},
+	}, nil)
+	controller := NewController(mockRepo)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/accounts/1/status-history", nil)
+	c.Params = []gin.Param{{Key: "accountId", Value: "1"}}
+
+	controller.ListAccountStatusChanges(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "active", response["status"])
+
+	changes := response["changes"].([]interface{})
+	assert.Len(t, changes, 2)
+	first := changes[0].(map[string]interface{})
+	assert.Equal(t, "blocked", first["to_status"])
+	assert.Equal(t, "lost_or_stolen", first["reason_code"])
+	assert.Equal(t, "support", first["changed_by"])
+	assert.Equal(t, "2025-02-10T10:00:00Z", first["changed_at"])
+}
@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"gorm.io/gorm"
+)
+
+// accrualRateRequest is the body of an accrual rate change
+type accrualRateRequest struct {
+	InterestRateBps uint        `json:"interest_rate_bps"`
+	LateFee         model.Money `json:"late_fee"`
+	GraceDays       uint        `json:"grace_days"`
+}
+
+// ListAccrualRates method returns the interest and late fee configuration of every operation type which has one
+func (c *Controller) ListAccrualRates(ctx *gin.Context) {
+	rates, err := c.repo.ListAccrualRates(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to fetch accrual rates",
+		})
+		return
+	}
+
+	if rates == nil {
+		rates = []model.AccrualRate{}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"accrual_rates": rates,
+		"msg":           "Accrual rates fetched successfully",
+	})
+}
+
+// SaveAccrualRate method sets the interest rate, late fee and grace days applied to overdue debt of an
+// operation type. Only debit operation types can accrue charges.
+func (c *Controller) SaveAccrualRate(ctx *gin.Context) {
+	var request accrualRateRequest
+
+	operationTypeID, err := strconv.Atoi(ctx.Param("operationTypeId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid operationTypeId",
+			"msg":       "Not able to save accrual rate",
+		})
+		return
+	}
+
+	if err = ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Invalid request body",
+			"msg":       "Not able to save accrual rate",
+		})
+		return
+	}
+
+	if request.LateFee < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error_msg": "Late fee can not be negative",
+			"msg":       "Not able to save accrual rate",
+		})
+		return
+	}
+
+	operationType, err := c.repo.GetOperationType(ctx.Request.Context(), uint(operationTypeID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error_msg": "Operation type not found",
+			"msg":       "Not able to save accrual rate",
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to save accrual rate",
+		})
+		return
+	}
+
+	if !operationType.IsDebit() {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error_msg": "Only debit operation types can accrue charges",
+			"msg":       "Not able to save accrual rate",
+		})
+		return
+	}
+
+	rate, err := c.repo.SaveAccrualRate(ctx.Request.Context(), model.AccrualRate{
+		OperationTypeID: operationType.ID,
+		InterestRateBps: request.InterestRateBps,
+		LateFee:         request.LateFee,
+		GraceDays:       request.GraceDays,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to save accrual rate",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"accrual_rate": rate,
+		"msg":          "Accrual rate saved successfully",
+	})
+}
+
+// DeleteAccrualRate method removes the accrual rate of an operation type, so its debt stops accruing charges
+func (c *Controller) DeleteAccrualRate(ctx *gin.Context) {
+	operationTypeID, err := strconv.Atoi(ctx.Param("operationTypeId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid operationTypeId",
+			"msg":       "Not able to delete accrual rate",
+		})
+		return
+	}
+
+	err = c.repo.DeleteAccrualRate(ctx.Request.Context(), uint(operationTypeID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error_msg": "Accrual rate not found",
+			"msg":       "Not able to delete accrual rate",
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to delete accrual rate",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"operation_type_id": operationTypeID,
+		"msg":               "Accrual rate deleted successfully",
+	})
+}
+
+// ListTransactionAccruals method lists the interest and late fee charges posted on a debt
+func (c *Controller) ListTransactionAccruals(ctx *gin.Context) {
+	transactionID, err := strconv.Atoi(ctx.Param("transactionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid transactionId",
+			"msg":       "Not able to fetch accruals",
+		})
+		return
+	}
+
+	transactionInfo, err := c.repo.GetTransaction(ctx.Request.Context(), uint(transactionID))
+	if err != nil || transactionInfo == nil || transactionInfo.ID == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error_msg": "Transaction not found",
+			"msg":       "Not able to fetch accruals",
+		})
+		return
+	}
+
+	accruals, err := c.repo.ListAccruals(ctx.Request.Context(), transactionInfo.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to fetch accruals",
+		})
+		return
+	}
+
+	items := make([]gin.H, 0, len(accruals))
+	for _, accrual := range accruals {
+		items = append(items, gin.H{
+			"accrual_id":     accrual.ID,
+			"kind":           accrual.Kind,
+			"accrual_date":   accrual.AccrualDate,
+			"transaction_id": accrual.TransactionID,
+			"amount":         accrual.Amount,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"transaction_id": transactionInfo.ID,
+		"balance":        transactionInfo.Balance,
+		"accruals":       items,
+		"msg":            "Accruals fetched successfully",
+	})
+}
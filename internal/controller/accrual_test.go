@@ -0,0 +1,227 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo/mock"
+	"gorm.io/gorm"
+)
+
+func TestController_SaveAccrualRate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name            string
+		operationTypeID string
+		body            string
+		mockBehavior    func(m *mock.MockIRepository)
+		expectedStatus  int
+		expectedBody    map[string]interface{}
+	}{
+		{
+			name:            "Success",
+			operationTypeID: "1",
+			body:            `{"interest_rate_bps":2400,"late_fee":10,"grace_days":5}`,
+			mockBehavior: func(m *mock.MockIRepository) {
+				expectOperationTypes(m)
+				m.EXPECT().
+					SaveAccrualRate(gomock.Any(), model.AccrualRate{OperationTypeID: 1, InterestRateBps: 2400, LateFee: model.MustParseMoney("10"), GraceDays: 5}).
+					Return(&model.AccrualRate{OperationTypeID: 1, InterestRateBps: 2400, LateFee: model.MustParseMoney("10"), GraceDays: 5}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"accrual_rate": map[string]interface{}{
+					"operation_type_id": float64(1),
+					"interest_rate_bps": float64(2400),
+					"late_fee":          float64(10),
+					"grace_days":        float64(5),
+					"updated_at":        "0001-01-01T00:00:00Z",
+				},
+				"msg": "Accrual rate saved successfully",
+			},
+		},
+		{
+			name:            "Credit Operation Type",
+			operationTypeID: "4",
+			body:            `{"interest_rate_bps":2400}`,
+			mockBehavior: func(m *mock.MockIRepository) {
+				expectOperationTypes(m)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Only debit operation types can accrue charges",
+				"msg":       "Not able to save accrual rate",
+			},
+		},
+		{
+			name:            "Negative Late Fee",
+			operationTypeID: "1",
+			body:            `{"late_fee":-1}`,
+			mockBehavior:    func(m *mock.MockIRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Late fee can not be negative",
+			},
+		},
+		{
+			name:            "Unknown Operation Type",
+			operationTypeID: "99",
+			body:            `{"interest_rate_bps":2400}`,
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetOperationType(gomock.Any(), uint(99)).Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Operation type not found",
+			},
+		},
+		{
+			name:            "Invalid Operation Type ID",
+			operationTypeID: "abc",
+			body:            `{}`,
+			mockBehavior:    func(m *mock.MockIRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Not valid operationTypeId",
+			},
+		},
+		{
+			name:            "Database Error",
+			operationTypeID: "1",
+			body:            `{"interest_rate_bps":2400}`,
+			mockBehavior: func(m *mock.MockIRepository) {
+				expectOperationTypes(m)
+				m.EXPECT().SaveAccrualRate(gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"error":     "database error",
+				"error_msg": "Internal Server Error",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+			controller := NewController(mockRepo)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/operation-types/"+tt.operationTypeID+"/accrual-rate", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = []gin.Param{{Key: "operationTypeId", Value: tt.operationTypeID}}
+
+			controller.SaveAccrualRate(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key], "mismatch in field: %s", key)
+			}
+		})
+	}
+}
+
+func TestController_ListTransactionAccruals(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		transactionID  string
+		mockBehavior   func(m *mock.MockIRepository)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:          "Success",
+			transactionID: "1",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetTransaction(gomock.Any(), uint(1)).Return(&model.Transaction{ID: 1, Balance: model.MustParseMoney("-100")}, nil)
+				m.EXPECT().ListAccruals(gomock.Any(), uint(1)).Return([]model.Accrual{
+					{ID: 1, DebtID: 1, Kind: model.AccrualLateFee, AccrualDate: "2025-02-10", TransactionID: 8, Amount: model.MustParseMoney("-10")},
+					{ID: 2, DebtID: 1, Kind: model.AccrualInterest, AccrualDate: "2025-02-10", TransactionID: 9, Amount: model.MustParseMoney("-0.07")},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"transaction_id": float64(1),
+				"balance":        float64(-100),
+				"accruals": []interface{}{
+					map[string]interface{}{
+						"accrual_id":     float64(1),
+						"kind":           "late_fee",
+						"accrual_date":   "2025-02-10",
+						"transaction_id": float64(8),
+						"amount":         float64(-10),
+					},
+					map[string]interface{}{
+						"accrual_id":     float64(2),
+						"kind":           "interest",
+						"accrual_date":   "2025-02-10",
+						"transaction_id": float64(9),
+						"amount":         -0.07,
+					},
+				},
+				"msg": "Accruals fetched successfully",
+			},
+		},
+		{
+			name:          "Transaction Not Found",
+			transactionID: "7",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetTransaction(gomock.Any(), uint(7)).Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Transaction not found",
+				"msg":       "Not able to fetch accruals",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+			controller := NewController(mockRepo)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/transactions/"+tt.transactionID+"/accruals", nil)
+			c.Params = []gin.Param{{Key: "transactionId", Value: tt.transactionID}}
+
+			controller.ListTransactionAccruals(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key], "mismatch in field: %s", key)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListTransactionAllocations method lists which voucher paid which debit for the given transaction.
+// For a credit voucher these are the debits it paid, for a debit the vouchers which paid it.
+func (c *Controller) ListTransactionAllocations(ctx *gin.Context) {
+	transactionID, err := strconv.Atoi(ctx.Param("transactionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid transactionId",
+			"msg":       "Not able to fetch allocations",
+		})
+		return
+	}
+
+	transactionInfo, err := c.repo.GetTransaction(ctx.Request.Context(), uint(transactionID))
+	if err != nil || transactionInfo == nil || transactionInfo.ID == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error_msg": "Transaction not found",
+			"msg":       "Not able to fetch allocations",
+		})
+		return
+	}
+
+	allocations, err := c.repo.ListTransactionAllocations(ctx.Request.Context(), transactionInfo.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to fetch allocations",
+		})
+		return
+	}
+
+	items := make([]gin.H, 0, len(allocations))
+	for _, allocation := range allocations {
+		items = append(items, gin.H{
+			"allocation_id": allocation.ID,
+			"voucher_id":    allocation.VoucherID,
+			"debit_id":      allocation.DebitID,
+			"amount":        allocation.Amount,
+			"allocated_at":  allocation.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"transaction_id":    transactionInfo.ID,
+		"operation_type_id": transactionInfo.OperationTypeId,
+		"amount":            transactionInfo.Amount,
+		"balance":           transactionInfo.Balance,
+		"allocations":       items,
+		"msg":               "Allocations fetched successfully",
+	})
+}
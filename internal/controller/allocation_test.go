@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo/mock"
+)
+
+func TestController_ListTransactionAllocations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		transactionID  string
+		mockBehavior   func(m *mock.MockIRepository)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+		expectedCount  int
+	}{
+		{
+			name:          "Voucher Allocations",
+			transactionID: "5",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetTransaction(gomock.Any(), uint(5)).Return(&model.Transaction{
+					ID:              5,
+					OperationTypeId: 4,
+					Amount:          model.MustParseMoney("60"),
+					Balance:         model.MustParseMoney("0"),
+				}, nil)
+				m.EXPECT().ListTransactionAllocations(gomock.Any(), uint(5)).Return([]model.Allocation{
+					{ID: 1, VoucherID: 5, DebitID: 3, Amount: model.MustParseMoney("40")},
+					{ID: 2, VoucherID: 5, DebitID: 4, Amount: model.MustParseMoney("20")},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"transaction_id":    float64(5),
+				"operation_type_id": float64(4),
+				"amount":            float64(60),
+				"balance":           float64(0),
+				"msg":               "Allocations fetched successfully",
+			},
+			expectedCount: 2,
+		},
+		{
+			name:           "Invalid Transaction ID",
+			transactionID:  "abc",
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Not valid transactionId",
+				"msg":       "Not able to fetch allocations",
+			},
+		},
+		{
+			name:          "Transaction Not Found",
+			transactionID: "99",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetTransaction(gomock.Any(), uint(99)).Return(nil, errors.New("record not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Transaction not found",
+			},
+		},
+		{
+			name:          "Database Error",
+			transactionID: "5",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetTransaction(gomock.Any(), uint(5)).Return(&model.Transaction{ID: 5}, nil)
+				m.EXPECT().ListTransactionAllocations(gomock.Any(), uint(5)).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"error":     "database error",
+				"error_msg": "Internal Server Error",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+			controller := NewController(mockRepo)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/transactions/"+tt.transactionID+"/allocations", nil)
+			c.Params = []gin.Param{{Key: "transactionId", Value: tt.transactionID}}
+
+			controller.ListTransactionAllocations(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key], "mismatch in field: %s", key)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				assert.Len(t, response["allocations"], tt.expectedCount)
+			}
+		})
+	}
+}
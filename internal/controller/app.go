@@ -1,10 +1,13 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/vamshi1997/pismo-assessment/internal/document"
 	"github.com/vamshi1997/pismo-assessment/internal/model"
 	"github.com/vamshi1997/pismo-assessment/internal/repo"
-	"log"
+	"gorm.io/gorm"
 	"net/http"
 	"strconv"
 )
@@ -41,16 +44,62 @@ func (c *Controller) CreateAccount(ctx *gin.Context) {
 		return
 	}
 
-	// check if document number is valid or not
-	if len(account.DocumentNumber) != 11 {
+	// check if document number is a valid CPF or CNPJ, and store it without formatting
+	documentNumber, documentType, err := document.Validate(account.DocumentNumber)
+	if err != nil {
+		var validationErr *document.ValidationError
+		if errors.As(err, &validationErr) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":      validationErr.Message,
+				"error_code": validationErr.Code,
+				"error_msg":  "Document number given is not valid",
+				"msg":        "Not able to create account",
+			})
+			return
+		}
 		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
 			"error_msg": "Document number given is not valid",
 			"msg":       "Not able to create account",
 		})
 		return
 	}
+	account.DocumentNumber = documentNumber
+	account.DocumentType = string(documentType)
+
+	// new accounts are always active, the available limit is always computed, and a credit limit can
+	// not be negative
+	account.Status = model.AccountActive
+	account.AvailableLimit = nil
+	if account.CreditLimit != nil && *account.CreditLimit < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error_msg": "Credit limit can not be negative",
+			"msg":       "Not able to create account",
+		})
+		return
+	}
 
-	accountInfo, err := c.repo.CreateAccount(account)
+	// accounts which do not choose a billing day close their cycle on the default day
+	if account.BillingDay == 0 {
+		account.BillingDay = model.DefaultBillingDay
+	}
+	if !model.IsValidBillingDay(account.BillingDay) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error_msg": fmt.Sprintf("Billing day should be between %d and %d", model.MinBillingDay, model.MaxBillingDay),
+			"msg":       "Not able to create account",
+		})
+		return
+	}
+
+	accountInfo, err = c.repo.CreateAccount(ctx.Request.Context(), account)
+	if errors.Is(err, repo.ErrDocumentNumberTaken) {
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Document number is already registered",
+			"msg":       "Not able to create account",
+		})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"error":     err.Error(),
@@ -62,7 +111,11 @@ func (c *Controller) CreateAccount(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{
 		"document_number": accountInfo.DocumentNumber,
+		"document_type":   accountInfo.DocumentType,
+		"status":          accountInfo.Status,
 		"account_id":      accountInfo.ID,
+		"billing_day":     accountInfo.BillingDay,
+		"credit_limit":    accountInfo.CreditLimit,
 		"msg":             "Account created successfully",
 	})
 }
@@ -82,7 +135,7 @@ func (c *Controller) GetAccount(ctx *gin.Context) {
 	}
 
 	// fetch account info from db
-	accountInfo, err := c.repo.GetAccount(uint(accountID))
+	accountInfo, err := c.repo.GetAccount(ctx.Request.Context(), uint(accountID))
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{
 			"error":     err.Error(),
@@ -100,9 +153,28 @@ func (c *Controller) GetAccount(ctx *gin.Context) {
 		return // Add this return statement
 	}
 
+	// the available limit only exists for accounts with a credit limit
+	if accountInfo.CreditLimit != nil {
+		netBalance, err := c.repo.GetAccountNetBalance(ctx.Request.Context(), accountInfo.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":     err.Error(),
+				"error_msg": "Internal Server Error",
+				"msg":       "Not able to fetch account details",
+			})
+			return
+		}
+		accountInfo.AvailableLimit = accountInfo.AvailableLimitFor(netBalance)
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"account_id":      accountInfo.ID,
 		"document_number": accountInfo.DocumentNumber,
+		"document_type":   accountInfo.DocumentType,
+		"status":          accountInfo.Status,
+		"billing_day":     accountInfo.BillingDay,
+		"credit_limit":    accountInfo.CreditLimit,
+		"available_limit": accountInfo.AvailableLimit,
 		"msg":             "Account details fetched successfully",
 	})
 }
@@ -123,16 +195,30 @@ func (c *Controller) CreateTransaction(ctx *gin.Context) {
 		return
 	}
 
-	// check1: operation should be valid type
-	if !model.IsValidOperationType(transaction.OperationTypeId) {
+	// fields which are only set by the service itself
+	transaction.InstallmentNumber = 0
+	transaction.ParentTransactionID = nil
+	transaction.DueDate = nil
+	transaction.ReversedTransactionID = nil
+
+	// check1: operation should be a known type which clients are allowed to create
+	operationType, err := c.repo.GetOperationType(ctx.Request.Context(), transaction.OperationTypeId)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to create transaction"})
+		return
+	}
+	if operationType == nil || operationType.Internal {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"error_msg": "Invalid operation type",
 			"msg":       "Not able to create transaction"})
 		return
 	}
 
-	// check 2: purchase operations should have negative amount
-	if (transaction.OperationTypeId == 1 || transaction.OperationTypeId == 2 || transaction.OperationTypeId == 3) && transaction.Amount >= 0 {
+	// check 2: debit operations such as purchases should have negative amount
+	if operationType.IsDebit() && transaction.Amount >= 0 {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"error_msg": "Amount can not be positive for this operation type",
 			"msg":       "Not able to create transaction",
@@ -140,8 +226,8 @@ func (c *Controller) CreateTransaction(ctx *gin.Context) {
 		return
 	}
 
-	// check 3: credit voucher should have positive amount
-	if transaction.OperationTypeId == 4 && transaction.Amount < 0 {
+	// check 3: credit operations such as credit vouchers should have positive amount
+	if operationType.IsCredit() && transaction.Amount < 0 {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"error_mgs": "Amount can not be negative for this operation type",
 			"msg":       "Not able to create transaction",
@@ -149,8 +235,22 @@ func (c *Controller) CreateTransaction(ctx *gin.Context) {
 		return
 	}
 
-	// check 4: if account is valid or not, then only transaction can be done
-	accountInfo, err := c.repo.GetAccount(transaction.AccountID)
+	// check 4: only purchases with installments can be split, and each installment needs at least a cent
+	if transaction.Installments != 0 {
+		if !operationType.AllowsInstallments ||
+			!model.IsValidInstallmentCount(transaction.Installments) ||
+			-transaction.Amount.MinorUnits() < int64(transaction.Installments) {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error_msg": fmt.Sprintf("Installments should be between %d and %d for purchases with installments",
+					model.MinInstallments, model.MaxInstallments),
+				"msg": "Not able to create transaction",
+			})
+			return
+		}
+	}
+
+	// check 5: if account is valid or not, then only transaction can be done
+	accountInfo, err := c.repo.GetAccount(ctx.Request.Context(), transaction.AccountID)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{
 			"error":     err.Error(),
@@ -167,91 +267,75 @@ func (c *Controller) CreateTransaction(ctx *gin.Context) {
 		return // Add this return statement
 	}
 
+	// check 6: blocked accounts only receive credits and closed accounts receive nothing
+	if (operationType.IsDebit() && !accountInfo.Status.AllowsDebits()) ||
+		(operationType.IsCredit() && !accountInfo.Status.AllowsCredits()) {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error_msg": "Account status does not allow this operation type",
+			"msg":       "Not able to create transaction",
+			"status":    accountInfo.Status,
+		})
+		return
+	}
+
 	// case 1: in case copy balance
-	if transaction.OperationTypeId == 1 || transaction.OperationTypeId == 2 || transaction.OperationTypeId == 3 {
+	if operationType.IsDebit() && transaction.Installments == 0 {
 		transaction.Balance = transaction.Amount
 
-		if transactionInfo, err = c.repo.CreateTransaction(transaction); err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{
-				"error":     err.Error(),
-				"error_msg": "Invalid transaction",
-				"msg":       "Not able to create transaction"})
+		if transactionInfo, err = c.repo.CreateTransaction(ctx.Request.Context(), transaction); err != nil {
+			transactionFailure(ctx, err)
 			return
 		}
 	}
 
-	// case 2: read previous transaction and update it
-	if transaction.OperationTypeId == 4 {
-		previousTransactions, err := c.repo.GetPreviousTransactions()
-
-		log.Println("previous transactions len: ", len(previousTransactions))
-		log.Println("previous transactions: ", previousTransactions)
-
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{})
+	// case 2: purchase with installments, the debt is spread over the installment schedule
+	if operationType.IsDebit() && transaction.Installments != 0 {
+		if transactionInfo, err = c.repo.CreateInstallmentPurchase(ctx.Request.Context(), transaction); err != nil {
+			transactionFailure(ctx, err)
 			return
 		}
+	}
 
-		if len(previousTransactions) <= 0 {
-
-			transaction.Balance = transaction.Amount
-
-			if transactionInfo, err = c.repo.CreateTransaction(transaction); err != nil {
-				ctx.JSON(http.StatusInternalServerError, gin.H{
-					"error":     err.Error(),
-					"error_msg": "Invalid transaction",
-					"msg":       "Not able to create transaction"})
-			}
-
-			ctx.JSON(http.StatusOK, map[string]interface{}{
-				"msg":               "transaction created successfully",
-				"account_id":        transaction.AccountID,
-				"transaction_id":    transactionInfo.ID,
-				"operation_type_id": transaction.OperationTypeId,
-				"amount":            transaction.Amount})
-
+	// case 3: discharge the account's previous transactions and store the voucher atomically
+	if operationType.IsCredit() {
+		if transactionInfo, err = c.repo.DischargeCreditVoucher(ctx.Request.Context(), transaction); err != nil {
+			transactionFailure(ctx, err)
 			return
-		} else {
-			remainingBalance := transaction.Amount
-			log.Println("initial remaining balance: ", remainingBalance)
-
-			for _, previousTransaction := range previousTransactions {
-				if previousTransaction.Balance < 0 {
-					remainingBalance = remainingBalance + previousTransaction.Balance
-					log.Println("leftover remaining balance: ", remainingBalance)
-
-					if remainingBalance >= 0 {
-						c.repo.UpdateTransactionBalance(0, previousTransaction.ID)
-					} else {
-						c.repo.UpdateTransactionBalance(remainingBalance, previousTransaction.ID)
-						break
-					}
-				}
-			}
-
-			if remainingBalance > 0 {
-				transaction.Balance = remainingBalance
-				transactionInfo, _ = c.repo.CreateTransaction(transaction)
-			} else {
-				transaction.Balance = 0
-				transactionInfo, _ = c.repo.CreateTransaction(transaction)
-			}
 		}
-
-		ctx.JSON(http.StatusOK, map[string]interface{}{
-			"msg":               "transaction created successfully",
-			"account_id":        transaction.AccountID,
-			"transaction_id":    transactionInfo.ID,
-			"operation_type_id": transaction.OperationTypeId,
-			"amount":            transaction.Amount})
-
-		return
 	}
 
-	ctx.JSON(http.StatusOK, map[string]interface{}{
+	response := map[string]interface{}{
 		"msg":               "transaction created successfully",
 		"account_id":        transaction.AccountID,
 		"transaction_id":    transactionInfo.ID,
 		"operation_type_id": transaction.OperationTypeId,
-		"amount":            transaction.Amount})
+		"amount":            transaction.Amount}
+	if transaction.Installments != 0 {
+		response["installments"] = transaction.Installments
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// transactionFailure responds to a transaction the repository refused to store. The account status and
+// the credit limit can change between the checks above and the write, so the repository checks them
+// again with the account locked.
+func transactionFailure(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repo.ErrTransactionNotAllowed):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Account status does not allow this operation type",
+			"msg":       "Not able to create transaction"})
+	case errors.Is(err, repo.ErrCreditLimitExceeded):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Credit limit exceeded",
+			"msg":       "Not able to create transaction"})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Invalid transaction",
+			"msg":       "Not able to create transaction"})
+	}
 }
@@ -2,19 +2,41 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
 	"github.com/vamshi1997/pismo-assessment/internal/repo/mock"
+	"gorm.io/gorm"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
 	"testing"
 )
 
+// expectOperationTypes lets the mock serve the default operation types, after any case specific expectations
+func expectOperationTypes(m *mock.MockIRepository) {
+	m.EXPECT().
+		GetOperationType(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, operationTypeId uint) (*model.OperationType, error) {
+			for _, operationType := range model.DefaultOperationTypes {
+				if operationType.ID == operationTypeId {
+					return &operationType, nil
+				}
+			}
+			return nil, gorm.ErrRecordNotFound
+		}).
+		AnyTimes()
+	m.EXPECT().
+		ListOperationTypes(gomock.Any()).
+		Return(model.DefaultOperationTypes, nil).
+		AnyTimes()
+}
+
 func TestStatus(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	w := httptest.NewRecorder()
@@ -39,6 +61,8 @@ func TestController_CreateAccount(t *testing.T) {
 	mockRepo := mock.NewMockIRepository(ctrl)
 	controller := NewController(mockRepo)
 
+	negativeLimit := model.MustParseMoney("-1")
+
 	tests := []struct {
 		name           string
 		input          model.Account
@@ -49,20 +73,38 @@ func TestController_CreateAccount(t *testing.T) {
 		{
 			name: "Success",
 			input: model.Account{
-				DocumentNumber: "12345678901",
+				DocumentNumber: "12345678909",
 			},
 			mockBehavior: func(mock *mock.MockIRepository, account model.Account) {
 				mock.EXPECT().
-					CreateAccount(account).
-					Return(model.Account{ID: 1, DocumentNumber: account.DocumentNumber}, nil)
+					CreateAccount(gomock.Any(), model.Account{DocumentNumber: "12345678909", DocumentType: "CPF", Status: model.AccountActive, BillingDay: model.DefaultBillingDay}).
+					Return(model.Account{ID: 1, DocumentNumber: "12345678909", DocumentType: "CPF"}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody: gin.H{
-				"document_number": "12345678901",
+				"document_number": "12345678909",
+				"document_type":   "CPF",
 				"account_id":      float64(1),
 				"msg":             "Account created successfully",
 			},
 		},
+		{
+			name: "Formatted CNPJ",
+			input: model.Account{
+				DocumentNumber: "11.222.333/0001-81",
+			},
+			mockBehavior: func(mock *mock.MockIRepository, account model.Account) {
+				mock.EXPECT().
+					CreateAccount(gomock.Any(), model.Account{DocumentNumber: "11222333000181", DocumentType: "CNPJ", Status: model.AccountActive, BillingDay: model.DefaultBillingDay}).
+					Return(model.Account{ID: 2, DocumentNumber: "11222333000181", DocumentType: "CNPJ"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: gin.H{
+				"document_number": "11222333000181",
+				"document_type":   "CNPJ",
+				"account_id":      float64(2),
+			},
+		},
 		{
 			name: "Invalid Document Number",
 			input: model.Account{
@@ -71,18 +113,102 @@ func TestController_CreateAccount(t *testing.T) {
 			mockBehavior:   func(mock *mock.MockIRepository, account model.Account) {},
 			expectedStatus: http.StatusBadRequest,
 			expectedBody: gin.H{
-				"error_msg": "Document number given is not valid",
+				"error_code": "document_invalid_length",
+				"error_msg":  "Document number given is not valid",
+				"msg":        "Not able to create account",
+			},
+		},
+		{
+			name: "Letters In Document Number",
+			input: model.Account{
+				DocumentNumber: "1234567890a",
+			},
+			mockBehavior:   func(mock *mock.MockIRepository, account model.Account) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: gin.H{
+				"error_code": "document_invalid_characters",
+			},
+		},
+		{
+			name: "Wrong Check Digit",
+			input: model.Account{
+				DocumentNumber: "12345678901",
+			},
+			mockBehavior:   func(mock *mock.MockIRepository, account model.Account) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: gin.H{
+				"error":      "document number check digits do not match",
+				"error_code": "document_invalid_check_digit",
+			},
+		},
+		{
+			name: "Negative Credit Limit",
+			input: model.Account{
+				DocumentNumber: "12345678909",
+				CreditLimit:    &negativeLimit,
+			},
+			mockBehavior:   func(mock *mock.MockIRepository, account model.Account) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: gin.H{
+				"error_msg": "Credit limit can not be negative",
+				"msg":       "Not able to create account",
+			},
+		},
+		{
+			name: "Chosen Billing Day",
+			input: model.Account{
+				DocumentNumber: "12345678909",
+				BillingDay:     15,
+			},
+			mockBehavior: func(mock *mock.MockIRepository, account model.Account) {
+				mock.EXPECT().
+					CreateAccount(gomock.Any(), model.Account{DocumentNumber: "12345678909", DocumentType: "CPF", Status: model.AccountActive, BillingDay: 15}).
+					Return(model.Account{ID: 3, DocumentNumber: "12345678909", DocumentType: "CPF", BillingDay: 15}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: gin.H{
+				"account_id":  float64(3),
+				"billing_day": float64(15),
+			},
+		},
+		{
+			name: "Invalid Billing Day",
+			input: model.Account{
+				DocumentNumber: "12345678909",
+				BillingDay:     31,
+			},
+			mockBehavior:   func(mock *mock.MockIRepository, account model.Account) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: gin.H{
+				"error_msg": "Billing day should be between 1 and 28",
+				"msg":       "Not able to create account",
+			},
+		},
+		{
+			name: "Document Number Taken",
+			input: model.Account{
+				DocumentNumber: "12345678909",
+			},
+			mockBehavior: func(mock *mock.MockIRepository, account model.Account) {
+				mock.EXPECT().
+					CreateAccount(gomock.Any(), gomock.Any()).
+					Return(model.Account{}, repo.ErrDocumentNumberTaken)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: gin.H{
+				"error":     "an account with this document number already exists",
+				"error_msg": "Document number is already registered",
 				"msg":       "Not able to create account",
 			},
 		},
 		{
 			name: "Database Error",
 			input: model.Account{
-				DocumentNumber: "12345678901",
+				DocumentNumber: "12345678909",
 			},
 			mockBehavior: func(mock *mock.MockIRepository, account model.Account) {
 				mock.EXPECT().
-					CreateAccount(account).
+					CreateAccount(gomock.Any(), gomock.Any()).
 					Return(model.Account{}, errors.New("database error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
@@ -140,7 +266,7 @@ func TestController_GetAccount(t *testing.T) {
 			accountID: "1",
 			mockBehavior: func(mock *mock.MockIRepository, accountID uint) {
 				mock.EXPECT().
-					GetAccount(accountID).
+					GetAccount(gomock.Any(), accountID).
 					Return(&model.Account{
 						ID:             1,
 						DocumentNumber: "12345678901",
@@ -153,6 +279,29 @@ func TestController_GetAccount(t *testing.T) {
 				"msg":             "Account details fetched successfully",
 			},
 		},
+		{
+			name:      "Success With Credit Limit",
+			accountID: "2",
+			mockBehavior: func(mock *mock.MockIRepository, accountID uint) {
+				limit := model.MustParseMoney("1000")
+				mock.EXPECT().
+					GetAccount(gomock.Any(), accountID).
+					Return(&model.Account{
+						ID:             2,
+						DocumentNumber: "12345678901",
+						CreditLimit:    &limit,
+					}, nil)
+				mock.EXPECT().
+					GetAccountNetBalance(gomock.Any(), accountID).
+					Return(model.MustParseMoney("-250.50"), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"account_id":      float64(2),
+				"credit_limit":    float64(1000),
+				"available_limit": 749.5,
+			},
+		},
 		{
 			name:           "Invalid Account ID Format",
 			accountID:      "invalid",
@@ -168,7 +317,7 @@ func TestController_GetAccount(t *testing.T) {
 			accountID: "3",
 			mockBehavior: func(mock *mock.MockIRepository, accountID uint) {
 				mock.EXPECT().
-					GetAccount(accountID).
+					GetAccount(gomock.Any(), accountID).
 					Return(nil, errors.New("account not found"))
 			},
 			expectedStatus: http.StatusNotFound,
@@ -183,7 +332,7 @@ func TestController_GetAccount(t *testing.T) {
 			accountID: "4",
 			mockBehavior: func(mock *mock.MockIRepository, accountID uint) {
 				mock.EXPECT().
-					GetAccount(accountID).
+					GetAccount(gomock.Any(), accountID).
 					Return(&model.Account{}, nil)
 			},
 			expectedStatus: http.StatusNotFound,
@@ -238,21 +387,21 @@ func TestController_CreateTransaction(t *testing.T) {
 			input: model.Transaction{
 				AccountID:       1,
 				OperationTypeId: 1,
-				Amount:          -100.0,
+				Amount:          model.MustParseMoney("-100"),
 			},
 			mockBehavior: func(m *mock.MockIRepository) {
 				m.EXPECT().
-					GetAccount(uint(1)).
-					Return(&model.Account{ID: 1, DocumentNumber: "12345678901"}, nil)
+					GetAccount(gomock.Any(), uint(1)).
+					Return(&model.Account{ID: 1, DocumentNumber: "12345678901", Status: model.AccountActive}, nil)
 
 				m.EXPECT().
-					CreateTransaction(gomock.Any()).
+					CreateTransaction(gomock.Any(), gomock.Any()).
 					Return(&model.Transaction{
 						ID:              1,
 						AccountID:       1,
 						OperationTypeId: 1,
-						Amount:          -100.0,
-						Balance:         -100.0,
+						Amount:          model.MustParseMoney("-100"),
+						Balance:         model.MustParseMoney("-100"),
 					}, nil)
 			},
 			expectedStatus: http.StatusOK,
@@ -269,7 +418,21 @@ func TestController_CreateTransaction(t *testing.T) {
 			input: model.Transaction{
 				AccountID:       1,
 				OperationTypeId: 10, // Invalid operation type
-				Amount:          -100.0,
+				Amount:          model.MustParseMoney("-100"),
+			},
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Invalid operation type",
+				"msg":       "Not able to create transaction",
+			},
+		},
+		{
+			name: "Internal Operation Type",
+			input: model.Transaction{
+				AccountID:       1,
+				OperationTypeId: 5, // reversals are only created by the reversal endpoint
+				Amount:          model.MustParseMoney("100"),
 			},
 			mockBehavior:   func(m *mock.MockIRepository) {},
 			expectedStatus: http.StatusBadRequest,
@@ -278,12 +441,60 @@ func TestController_CreateTransaction(t *testing.T) {
 				"msg":       "Not able to create transaction",
 			},
 		},
+		{
+			name: "New Debit Operation Type From Table",
+			input: model.Transaction{
+				AccountID:       1,
+				OperationTypeId: 6,
+				Amount:          model.MustParseMoney("-15"),
+			},
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().
+					GetOperationType(gomock.Any(), uint(6)).
+					Return(&model.OperationType{ID: 6, Description: "Bill Payment", AmountSign: -1, DischargeEligible: true}, nil)
+
+				m.EXPECT().
+					GetAccount(gomock.Any(), uint(1)).
+					Return(&model.Account{ID: 1, DocumentNumber: "12345678901", Status: model.AccountActive}, nil)
+
+				m.EXPECT().
+					CreateTransaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, transaction model.Transaction) (*model.Transaction, error) {
+						assert.Equal(t, transaction.Amount, transaction.Balance)
+						return &model.Transaction{ID: 11, AccountID: 1, OperationTypeId: 6, Amount: transaction.Amount}, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"transaction_id":    float64(11),
+				"operation_type_id": float64(6),
+				"amount":            float64(-15),
+			},
+		},
+		{
+			name: "Operation Type Lookup Failure",
+			input: model.Transaction{
+				AccountID:       1,
+				OperationTypeId: 1,
+				Amount:          model.MustParseMoney("-15"),
+			},
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().
+					GetOperationType(gomock.Any(), uint(1)).
+					Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"error":     "database error",
+				"error_msg": "Internal Server Error",
+			},
+		},
 		{
 			name: "Invalid Amount for Purchase (Positive Amount)",
 			input: model.Transaction{
 				AccountID:       1,
 				OperationTypeId: 1,
-				Amount:          100.0, // Should be negative for purchase
+				Amount:          model.MustParseMoney("100"), // Should be negative for purchase
 			},
 			mockBehavior:   func(m *mock.MockIRepository) {},
 			expectedStatus: http.StatusBadRequest,
@@ -292,16 +503,81 @@ func TestController_CreateTransaction(t *testing.T) {
 				"msg":       "Not able to create transaction",
 			},
 		},
+		{
+			name: "Valid Purchase With Installments",
+			input: model.Transaction{
+				AccountID:       1,
+				OperationTypeId: 2,
+				Amount:          model.MustParseMoney("-300"),
+				Installments:    3,
+			},
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().
+					GetAccount(gomock.Any(), uint(1)).
+					Return(&model.Account{ID: 1, DocumentNumber: "12345678901", Status: model.AccountActive}, nil)
+
+				m.EXPECT().
+					CreateInstallmentPurchase(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, purchase model.Transaction) (*model.Transaction, error) {
+						assert.Equal(t, uint(3), purchase.Installments)
+						assert.Equal(t, model.MustParseMoney("-300"), purchase.Amount)
+						return &model.Transaction{
+							ID:              1,
+							AccountID:       1,
+							OperationTypeId: 2,
+							Amount:          purchase.Amount,
+							Installments:    3,
+						}, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"msg":               "transaction created successfully",
+				"transaction_id":    float64(1),
+				"operation_type_id": float64(2),
+				"amount":            float64(-300),
+				"installments":      float64(3),
+			},
+		},
+		{
+			name: "Installments On Normal Purchase",
+			input: model.Transaction{
+				AccountID:       1,
+				OperationTypeId: 1,
+				Amount:          model.MustParseMoney("-300"),
+				Installments:    3,
+			},
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Installments should be between 2 and 48 for purchases with installments",
+				"msg":       "Not able to create transaction",
+			},
+		},
+		{
+			name: "Too Many Installments",
+			input: model.Transaction{
+				AccountID:       1,
+				OperationTypeId: 2,
+				Amount:          model.MustParseMoney("-300"),
+				Installments:    49,
+			},
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Installments should be between 2 and 48 for purchases with installments",
+			},
+		},
 		{
 			name: "Invalid Account",
 			input: model.Transaction{
 				AccountID:       999,
 				OperationTypeId: 1,
-				Amount:          -100.0,
+				Amount:          model.MustParseMoney("-100"),
 			},
 			mockBehavior: func(m *mock.MockIRepository) {
 				m.EXPECT().
-					GetAccount(uint(999)).
+					GetAccount(gomock.Any(), uint(999)).
 					Return((*model.Account)(nil), errors.New("account not found"))
 			},
 			expectedStatus: http.StatusNotFound,
@@ -316,50 +592,29 @@ func TestController_CreateTransaction(t *testing.T) {
 			input: model.Transaction{
 				AccountID:       1,
 				OperationTypeId: 4,
-				Amount:          100.0,
+				Amount:          model.MustParseMoney("100"),
 			},
 			mockBehavior: func(m *mock.MockIRepository) {
-				// Create expected calls in sequence
 				gomock.InOrder(
 					// 1. Check account exists
 					m.EXPECT().
-						GetAccount(uint(1)).
-						Return(&model.Account{ID: 1, DocumentNumber: "12345678901"}, nil),
+						GetAccount(gomock.Any(), uint(1)).
+						Return(&model.Account{ID: 1, DocumentNumber: "12345678901", Status: model.AccountActive}, nil),
 
-					// 2. Get previous transactions
+					// 2. Discharge previous transactions and create the voucher in one unit of work
 					m.EXPECT().
-						GetPreviousTransactions().
-						Return([]model.Transaction{
-							{
-								ID:              1,
+						DischargeCreditVoucher(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(_ context.Context, voucher model.Transaction) (*model.Transaction, error) {
+							assert.Equal(t, uint(1), voucher.AccountID)
+							assert.Equal(t, model.MustParseMoney("100"), voucher.Amount)
+							return &model.Transaction{
+								ID:              2,
 								AccountID:       1,
-								OperationTypeId: 1,
-								Amount:          -150.0,
-								Balance:         -150.0,
-							},
-						}, nil),
-
-					// 3. Update transaction balance
-					m.EXPECT().
-						UpdateTransactionBalance(gomock.Any(), uint(1)).
-						Return(&model.Transaction{
-							ID:              1,
-							AccountID:       1,
-							OperationTypeId: 1,
-							Amount:          -150.0,
-							Balance:         0.0,
-						}, nil),
-
-					// 4. Create new transaction
-					m.EXPECT().
-						CreateTransaction(gomock.Any()).
-						Return(&model.Transaction{
-							ID:              2,
-							AccountID:       1,
-							OperationTypeId: 4,
-							Amount:          100.0,
-							Balance:         0.0,
-						}, nil),
+								OperationTypeId: 4,
+								Amount:          model.MustParseMoney("100"),
+								Balance:         model.MustParseMoney("0"),
+							}, nil
+						}),
 				)
 			},
 			expectedStatus: http.StatusOK,
@@ -372,43 +627,152 @@ func TestController_CreateTransaction(t *testing.T) {
 			},
 		},
 		{
-			name: "Credit Voucher With No Previous Transactions",
+			name: "Purchase Above Credit Limit",
 			input: model.Transaction{
 				AccountID:       1,
-				OperationTypeId: 4,
-				Amount:          100.0,
+				OperationTypeId: 1,
+				Amount:          model.MustParseMoney("-600"),
 			},
 			mockBehavior: func(m *mock.MockIRepository) {
-				// First, expect GetAccount call
 				m.EXPECT().
-					GetAccount(uint(1)).
-					Return(&model.Account{ID: 1, DocumentNumber: "12345678901"}, nil)
+					GetAccount(gomock.Any(), uint(1)).
+					Return(&model.Account{ID: 1, DocumentNumber: "12345678901", Status: model.AccountActive}, nil)
 
-				// Then, expect GetPreviousTransactions call returning empty slice
 				m.EXPECT().
-					GetPreviousTransactions().
-					Return([]model.Transaction{}, nil)
+					CreateTransaction(gomock.Any(), gomock.Any()).
+					Return(nil, repo.ErrCreditLimitExceeded)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody: map[string]interface{}{
+				"error":     "amount exceeds the available credit limit",
+				"error_msg": "Credit limit exceeded",
+				"msg":       "Not able to create transaction",
+			},
+		},
+		{
+			name: "Installment Purchase Above Credit Limit",
+			input: model.Transaction{
+				AccountID:       1,
+				OperationTypeId: 2,
+				Amount:          model.MustParseMoney("-600"),
+				Installments:    3,
+			},
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().
+					GetAccount(gomock.Any(), uint(1)).
+					Return(&model.Account{ID: 1, DocumentNumber: "12345678901", Status: model.AccountActive}, nil)
 
-				// Expect CreateTransaction call
 				m.EXPECT().
-					CreateTransaction(gomock.Any()).
-					DoAndReturn(func(transaction model.Transaction) (*model.Transaction, error) {
-						return &model.Transaction{
-							ID:              1,
-							AccountID:       1,
-							OperationTypeId: 4,
-							Amount:          100.0,
-							Balance:         100.0,
-						}, nil
-					})
+					CreateInstallmentPurchase(gomock.Any(), gomock.Any()).
+					Return(nil, repo.ErrCreditLimitExceeded)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Credit limit exceeded",
+				"msg":       "Not able to create transaction",
+			},
+		},
+		{
+			name: "Purchase On Blocked Account",
+			input: model.Transaction{
+				AccountID:       1,
+				OperationTypeId: 1,
+				Amount:          model.MustParseMoney("-100"),
+			},
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().
+					GetAccount(gomock.Any(), uint(1)).
+					Return(&model.Account{ID: 1, DocumentNumber: "12345678901", Status: model.AccountBlocked}, nil)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Account status does not allow this operation type",
+				"msg":       "Not able to create transaction",
+				"status":    "blocked",
+			},
+		},
+		{
+			name: "Credit Voucher On Blocked Account",
+			input: model.Transaction{
+				AccountID:       1,
+				OperationTypeId: 4,
+				Amount:          model.MustParseMoney("100"),
+			},
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().
+					GetAccount(gomock.Any(), uint(1)).
+					Return(&model.Account{ID: 1, DocumentNumber: "12345678901", Status: model.AccountBlocked}, nil)
+
+				m.EXPECT().
+					DischargeCreditVoucher(gomock.Any(), gomock.Any()).
+					Return(&model.Transaction{ID: 2, AccountID: 1, OperationTypeId: 4, Amount: model.MustParseMoney("100")}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody: map[string]interface{}{
-				"msg":               "transaction created successfully",
-				"account_id":        float64(1),
-				"transaction_id":    float64(1),
-				"operation_type_id": float64(4),
-				"amount":            float64(100.0),
+				"transaction_id": float64(2),
+			},
+		},
+		{
+			name: "Credit Voucher On Closed Account",
+			input: model.Transaction{
+				AccountID:       1,
+				OperationTypeId: 4,
+				Amount:          model.MustParseMoney("100"),
+			},
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().
+					GetAccount(gomock.Any(), uint(1)).
+					Return(&model.Account{ID: 1, DocumentNumber: "12345678901", Status: model.AccountClosed}, nil)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Account status does not allow this operation type",
+				"status":    "closed",
+			},
+		},
+		{
+			name: "Account Blocked While Creating Purchase",
+			input: model.Transaction{
+				AccountID:       1,
+				OperationTypeId: 1,
+				Amount:          model.MustParseMoney("-100"),
+			},
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().
+					GetAccount(gomock.Any(), uint(1)).
+					Return(&model.Account{ID: 1, DocumentNumber: "12345678901", Status: model.AccountActive}, nil)
+
+				m.EXPECT().
+					CreateTransaction(gomock.Any(), gomock.Any()).
+					Return(nil, repo.ErrTransactionNotAllowed)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody: map[string]interface{}{
+				"error":     "account status does not allow this transaction",
+				"error_msg": "Account status does not allow this operation type",
+			},
+		},
+		{
+			name: "Credit Voucher Discharge Failure",
+			input: model.Transaction{
+				AccountID:       1,
+				OperationTypeId: 4,
+				Amount:          model.MustParseMoney("100"),
+			},
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().
+					GetAccount(gomock.Any(), uint(1)).
+					Return(&model.Account{ID: 1, DocumentNumber: "12345678901", Status: model.AccountActive}, nil)
+
+				m.EXPECT().
+					DischargeCreditVoucher(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("lock wait timeout exceeded"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"error":     "lock wait timeout exceeded",
+				"error_msg": "Invalid transaction",
+				"msg":       "Not able to create transaction",
 			},
 		},
 	}
@@ -421,6 +785,7 @@ func TestController_CreateTransaction(t *testing.T) {
 
 			mockRepo := mock.NewMockIRepository(ctrl)
 			tt.mockBehavior(mockRepo)
+			expectOperationTypes(mockRepo)
 			controller := NewController(mockRepo)
 
 			w := httptest.NewRecorder()
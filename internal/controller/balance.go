@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+)
+
+// GetAccountBalance method sums up what an account still owes and the credit it still holds, in total
+// and per operation type. Debt is negative and credit positive, like the balance of a transaction.
+// Installments which are not due yet are reported apart as scheduled debt and left out of the net position.
+func (c *Controller) GetAccountBalance(ctx *gin.Context) {
+	accountID, err := strconv.Atoi(ctx.Param("accountId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid accountId",
+			"msg":       "Not able to fetch account balance",
+		})
+		return
+	}
+
+	accountInfo, err := c.repo.GetAccount(ctx.Request.Context(), uint(accountID))
+	if err != nil || accountInfo == nil || accountInfo.ID == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error_msg": "Account not found",
+			"msg":       "Not able to fetch account balance",
+		})
+		return
+	}
+
+	balances, err := c.repo.GetAccountBalances(ctx.Request.Context(), accountInfo.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to fetch account balance",
+		})
+		return
+	}
+
+	names, err := c.operationTypeNames(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to fetch account balance",
+		})
+		return
+	}
+
+	var (
+		outstandingDebt model.Money
+		scheduledDebt   model.Money
+		unappliedCredit model.Money
+		byOperationType = make([]gin.H, 0, len(balances))
+	)
+
+	for _, balance := range balances {
+		outstandingDebt += balance.OutstandingDebt
+		scheduledDebt += balance.ScheduledDebt
+		unappliedCredit += balance.UnappliedCredit
+
+		byOperationType = append(byOperationType, gin.H{
+			"operation_type_id": balance.OperationTypeID,
+			"operation_type":    names[balance.OperationTypeID],
+			"outstanding_debt":  balance.OutstandingDebt,
+			"scheduled_debt":    balance.ScheduledDebt,
+			"unapplied_credit":  balance.UnappliedCredit,
+			"net_position":      balance.OutstandingDebt + balance.UnappliedCredit,
+			"transaction_count": balance.TransactionCount,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"account_id":        accountInfo.ID,
+		"outstanding_debt":  outstandingDebt,
+		"scheduled_debt":    scheduledDebt,
+		"unapplied_credit":  unappliedCredit,
+		"net_position":      outstandingDebt + unappliedCredit,
+		"by_operation_type": byOperationType,
+		"msg":               "Account balance fetched successfully",
+	})
+}
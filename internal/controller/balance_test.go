@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+	"github.com/vamshi1997/pismo-assessment/internal/repo/mock"
+)
+
+func TestController_GetAccountBalance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		accountID      string
+		mockBehavior   func(m *mock.MockIRepository)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:      "Success",
+			accountID: "1",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(1)).Return(&model.Account{ID: 1}, nil)
+				m.EXPECT().GetAccountBalances(gomock.Any(), uint(1)).Return([]repo.OperationTypeBalance{
+					{OperationTypeID: 1, OutstandingDebt: model.MustParseMoney("-120.10"), TransactionCount: 3},
+					{OperationTypeID: 2, OutstandingDebt: model.MustParseMoney("-10"), ScheduledDebt: model.MustParseMoney("-20"), TransactionCount: 4},
+					{OperationTypeID: 3, OutstandingDebt: model.MustParseMoney("-0.2"), TransactionCount: 1},
+					{OperationTypeID: 4, UnappliedCredit: model.MustParseMoney("40.1"), TransactionCount: 2},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"account_id":       float64(1),
+				"outstanding_debt": -130.3,
+				"scheduled_debt":   float64(-20),
+				"unapplied_credit": 40.1,
+				"net_position":     -90.2,
+				"by_operation_type": []interface{}{
+					map[string]interface{}{
+						"operation_type_id": float64(1),
+						"operation_type":    "Normal Purchase",
+						"outstanding_debt":  -120.1,
+						"scheduled_debt":    float64(0),
+						"unapplied_credit":  float64(0),
+						"net_position":      -120.1,
+						"transaction_count": float64(3),
+					},
+					map[string]interface{}{
+						"operation_type_id": float64(2),
+						"operation_type":    "Purchase with Installments",
+						"outstanding_debt":  float64(-10),
+						"scheduled_debt":    float64(-20),
+						"unapplied_credit":  float64(0),
+						"net_position":      float64(-10),
+						"transaction_count": float64(4),
+					},
+					map[string]interface{}{
+						"operation_type_id": float64(3),
+						"operation_type":    "Withdrawal",
+						"outstanding_debt":  -0.2,
+						"scheduled_debt":    float64(0),
+						"unapplied_credit":  float64(0),
+						"net_position":      -0.2,
+						"transaction_count": float64(1),
+					},
+					map[string]interface{}{
+						"operation_type_id": float64(4),
+						"operation_type":    "Credit Voucher",
+						"outstanding_debt":  float64(0),
+						"scheduled_debt":    float64(0),
+						"unapplied_credit":  40.1,
+						"net_position":      40.1,
+						"transaction_count": float64(2),
+					},
+				},
+				"msg": "Account balance fetched successfully",
+			},
+		},
+		{
+			name:      "Account Without Transactions",
+			accountID: "2",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(2)).Return(&model.Account{ID: 2}, nil)
+				m.EXPECT().GetAccountBalances(gomock.Any(), uint(2)).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"outstanding_debt":  float64(0),
+				"scheduled_debt":    float64(0),
+				"unapplied_credit":  float64(0),
+				"net_position":      float64(0),
+				"by_operation_type": []interface{}{},
+			},
+		},
+		{
+			name:           "Invalid Account ID",
+			accountID:      "abc",
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Not valid accountId",
+				"msg":       "Not able to fetch account balance",
+			},
+		},
+		{
+			name:      "Account Not Found",
+			accountID: "9",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(9)).Return(nil, errors.New("record not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Account not found",
+			},
+		},
+		{
+			name:      "Database Error",
+			accountID: "1",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(1)).Return(&model.Account{ID: 1}, nil)
+				m.EXPECT().GetAccountBalances(gomock.Any(), uint(1)).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"error":     "database error",
+				"error_msg": "Internal Server Error",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+			expectOperationTypes(mockRepo)
+			controller := NewController(mockRepo)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/accounts/"+tt.accountID+"/balance", nil)
+			c.Params = []gin.Param{{Key: "accountId", Value: tt.accountID}}
+
+			controller.GetAccountBalance(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key], "mismatch in field: %s", key)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vamshi1997/pismo-assessment/internal/export"
+	"github.com/vamshi1997/pismo-assessment/internal/logging"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+)
+
+// exportBatchSize is how many transactions an export reads from the database at a time
+const exportBatchSize = 500
+
+// ExportTransactions method streams transactions as CSV, NDJSON or OFX (?format=, csv by default),
+// optionally only those of one account (?account_id=) and of an event date range (?from= and ?to=).
+// Rows are read and sent in batches, so an export never holds every transaction in memory. Since the
+// response has started by then, an error in the middle of an export ends the response early.
+func (c *Controller) ExportTransactions(ctx *gin.Context) {
+	format, filter, err := parseExportRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Invalid query parameters",
+			"msg":       "Not able to export transactions",
+		})
+		return
+	}
+
+	if filter.AccountID != 0 {
+		accountInfo, err := c.repo.GetAccount(ctx.Request.Context(), filter.AccountID)
+		if err != nil || accountInfo == nil || accountInfo.ID == 0 {
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"error_msg": "Account not found",
+				"msg":       "Not able to export transactions",
+			})
+			return
+		}
+	}
+
+	writer, err := export.NewWriter(format, ctx.Writer)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to export transactions",
+		})
+		return
+	}
+
+	filename := fmt.Sprintf("transactions-%s.%s", time.Now().In(model.IST).Format("20060102T150405"), format)
+	if filter.AccountID != 0 {
+		filename = fmt.Sprintf("account-%d-%s", filter.AccountID, filename)
+	}
+	// an export can take longer than the server's write timeout, so the deadline is lifted for it
+	_ = http.NewResponseController(ctx.Writer).SetWriteDeadline(time.Time{})
+
+	ctx.Header("Content-Type", format.ContentType())
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	written, err := export.Export(ctx.Request.Context(), c.repo, filter, writer, exportBatchSize)
+	if err != nil {
+		if !ctx.Writer.Written() {
+			ctx.Writer.Header().Del("Content-Type")
+			ctx.Writer.Header().Del("Content-Disposition")
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":     err.Error(),
+				"error_msg": "Internal Server Error",
+				"msg":       "Not able to export transactions",
+			})
+			return
+		}
+		logging.FromContext(ctx.Request.Context()).Error("export stopped", "written", written, "error", err)
+		ctx.Abort()
+	}
+}
+
+// parseExportRequest reads the format and the filters of an export from the query string
+func parseExportRequest(ctx *gin.Context) (export.Format, repo.ExportFilter, error) {
+	var filter repo.ExportFilter
+
+	format := export.CSV
+	if value := ctx.Query("format"); value != "" {
+		parsed, err := export.ParseFormat(value)
+		if err != nil {
+			return format, filter, err
+		}
+		format = parsed
+	}
+
+	if value := ctx.Query("account_id"); value != "" {
+		accountID, err := strconv.Atoi(value)
+		if err != nil || accountID < 1 {
+			return format, filter, fmt.Errorf("invalid account_id %q", value)
+		}
+		filter.AccountID = uint(accountID)
+	}
+
+	if value := ctx.Query("from"); value != "" {
+		from, err := model.ParseEventDateBound(value, false)
+		if err != nil {
+			return format, filter, fmt.Errorf("invalid from %q: %w", value, err)
+		}
+		filter.FromEventDate = from
+	}
+
+	if value := ctx.Query("to"); value != "" {
+		to, err := model.ParseEventDateBound(value, true)
+		if err != nil {
+			return format, filter, fmt.Errorf("invalid to %q: %w", value, err)
+		}
+		filter.ToEventDate = to
+	}
+
+	if filter.FromEventDate != "" && filter.ToEventDate != "" && filter.FromEventDate > filter.ToEventDate {
+		return format, filter, fmt.Errorf("from can not be after to")
+	}
+
+	return format, filter, nil
+}
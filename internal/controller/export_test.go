@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+	"github.com/vamshi1997/pismo-assessment/internal/repo/mock"
+)
+
+func TestController_ExportTransactions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	transactions := []model.Transaction{
+		{ID: 1, AccountID: 1, OperationTypeId: model.NormalPurchase, Amount: model.MustParseMoney("-50"), Balance: model.MustParseMoney("-50"), EventDate: "2025-02-10T10:00:00+05:30"},
+		{ID: 2, AccountID: 1, OperationTypeId: model.CreditVoucher, Amount: model.MustParseMoney("20"), Balance: model.MustParseMoney("0"), EventDate: "2025-02-11T10:00:00+05:30"},
+	}
+	stream := func(_ context.Context, filter repo.ExportFilter, batchSize int, fn func(batch []model.Transaction) error) error {
+		return fn(transactions)
+	}
+
+	tests := []struct {
+		name                string
+		query               string
+		mockBehavior        func(m *mock.MockIRepository)
+		expectedStatus      int
+		expectedContentType string
+		expectedLines       int
+		expectedBody        map[string]interface{}
+	}{
+		{
+			name:  "CSV Of An Account And Date Range",
+			query: "?account_id=1&from=2025-02-01&to=2025-02-28",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(1)).Return(&model.Account{ID: 1}, nil)
+				m.EXPECT().ListOperationTypes(gomock.Any()).Return(model.DefaultOperationTypes, nil)
+				m.EXPECT().
+					StreamTransactions(gomock.Any(), repo.ExportFilter{AccountID: 1, FromEventDate: "2025-02-01T00:00:00", ToEventDate: "2025-02-28T23:59:59.999999"}, exportBatchSize, gomock.Any()).
+					DoAndReturn(stream)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv",
+			expectedLines:       3,
+		},
+		{
+			name:  "NDJSON Of Every Account",
+			query: "?format=ndjson",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().ListOperationTypes(gomock.Any()).Return(model.DefaultOperationTypes, nil)
+				m.EXPECT().StreamTransactions(gomock.Any(), repo.ExportFilter{}, exportBatchSize, gomock.Any()).DoAndReturn(stream)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedLines:       2,
+		},
+		{
+			name:           "Unknown Format",
+			query:          "?format=xlsx",
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error":     `unknown export format "xlsx", expected csv, ndjson or ofx`,
+				"error_msg": "Invalid query parameters",
+				"msg":       "Not able to export transactions",
+			},
+		},
+		{
+			name:           "Range Ends Before It Starts",
+			query:          "?from=2025-03-01&to=2025-02-01",
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error": "from can not be after to",
+			},
+		},
+		{
+			name:  "Account Not Found",
+			query: "?account_id=7",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(7)).Return(nil, errors.New("record not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Account not found",
+			},
+		},
+		{
+			name: "Database Error Before Any Row",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().ListOperationTypes(gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"error":     "database error",
+				"error_msg": "Internal Server Error",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+			controller := NewController(mockRepo)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/transactions/export"+tt.query, nil)
+
+			controller.ExportTransactions(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+				assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+				assert.Len(t, strings.Split(strings.TrimSpace(w.Body.String()), "\n"), tt.expectedLines)
+				return
+			}
+
+			assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key], "mismatch in field: %s", key)
+			}
+		})
+	}
+}
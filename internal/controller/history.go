@@ -0,0 +1,210 @@
+package controller
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// ListAccountTransactions method returns a page of an account's transactions ordered by event date,
+// optionally filtered by operation type, event date range and amount range
+func (c *Controller) ListAccountTransactions(ctx *gin.Context) {
+	accountID, err := strconv.Atoi(ctx.Param("accountId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid accountId",
+			"msg":       "Not able to fetch transactions",
+		})
+		return
+	}
+
+	filter, err := parseTransactionFilter(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Invalid query parameters",
+			"msg":       "Not able to fetch transactions",
+		})
+		return
+	}
+	filter.AccountID = uint(accountID)
+
+	accountInfo, err := c.repo.GetAccount(ctx.Request.Context(), uint(accountID))
+	if err != nil || accountInfo == nil || accountInfo.ID == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error_msg": "Account not found",
+			"msg":       "Not able to fetch transactions",
+		})
+		return
+	}
+
+	// fetch one extra row to know if there is a next page
+	pageSize := filter.Limit
+	filter.Limit = pageSize + 1
+
+	transactions, err := c.repo.ListAccountTransactions(ctx.Request.Context(), filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to fetch transactions",
+		})
+		return
+	}
+
+	nextCursor := ""
+	if len(transactions) > pageSize {
+		transactions = transactions[:pageSize]
+		last := transactions[len(transactions)-1]
+		if nextCursor, err = encodeCursor(last); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error":     err.Error(),
+				"error_msg": "Internal Server Error",
+				"msg":       "Not able to fetch transactions",
+			})
+			return
+		}
+	}
+
+	names, err := c.operationTypeNames(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to fetch transactions",
+		})
+		return
+	}
+
+	items := make([]gin.H, 0, len(transactions))
+	for _, transaction := range transactions {
+		items = append(items, gin.H{
+			"transaction_id":    transaction.ID,
+			"operation_type_id": transaction.OperationTypeId,
+			"operation_type":    names[transaction.OperationTypeId],
+			"amount":            transaction.Amount,
+			"balance":           transaction.Balance,
+			"event_date":        transaction.EventDate,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"account_id":   accountInfo.ID,
+		"transactions": items,
+		"next_cursor":  nextCursor,
+		"msg":          "Transactions fetched successfully",
+	})
+}
+
+// parseTransactionFilter reads the filters and page parameters from the query string
+func parseTransactionFilter(ctx *gin.Context) (repo.TransactionFilter, error) {
+	filter := repo.TransactionFilter{Limit: defaultPageSize}
+
+	if value := ctx.Query("operation_type_id"); value != "" {
+		operationTypeID, err := strconv.Atoi(value)
+		if err != nil || operationTypeID < 1 {
+			return filter, fmt.Errorf("invalid operation_type_id %q", value)
+		}
+		filter.OperationTypeID = uint(operationTypeID)
+	}
+
+	if value := ctx.Query("from"); value != "" {
+		from, err := model.ParseEventDateBound(value, false)
+		if err != nil {
+			return filter, fmt.Errorf("invalid from %q: %w", value, err)
+		}
+		filter.FromEventDate = from
+	}
+
+	if value := ctx.Query("to"); value != "" {
+		to, err := model.ParseEventDateBound(value, true)
+		if err != nil {
+			return filter, fmt.Errorf("invalid to %q: %w", value, err)
+		}
+		filter.ToEventDate = to
+	}
+
+	if value := ctx.Query("min_amount"); value != "" {
+		minAmount, err := model.ParseMoney(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid min_amount %q", value)
+		}
+		filter.MinAmount = &minAmount
+	}
+
+	if value := ctx.Query("max_amount"); value != "" {
+		maxAmount, err := model.ParseMoney(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid max_amount %q", value)
+		}
+		filter.MaxAmount = &maxAmount
+	}
+
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MinAmount > *filter.MaxAmount {
+		return filter, fmt.Errorf("min_amount can not be greater than max_amount")
+	}
+
+	if value := ctx.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			return filter, fmt.Errorf("limit should be between 1 and %d", maxPageSize)
+		}
+		filter.Limit = limit
+	}
+
+	if value := ctx.Query("cursor"); value != "" {
+		eventDate, id, err := decodeCursor(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid cursor")
+		}
+		filter.AfterEventDate = eventDate
+		filter.AfterID = id
+	}
+
+	return filter, nil
+}
+
+// encodeCursor builds an opaque cursor pointing right after the given transaction
+func encodeCursor(transaction model.Transaction) (string, error) {
+	eventDate, err := model.ParseEventDate(transaction.EventDate)
+	if err != nil {
+		return "", err
+	}
+
+	raw := fmt.Sprintf("%s|%d", eventDate.In(model.IST).Format(model.EventDateLayout), transaction.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw)), nil
+}
+
+func decodeCursor(cursor string) (string, uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", 0, err
+	}
+
+	eventDate, id, found := strings.Cut(string(raw), "|")
+	if !found {
+		return "", 0, fmt.Errorf("malformed cursor")
+	}
+	if _, err = time.ParseInLocation(model.EventDateLayout, eventDate, model.IST); err != nil {
+		return "", 0, err
+	}
+
+	parsedID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return "", 0, err
+	}
+	return eventDate, uint(parsedID), nil
+}
@@ -0,0 +1,212 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+	"github.com/vamshi1997/pismo-assessment/internal/repo/mock"
+)
+
+func TestController_ListAccountTransactions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	minAmount := model.MustParseMoney("-100")
+	maxAmount := model.MustParseMoney("-10.5")
+
+	secondPage, _ := encodeCursor(model.Transaction{ID: 2, EventDate: "2025-02-10T10:00:00.5+05:30"})
+
+	tests := []struct {
+		name           string
+		accountID      string
+		query          string
+		mockBehavior   func(m *mock.MockIRepository)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+		expectedCount  int
+		expectedCursor bool
+	}{
+		{
+			name:      "First Page With Next Cursor",
+			accountID: "1",
+			query:     "?limit=2",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(1)).Return(&model.Account{ID: 1}, nil)
+				m.EXPECT().
+					ListAccountTransactions(gomock.Any(), repo.TransactionFilter{AccountID: 1, Limit: 3}).
+					Return([]model.Transaction{
+						{ID: 1, AccountID: 1, OperationTypeId: 1, Amount: model.MustParseMoney("-50"), Balance: model.MustParseMoney("-20"), EventDate: "2025-02-10T09:00:00+05:30"},
+						{ID: 2, AccountID: 1, OperationTypeId: 4, Amount: model.MustParseMoney("30"), Balance: model.MustParseMoney("0"), EventDate: "2025-02-10T10:00:00.5+05:30"},
+						{ID: 3, AccountID: 1, OperationTypeId: 3, Amount: model.MustParseMoney("-10"), Balance: model.MustParseMoney("-10"), EventDate: "2025-02-11T10:00:00+05:30"},
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"account_id":  float64(1),
+				"next_cursor": secondPage,
+				"msg":         "Transactions fetched successfully",
+			},
+			expectedCount:  2,
+			expectedCursor: true,
+		},
+		{
+			name:      "Next Page With Filters",
+			accountID: "1",
+			query:     "?operation_type_id=1&from=2025-02-01&to=2025-02-28&min_amount=-100&max_amount=-10.5&cursor=" + secondPage,
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(1)).Return(&model.Account{ID: 1}, nil)
+				m.EXPECT().
+					ListAccountTransactions(gomock.Any(), repo.TransactionFilter{
+						AccountID:       1,
+						OperationTypeID: 1,
+						FromEventDate:   "2025-02-01T00:00:00",
+						ToEventDate:     "2025-02-28T23:59:59.999999",
+						MinAmount:       &minAmount,
+						MaxAmount:       &maxAmount,
+						AfterEventDate:  "2025-02-10T10:00:00.5",
+						AfterID:         2,
+						Limit:           defaultPageSize + 1,
+					}).
+					Return([]model.Transaction{
+						{ID: 3, AccountID: 1, OperationTypeId: 1, Amount: model.MustParseMoney("-10.5"), Balance: model.MustParseMoney("-10.5"), EventDate: "2025-02-11T10:00:00+05:30"},
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"account_id":  float64(1),
+				"next_cursor": "",
+			},
+			expectedCount: 1,
+		},
+		{
+			name:           "Invalid Account ID",
+			accountID:      "abc",
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Not valid accountId",
+				"msg":       "Not able to fetch transactions",
+			},
+		},
+		{
+			name:           "Invalid Operation Type Filter",
+			accountID:      "1",
+			query:          "?operation_type_id=abc",
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error":     `invalid operation_type_id "abc"`,
+				"error_msg": "Invalid query parameters",
+			},
+		},
+		{
+			name:           "Invalid Amount Range",
+			accountID:      "1",
+			query:          "?min_amount=10&max_amount=5",
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error": "min_amount can not be greater than max_amount",
+			},
+		},
+		{
+			name:           "Invalid Cursor",
+			accountID:      "1",
+			query:          "?cursor=not-a-cursor",
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error": "invalid cursor",
+			},
+		},
+		{
+			name:           "Limit Too Large",
+			accountID:      "1",
+			query:          "?limit=1000",
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error": "limit should be between 1 and 100",
+			},
+		},
+		{
+			name:      "Account Not Found",
+			accountID: "7",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(7)).Return(nil, errors.New("record not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Account not found",
+				"msg":       "Not able to fetch transactions",
+			},
+		},
+		{
+			name:      "Database Error",
+			accountID: "1",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(1)).Return(&model.Account{ID: 1}, nil)
+				m.EXPECT().ListAccountTransactions(gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"error":     "database error",
+				"error_msg": "Internal Server Error",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+			expectOperationTypes(mockRepo)
+			controller := NewController(mockRepo)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/accounts/"+tt.accountID+"/transactions"+tt.query, nil)
+			c.Params = []gin.Param{{Key: "accountId", Value: tt.accountID}}
+
+			controller.ListAccountTransactions(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key], "mismatch in field: %s", key)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				transactions := response["transactions"].([]interface{})
+				assert.Len(t, transactions, tt.expectedCount)
+				if tt.expectedCursor {
+					assert.NotEmpty(t, response["next_cursor"])
+				}
+			}
+		})
+	}
+}
+
+func TestTransactionCursor(t *testing.T) {
+	cursor, err := encodeCursor(model.Transaction{ID: 42, EventDate: "2025-02-12T19:58:03.123456+05:30"})
+	assert.NoError(t, err)
+
+	eventDate, id, err := decodeCursor(cursor)
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-02-12T19:58:03.123456", eventDate)
+	assert.Equal(t, uint(42), id)
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+)
+
+const (
+	installmentPaid     = "paid"
+	installmentDue      = "due"
+	installmentUpcoming = "upcoming"
+)
+
+// GetInstallmentSchedule method returns the installment schedule of a purchase with installments,
+// with the status of each installment and what is still left to pay
+func (c *Controller) GetInstallmentSchedule(ctx *gin.Context) {
+	transactionID, err := strconv.Atoi(ctx.Param("transactionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid transactionId",
+			"msg":       "Not able to fetch installments",
+		})
+		return
+	}
+
+	purchase, err := c.repo.GetTransaction(ctx.Request.Context(), uint(transactionID))
+	if err != nil || purchase == nil || purchase.ID == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error_msg": "Transaction not found",
+			"msg":       "Not able to fetch installments",
+		})
+		return
+	}
+
+	if purchase.Installments == 0 {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error_msg": "Transaction is not a purchase with installments",
+			"msg":       "Not able to fetch installments",
+		})
+		return
+	}
+
+	installments, err := c.repo.ListInstallments(ctx.Request.Context(), purchase.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to fetch installments",
+		})
+		return
+	}
+
+	var (
+		now                  = time.Now()
+		remainingAmount      model.Money
+		remainingInstallment int
+		schedule             = make([]gin.H, 0, len(installments))
+	)
+
+	for _, installment := range installments {
+		status := installmentPaid
+		if installment.Balance < 0 {
+			remainingAmount += installment.Balance
+			remainingInstallment++
+
+			status = installmentUpcoming
+			if installment.DueDate == nil || !installment.DueDate.After(now) {
+				status = installmentDue
+			}
+		}
+
+		schedule = append(schedule, gin.H{
+			"transaction_id":     installment.ID,
+			"installment_number": installment.InstallmentNumber,
+			"amount":             installment.Amount,
+			"balance":            installment.Balance,
+			"due_date":           installment.DueDate,
+			"status":             status,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"transaction_id":         purchase.ID,
+		"account_id":             purchase.AccountID,
+		"amount":                 purchase.Amount,
+		"installments":           purchase.Installments,
+		"remaining_installments": remainingInstallment,
+		"remaining_amount":       remainingAmount,
+		"schedule":               schedule,
+		"msg":                    "Installments fetched successfully",
+	})
+}
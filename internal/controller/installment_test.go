@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo/mock"
+)
+
+func TestController_GetInstallmentSchedule(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	purchaseID := uint(1)
+	past := time.Now().AddDate(0, -1, 0)
+	today := time.Now().Add(-time.Minute)
+	future := time.Now().AddDate(0, 1, 0)
+
+	tests := []struct {
+		name             string
+		transactionID    string
+		mockBehavior     func(m *mock.MockIRepository)
+		expectedStatus   int
+		expectedBody     map[string]interface{}
+		expectedStatuses []string
+	}{
+		{
+			name:          "Schedule With Paid Due And Upcoming Installments",
+			transactionID: "1",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetTransaction(gomock.Any(), uint(1)).Return(&model.Transaction{
+					ID:              1,
+					AccountID:       1,
+					OperationTypeId: 2,
+					Amount:          model.MustParseMoney("-300"),
+					Installments:    3,
+				}, nil)
+				m.EXPECT().ListInstallments(gomock.Any(), uint(1)).Return([]model.Transaction{
+					{ID: 2, InstallmentNumber: 1, Amount: model.MustParseMoney("-100"), Balance: model.MustParseMoney("0"), ParentTransactionID: &purchaseID, DueDate: &past},
+					{ID: 3, InstallmentNumber: 2, Amount: model.MustParseMoney("-100"), Balance: model.MustParseMoney("-40"), ParentTransactionID: &purchaseID, DueDate: &today},
+					{ID: 4, InstallmentNumber: 3, Amount: model.MustParseMoney("-100"), Balance: model.MustParseMoney("-100"), ParentTransactionID: &purchaseID, DueDate: &future},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"transaction_id":         float64(1),
+				"account_id":             float64(1),
+				"amount":                 float64(-300),
+				"installments":           float64(3),
+				"remaining_installments": float64(2),
+				"remaining_amount":       float64(-140),
+				"msg":                    "Installments fetched successfully",
+			},
+			expectedStatuses: []string{"paid", "due", "upcoming"},
+		},
+		{
+			name:          "Not A Purchase With Installments",
+			transactionID: "5",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetTransaction(gomock.Any(), uint(5)).Return(&model.Transaction{ID: 5, OperationTypeId: 1}, nil)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Transaction is not a purchase with installments",
+				"msg":       "Not able to fetch installments",
+			},
+		},
+		{
+			name:           "Invalid Transaction ID",
+			transactionID:  "abc",
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Not valid transactionId",
+			},
+		},
+		{
+			name:          "Transaction Not Found",
+			transactionID: "9",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetTransaction(gomock.Any(), uint(9)).Return(nil, errors.New("record not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Transaction not found",
+			},
+		},
+		{
+			name:          "Database Error",
+			transactionID: "1",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetTransaction(gomock.Any(), uint(1)).Return(&model.Transaction{ID: 1, Installments: 3}, nil)
+				m.EXPECT().ListInstallments(gomock.Any(), uint(1)).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"error":     "database error",
+				"error_msg": "Internal Server Error",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+			controller := NewController(mockRepo)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/transactions/"+tt.transactionID+"/installments", nil)
+			c.Params = []gin.Param{{Key: "transactionId", Value: tt.transactionID}}
+
+			controller.GetInstallmentSchedule(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key], "mismatch in field: %s", key)
+			}
+
+			if tt.expectedStatuses != nil {
+				schedule := response["schedule"].([]interface{})
+				assert.Len(t, schedule, len(tt.expectedStatuses))
+				for i, installment := range schedule {
+					assert.Equal(t, tt.expectedStatuses[i], installment.(map[string]interface{})["status"])
+				}
+			}
+		})
+	}
+}
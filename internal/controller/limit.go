@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"gorm.io/gorm"
+)
+
+// creditLimitRequest is the body of a credit limit change
+type creditLimitRequest struct {
+	CreditLimit *model.Money `json:"credit_limit" binding:"required"`
+	Reason      string       `json:"reason" binding:"max=255"`
+}
+
+// GetAccountLimit method returns the credit limit of an account and how much of it is still available
+func (c *Controller) GetAccountLimit(ctx *gin.Context) {
+	accountID, err := strconv.Atoi(ctx.Param("accountId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid accountId",
+			"msg":       "Not able to fetch credit limit",
+		})
+		return
+	}
+
+	accountInfo, err := c.repo.GetAccount(ctx.Request.Context(), uint(accountID))
+	if err != nil || accountInfo == nil || accountInfo.ID == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error_msg": "Account not found",
+			"msg":       "Not able to fetch credit limit",
+		})
+		return
+	}
+
+	c.respondWithLimit(ctx, accountInfo, "Credit limit fetched successfully", "Not able to fetch credit limit")
+}
+
+// UpdateAccountLimit method sets a new credit limit for an account. The limit may be set below what the
+// account already owes, in which case new purchases and withdrawals are refused until debt is paid.
+func (c *Controller) UpdateAccountLimit(ctx *gin.Context) {
+	var request creditLimitRequest
+
+	accountID, err := strconv.Atoi(ctx.Param("accountId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid accountId",
+			"msg":       "Not able to update credit limit",
+		})
+		return
+	}
+
+	if err = ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Invalid request body",
+			"msg":       "Not able to update credit limit",
+		})
+		return
+	}
+
+	if *request.CreditLimit < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error_msg": "Credit limit can not be negative",
+			"msg":       "Not able to update credit limit",
+		})
+		return
+	}
+
+	c.changeLimit(ctx, uint(accountID), request.CreditLimit, request.Reason,
+		"Credit limit updated successfully", "Not able to update credit limit")
+}
+
+// RemoveAccountLimit method removes the credit limit of an account, so its debits are no longer limited
+func (c *Controller) RemoveAccountLimit(ctx *gin.Context) {
+	accountID, err := strconv.Atoi(ctx.Param("accountId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid accountId",
+			"msg":       "Not able to remove credit limit",
+		})
+		return
+	}
+
+	c.changeLimit(ctx, uint(accountID), nil, ctx.Query("reason"),
+		"Credit limit removed successfully", "Not able to remove credit limit")
+}
+
+// ListAccountLimitChanges method returns every change made to the credit limit of an account, oldest first
+func (c *Controller) ListAccountLimitChanges(ctx *gin.Context) {
+	accountID, err := strconv.Atoi(ctx.Param("accountId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid accountId",
+			"msg":       "Not able to fetch credit limit history",
+		})
+		return
+	}
+
+	accountInfo, err := c.repo.GetAccount(ctx.Request.Context(), uint(accountID))
+	if err != nil || accountInfo == nil || accountInfo.ID == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error_msg": "Account not found",
+			"msg":       "Not able to fetch credit limit history",
+		})
+		return
+	}
+
+	changes, err := c.repo.ListCreditLimitChanges(ctx.Request.Context(), accountInfo.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to fetch credit limit history",
+		})
+		return
+	}
+
+	items := make([]gin.H, 0, len(changes))
+	for _, change := range changes {
+		items = append(items, gin.H{
+			"id":             change.ID,
+			"previous_limit": change.PreviousLimit,
+			"new_limit":      change.NewLimit,
+			"reason":         change.Reason,
+			"changed_at":     change.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"account_id": accountInfo.ID,
+		"changes":    items,
+		"msg":        "Credit limit history fetched successfully",
+	})
+}
+
+// changeLimit stores the new limit of an account and responds with the resulting available limit
+func (c *Controller) changeLimit(ctx *gin.Context, accountID uint, creditLimit *model.Money, reason, successMsg, failureMsg string) {
+	accountInfo, err := c.repo.UpdateCreditLimit(ctx.Request.Context(), accountID, creditLimit, reason)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error_msg": "Account not found",
+			"msg":       failureMsg,
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       failureMsg,
+		})
+		return
+	}
+
+	c.respondWithLimit(ctx, accountInfo, successMsg, failureMsg)
+}
+
+// respondWithLimit computes the available limit of the account and writes it in the response
+func (c *Controller) respondWithLimit(ctx *gin.Context, accountInfo *model.Account, successMsg, failureMsg string) {
+	netBalance, err := c.repo.GetAccountNetBalance(ctx.Request.Context(), accountInfo.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       failureMsg,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"account_id":      accountInfo.ID,
+		"credit_limit":    accountInfo.CreditLimit,
+		"available_limit": accountInfo.AvailableLimitFor(netBalance),
+		"net_balance":     netBalance,
+		"msg":             successMsg,
+	})
+}
@@ -0,0 +1,345 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo/mock"
+	"gorm.io/gorm"
+)
+
+func TestController_GetAccountLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limit := model.MustParseMoney("1000")
+
+	tests := []struct {
+		name           string
+		accountID      string
+		mockBehavior   func(m *mock.MockIRepository)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:      "Account With Limit",
+			accountID: "1",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(1)).Return(&model.Account{ID: 1, CreditLimit: &limit}, nil)
+				m.EXPECT().GetAccountNetBalance(gomock.Any(), uint(1)).Return(model.MustParseMoney("-300"), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"account_id":      float64(1),
+				"credit_limit":    float64(1000),
+				"available_limit": float64(700),
+				"net_balance":     float64(-300),
+				"msg":             "Credit limit fetched successfully",
+			},
+		},
+		{
+			name:      "Account Without Limit",
+			accountID: "2",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(2)).Return(&model.Account{ID: 2}, nil)
+				m.EXPECT().GetAccountNetBalance(gomock.Any(), uint(2)).Return(model.MustParseMoney("-300"), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"account_id":      float64(2),
+				"credit_limit":    nil,
+				"available_limit": nil,
+			},
+		},
+		{
+			name:           "Invalid Account ID",
+			accountID:      "abc",
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Not valid accountId",
+				"msg":       "Not able to fetch credit limit",
+			},
+		},
+		{
+			name:      "Account Not Found",
+			accountID: "7",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(7)).Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Account not found",
+			},
+		},
+		{
+			name:      "Database Error",
+			accountID: "1",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(1)).Return(&model.Account{ID: 1, CreditLimit: &limit}, nil)
+				m.EXPECT().GetAccountNetBalance(gomock.Any(), uint(1)).Return(model.Money(0), errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"error":     "database error",
+				"error_msg": "Internal Server Error",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+			controller := NewController(mockRepo)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/accounts/"+tt.accountID+"/limit", nil)
+			c.Params = []gin.Param{{Key: "accountId", Value: tt.accountID}}
+
+			controller.GetAccountLimit(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key], "mismatch in field: %s", key)
+			}
+		})
+	}
+}
+
+func TestController_UpdateAccountLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newLimit := model.MustParseMoney("500")
+
+	tests := []struct {
+		name           string
+		accountID      string
+		body           string
+		mockBehavior   func(m *mock.MockIRepository)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:      "Lower Limit Below Debt",
+			accountID: "1",
+			body:      `{"credit_limit": 500, "reason": "risk review"}`,
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().
+					UpdateCreditLimit(gomock.Any(), uint(1), &newLimit, "risk review").
+					Return(&model.Account{ID: 1, CreditLimit: &newLimit}, nil)
+				m.EXPECT().GetAccountNetBalance(gomock.Any(), uint(1)).Return(model.MustParseMoney("-650"), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"account_id":      float64(1),
+				"credit_limit":    float64(500),
+				"available_limit": float64(-150),
+				"msg":             "Credit limit updated successfully",
+			},
+		},
+		{
+			name:           "Missing Limit",
+			accountID:      "1",
+			body:           `{"reason": "risk review"}`,
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Invalid request body",
+				"msg":       "Not able to update credit limit",
+			},
+		},
+		{
+			name:           "Negative Limit",
+			accountID:      "1",
+			body:           `{"credit_limit": -1}`,
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Credit limit can not be negative",
+			},
+		},
+		{
+			name:      "Account Not Found",
+			accountID: "7",
+			body:      `{"credit_limit": 500}`,
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().UpdateCreditLimit(gomock.Any(), uint(7), &newLimit, "").Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Account not found",
+				"msg":       "Not able to update credit limit",
+			},
+		},
+		{
+			name:      "Database Error",
+			accountID: "1",
+			body:      `{"credit_limit": 500}`,
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().UpdateCreditLimit(gomock.Any(), uint(1), &newLimit, "").Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"error":     "database error",
+				"error_msg": "Internal Server Error",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+			controller := NewController(mockRepo)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/accounts/"+tt.accountID+"/limit", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = []gin.Param{{Key: "accountId", Value: tt.accountID}}
+
+			controller.UpdateAccountLimit(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key], "mismatch in field: %s", key)
+			}
+		})
+	}
+}
+
+func TestController_RemoveAccountLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockIRepository(ctrl)
+	mockRepo.EXPECT().
+		UpdateCreditLimit(gomock.Any(), uint(1), nil, "closed by support").
+		Return(&model.Account{ID: 1}, nil)
+	mockRepo.EXPECT().GetAccountNetBalance(gomock.Any(), uint(1)).Return(model.MustParseMoney("-650"), nil)
+	controller := NewController(mockRepo)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodDelete, "/accounts/1/limit?reason=closed+by+support", nil)
+	c.Params = []gin.Param{{Key: "accountId", Value: "1"}}
+
+	controller.RemoveAccountLimit(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Nil(t, response["credit_limit"])
+	assert.Nil(t, response["available_limit"])
+	assert.Equal(t, "Credit limit removed successfully", response["msg"])
+}
+
+func TestController_ListAccountLimitChanges(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	first := model.MustParseMoney("1000")
+	second := model.MustParseMoney("500")
+	changedAt := time.Date(2025, 2, 10, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		mockBehavior   func(m *mock.MockIRepository)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+		expectedCount  int
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(1)).Return(&model.Account{ID: 1, CreditLimit: &second}, nil)
+				m.EXPECT().ListCreditLimitChanges(gomock.Any(), uint(1)).Return([]model.CreditLimitChange{
+					{ID: 1, AccountID: 1, NewLimit: &first, Reason: "account created", Model: gorm.Model{CreatedAt: changedAt}},
+					{ID: 2, AccountID: 1, PreviousLimit: &first, NewLimit: &second, Reason: "risk review", Model: gorm.Model{CreatedAt: changedAt}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"account_id": float64(1),
+				"msg":        "Credit limit history fetched successfully",
+			},
+			expectedCount: 2,
+		},
+		{
+			name: "Database Error",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(1)).Return(&model.Account{ID: 1}, nil)
+				m.EXPECT().ListCreditLimitChanges(gomock.Any(), uint(1)).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"error":     "database error",
+				"error_msg": "Internal Server Error",
+				"msg":       "Not able to fetch credit limit history",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+			controller := NewController(mockRepo)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/accounts/1/limit/history", nil)
+			c.Params = []gin.Param{{Key: "accountId", Value: "1"}}
+
+			controller.ListAccountLimitChanges(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key], "mismatch in field: %s", key)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				changes := response["changes"].([]interface{})
+				assert.Len(t, changes, tt.expectedCount)
+				assert.Nil(t, changes[0].(map[string]interface{})["previous_limit"])
+				assert.Equal(t, float64(1000), changes[1].(map[string]interface{})["previous_limit"])
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListOperationTypes method returns every operation type with the rules its transactions follow
+func (c *Controller) ListOperationTypes(ctx *gin.Context) {
+	operationTypes, err := c.repo.ListOperationTypes(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to fetch operation types",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"operation_types": operationTypes,
+		"msg":             "Operation types fetched successfully",
+	})
+}
+
+// operationTypeNames maps operation type IDs to their descriptions
+func (c *Controller) operationTypeNames(ctx *gin.Context) (map[uint]string, error) {
+	operationTypes, err := c.repo.ListOperationTypes(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[uint]string, len(operationTypes))
+	for _, operationType := range operationTypes {
+		names[operationType.ID] = operationType.Description
+	}
+	return names, nil
+}
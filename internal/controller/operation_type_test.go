@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo/mock"
+)
+
+func TestController_ListOperationTypes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		mockBehavior   func(m *mock.MockIRepository)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().ListOperationTypes(gomock.Any()).Return(model.DefaultOperationTypes[:2], nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"operation_types": []interface{}{
+					map[string]interface{}{
+						"id":                  float64(1),
+						"description":         "Normal Purchase",
+						"amount_sign":         float64(-1),
+						"discharge_eligible":  true,
+						"allows_installments": false,
+						"internal":            false,
+					},
+					map[string]interface{}{
+						"id":                  float64(2),
+						"description":         "Purchase with Installments",
+						"amount_sign":         float64(-1),
+						"discharge_eligible":  true,
+						"allows_installments": true,
+						"internal":            false,
+					},
+				},
+				"msg": "Operation types fetched successfully",
+			},
+		},
+		{
+			name: "Database Error",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().ListOperationTypes(gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"error":     "database error",
+				"error_msg": "Internal Server Error",
+				"msg":       "Not able to fetch operation types",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+			controller := NewController(mockRepo)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/operation-types", nil)
+
+			controller.ListOperationTypes(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key], "mismatch in field: %s", key)
+			}
+		})
+	}
+}
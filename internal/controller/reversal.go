@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+	"gorm.io/gorm"
+)
+
+// reversalRequest is the optional body of a reversal. Without an amount the whole amount left to
+// reverse is reversed.
+type reversalRequest struct {
+	Amount *model.Money `json:"amount"`
+}
+
+// ReverseTransaction method reverses a purchase or withdrawal fully or partially, and gives back any
+// credit vouchers already paid on it
+func (c *Controller) ReverseTransaction(ctx *gin.Context) {
+	var request reversalRequest
+
+	transactionID, err := strconv.Atoi(ctx.Param("transactionId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid transactionId",
+			"msg":       "Not able to reverse transaction",
+		})
+		return
+	}
+
+	if ctx.Request.ContentLength != 0 {
+		if err = ctx.ShouldBindJSON(&request); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":     err.Error(),
+				"error_msg": "Invalid request body",
+				"msg":       "Not able to reverse transaction",
+			})
+			return
+		}
+	}
+
+	reversal, err := c.repo.ReverseTransaction(ctx.Request.Context(), uint(transactionID), request.Amount)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Transaction not found",
+			"msg":       "Not able to reverse transaction",
+		})
+		return
+	case errors.Is(err, repo.ErrNotReversible):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Only purchases, withdrawals and single installments can be reversed",
+			"msg":       "Not able to reverse transaction",
+		})
+		return
+	case errors.Is(err, repo.ErrReversalExceedsOriginal), errors.Is(err, repo.ErrInvalidReversalAmount):
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Invalid reversal amount",
+			"msg":       "Not able to reverse transaction",
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to reverse transaction",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"msg":                     "transaction reversed successfully",
+		"transaction_id":          reversal.ID,
+		"reversed_transaction_id": transactionID,
+		"account_id":              reversal.AccountID,
+		"operation_type_id":       reversal.OperationTypeId,
+		"amount":                  reversal.Amount,
+	})
+}
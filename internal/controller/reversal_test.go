@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+	"github.com/vamshi1997/pismo-assessment/internal/repo/mock"
+	"gorm.io/gorm"
+)
+
+func TestController_ReverseTransaction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	originalID := uint(1)
+	partialAmount := model.MustParseMoney("30")
+
+	tests := []struct {
+		name           string
+		transactionID  string
+		body           string
+		mockBehavior   func(m *mock.MockIRepository)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:          "Full Reversal",
+			transactionID: "1",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().
+					ReverseTransaction(gomock.Any(), uint(1), (*model.Money)(nil)).
+					Return(&model.Transaction{
+						ID:                    2,
+						AccountID:             1,
+						OperationTypeId:       model.Reversal,
+						Amount:                model.MustParseMoney("100"),
+						ReversedTransactionID: &originalID,
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"msg":                     "transaction reversed successfully",
+				"transaction_id":          float64(2),
+				"reversed_transaction_id": float64(1),
+				"account_id":              float64(1),
+				"operation_type_id":       float64(5),
+				"amount":                  float64(100),
+			},
+		},
+		{
+			name:          "Partial Reversal",
+			transactionID: "1",
+			body:          `{"amount": 30}`,
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().
+					ReverseTransaction(gomock.Any(), uint(1), &partialAmount).
+					Return(&model.Transaction{
+						ID:                    3,
+						AccountID:             1,
+						OperationTypeId:       model.Reversal,
+						Amount:                partialAmount,
+						ReversedTransactionID: &originalID,
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"transaction_id": float64(3),
+				"amount":         float64(30),
+			},
+		},
+		{
+			name:          "Reversal Of A Reversal",
+			transactionID: "2",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().ReverseTransaction(gomock.Any(), uint(2), gomock.Any()).Return(nil, repo.ErrNotReversible)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody: map[string]interface{}{
+				"error":     "transaction can not be reversed",
+				"error_msg": "Only purchases, withdrawals and single installments can be reversed",
+				"msg":       "Not able to reverse transaction",
+			},
+		},
+		{
+			name:          "Reversal Exceeds Original",
+			transactionID: "1",
+			body:          `{"amount": 500}`,
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().ReverseTransaction(gomock.Any(), uint(1), gomock.Any()).Return(nil, repo.ErrReversalExceedsOriginal)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error":     "reversal amount exceeds the amount left to reverse",
+				"error_msg": "Invalid reversal amount",
+			},
+		},
+		{
+			name:          "Transaction Not Found",
+			transactionID: "99",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().ReverseTransaction(gomock.Any(), uint(99), gomock.Any()).Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Transaction not found",
+			},
+		},
+		{
+			name:           "Invalid Body",
+			transactionID:  "1",
+			body:           `{"amount": "abc"}`,
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Invalid request body",
+			},
+		},
+		{
+			name:           "Invalid Transaction ID",
+			transactionID:  "abc",
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Not valid transactionId",
+			},
+		},
+		{
+			name:          "Database Error",
+			transactionID: "1",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().ReverseTransaction(gomock.Any(), uint(1), gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"error":     "database error",
+				"error_msg": "Internal Server Error",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+			controller := NewController(mockRepo)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/transactions/"+tt.transactionID+"/reversal", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = []gin.Param{{Key: "transactionId", Value: tt.transactionID}}
+
+			controller.ReverseTransaction(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key], "mismatch in field: %s", key)
+			}
+		})
+	}
+}
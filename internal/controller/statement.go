@@ -0,0 +1,314 @@
+package controller
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"gorm.io/gorm"
+)
+
+const csvContentType = "text/csv"
+
+// billingCycleRequest is the body of a billing cycle change
+type billingCycleRequest struct {
+	BillingDay uint `json:"billing_day" binding:"required"`
+}
+
+// statementColumns are the CSV columns of a statement, in the order statementRow writes them
+var statementColumns = []string{
+	"statement_id", "account_id", "period_start", "period_end", "due_date", "opening_balance", "purchases",
+	"withdrawals", "vouchers", "reversals", "interest", "fees", "other_debits", "other_credits", "closing_balance",
+	"transaction_count",
+}
+
+// statementLineColumns are the CSV columns of a statement line, in the order GetStatement writes them
+var statementLineColumns = []string{
+	"transaction_id", "operation_type_id", "operation_type", "amount", "billed_at", "event_date", "installment_number",
+}
+
+// ListStatements method returns the statements of an account, latest cycle first, as JSON or as CSV when
+// asked for with ?format=csv or an Accept: text/csv header
+func (c *Controller) ListStatements(ctx *gin.Context) {
+	accountID, err := strconv.Atoi(ctx.Param("accountId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid accountId",
+			"msg":       "Not able to fetch statements",
+		})
+		return
+	}
+
+	accountInfo, err := c.repo.GetAccount(ctx.Request.Context(), uint(accountID))
+	if err != nil || accountInfo == nil || accountInfo.ID == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error_msg": "Account not found",
+			"msg":       "Not able to fetch statements",
+		})
+		return
+	}
+
+	statements, err := c.repo.ListStatements(ctx.Request.Context(), accountInfo.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to fetch statements",
+		})
+		return
+	}
+
+	if wantsCSV(ctx) {
+		rows := make([][]string, 0, len(statements))
+		for _, statement := range statements {
+			rows = append(rows, statementRow(statement))
+		}
+		writeCSV(ctx, fmt.Sprintf("account-%d-statements.csv", accountInfo.ID), statementColumns, rows)
+		return
+	}
+
+	items := make([]gin.H, 0, len(statements))
+	for _, statement := range statements {
+		items = append(items, statementResponse(statement))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"account_id":  accountInfo.ID,
+		"billing_day": accountInfo.BillingDay,
+		"statements":  items,
+		"msg":         "Statements fetched successfully",
+	})
+}
+
+// GetStatement method returns one statement of an account with the transactions billed in its cycle, as
+// JSON or as CSV with one row per transaction
+func (c *Controller) GetStatement(ctx *gin.Context) {
+	accountID, err := strconv.Atoi(ctx.Param("accountId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid accountId",
+			"msg":       "Not able to fetch statement",
+		})
+		return
+	}
+
+	statementID, err := strconv.Atoi(ctx.Param("statementId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid statementId",
+			"msg":       "Not able to fetch statement",
+		})
+		return
+	}
+
+	statement, err := c.repo.GetStatement(ctx.Request.Context(), uint(accountID), uint(statementID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error_msg": "Statement not found",
+			"msg":       "Not able to fetch statement",
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to fetch statement",
+		})
+		return
+	}
+
+	transactions, err := c.repo.ListStatementTransactions(ctx.Request.Context(), *statement)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to fetch statement",
+		})
+		return
+	}
+
+	names, err := c.operationTypeNames(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to fetch statement",
+		})
+		return
+	}
+
+	if wantsCSV(ctx) {
+		rows := make([][]string, 0, len(transactions))
+		for _, transaction := range transactions {
+			rows = append(rows, []string{
+				strconv.FormatUint(uint64(transaction.ID), 10),
+				strconv.FormatUint(uint64(transaction.OperationTypeId), 10),
+				names[transaction.OperationTypeId],
+				transaction.Amount.String(),
+				billedAt(transaction),
+				transaction.EventDate,
+				strconv.FormatUint(uint64(transaction.InstallmentNumber), 10),
+			})
+		}
+		writeCSV(ctx, fmt.Sprintf("account-%d-statement-%d.csv", statement.AccountID, statement.ID), statementLineColumns, rows)
+		return
+	}
+
+	lines := make([]gin.H, 0, len(transactions))
+	for _, transaction := range transactions {
+		lines = append(lines, gin.H{
+			"transaction_id":     transaction.ID,
+			"operation_type_id":  transaction.OperationTypeId,
+			"operation_type":     names[transaction.OperationTypeId],
+			"amount":             transaction.Amount,
+			"billed_at":          billedAt(transaction),
+			"event_date":         transaction.EventDate,
+			"installment_number": transaction.InstallmentNumber,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"statement":    statementResponse(*statement),
+		"transactions": lines,
+		"msg":          "Statement fetched successfully",
+	})
+}
+
+// UpdateBillingCycle method changes the day of the month the billing cycle of an account closes on.
+// Statements already generated are kept, the running cycle closes on the new day.
+func (c *Controller) UpdateBillingCycle(ctx *gin.Context) {
+	var request billingCycleRequest
+
+	accountID, err := strconv.Atoi(ctx.Param("accountId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid accountId",
+			"msg":       "Not able to update billing cycle",
+		})
+		return
+	}
+
+	if err = ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Invalid request body",
+			"msg":       "Not able to update billing cycle",
+		})
+		return
+	}
+
+	if !model.IsValidBillingDay(request.BillingDay) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error_msg": fmt.Sprintf("Billing day should be between %d and %d", model.MinBillingDay, model.MaxBillingDay),
+			"msg":       "Not able to update billing cycle",
+		})
+		return
+	}
+
+	accountInfo, err := c.repo.UpdateBillingDay(ctx.Request.Context(), uint(accountID), request.BillingDay)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error_msg": "Account not found",
+			"msg":       "Not able to update billing cycle",
+		})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to update billing cycle",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"account_id":       accountInfo.ID,
+		"billing_day":      accountInfo.BillingDay,
+		"next_cycle_close": model.NextCycleClose(time.Now(), accountInfo.BillingDay),
+		"msg":              "Billing cycle updated successfully",
+	})
+}
+
+// wantsCSV tells if the client asked for CSV instead of JSON
+func wantsCSV(ctx *gin.Context) bool {
+	if format := ctx.Query("format"); format != "" {
+		return strings.EqualFold(format, "csv")
+	}
+	return strings.Contains(ctx.GetHeader("Accept"), csvContentType)
+}
+
+// writeCSV responds with a CSV attachment made of the header and the rows
+func writeCSV(ctx *gin.Context, filename string, header []string, rows [][]string) {
+	var body strings.Builder
+	writer := csv.NewWriter(&body)
+	_ = writer.Write(header)
+	_ = writer.WriteAll(rows)
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.Data(http.StatusOK, csvContentType+"; charset=utf-8", []byte(body.String()))
+}
+
+// statementResponse lists the fields of a statement shown to clients
+func statementResponse(statement model.Statement) gin.H {
+	return gin.H{
+		"statement_id":      statement.ID,
+		"account_id":        statement.AccountID,
+		"period_start":      statement.PeriodStart.In(model.IST),
+		"period_end":        statement.PeriodEnd.In(model.IST),
+		"due_date":          statement.DueDate.In(model.IST),
+		"opening_balance":   statement.OpeningBalance,
+		"purchases":         statement.Purchases,
+		"withdrawals":       statement.Withdrawals,
+		"vouchers":          statement.Vouchers,
+		"reversals":         statement.Reversals,
+		"interest":          statement.Interest,
+		"fees":              statement.Fees,
+		"other_debits":      statement.OtherDebits,
+		"other_credits":     statement.OtherCredits,
+		"closing_balance":   statement.ClosingBalance,
+		"transaction_count": statement.TransactionCount,
+	}
+}
+
+// statementRow formats a statement as a CSV row following statementColumns
+func statementRow(statement model.Statement) []string {
+	return []string{
+		strconv.FormatUint(uint64(statement.ID), 10),
+		strconv.FormatUint(uint64(statement.AccountID), 10),
+		statement.PeriodStart.In(model.IST).Format(time.RFC3339),
+		statement.PeriodEnd.In(model.IST).Format(time.RFC3339),
+		statement.DueDate.In(model.IST).Format(time.RFC3339),
+		statement.OpeningBalance.String(),
+		statement.Purchases.String(),
+		statement.Withdrawals.String(),
+		statement.Vouchers.String(),
+		statement.Reversals.String(),
+		statement.Interest.String(),
+		statement.Fees.String(),
+		statement.OtherDebits.String(),
+		statement.OtherCredits.String(),
+		statement.ClosingBalance.String(),
+		strconv.FormatInt(statement.TransactionCount, 10),
+	}
+}
+
+// billedAt returns when a transaction was billed: installments when they are due, other transactions at
+// their event date
+func billedAt(transaction model.Transaction) string {
+	if transaction.DueDate != nil {
+		return transaction.DueDate.In(model.IST).Format(model.EventDateLayout)
+	}
+	return transaction.EventDate
+}
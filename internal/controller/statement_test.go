@@ -0,0 +1,410 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo/mock"
+	"gorm.io/gorm"
+)
+
+func testStatement() model.Statement {
+	return model.Statement{
+		ID:               3,
+		AccountID:        1,
+		PeriodStart:      time.Date(2025, 2, 1, 0, 0, 0, 0, model.IST),
+		PeriodEnd:        time.Date(2025, 3, 1, 0, 0, 0, 0, model.IST),
+		DueDate:          time.Date(2025, 3, 11, 0, 0, 0, 0, model.IST),
+		OpeningBalance:   model.MustParseMoney("-20"),
+		Purchases:        model.MustParseMoney("-50"),
+		Vouchers:         model.MustParseMoney("30"),
+		ClosingBalance:   model.MustParseMoney("-40"),
+		TransactionCount: 2,
+	}
+}
+
+func TestController_ListStatements(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		accountID      string
+		query          string
+		accept         string
+		mockBehavior   func(m *mock.MockIRepository)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+		expectedCSV    [][]string
+	}{
+		{
+			name:      "JSON",
+			accountID: "1",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(1)).Return(&model.Account{ID: 1, BillingDay: 1}, nil)
+				m.EXPECT().ListStatements(gomock.Any(), uint(1)).Return([]model.Statement{testStatement()}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"account_id":  float64(1),
+				"billing_day": float64(1),
+				"statements": []interface{}{
+					map[string]interface{}{
+						"statement_id":      float64(3),
+						"account_id":        float64(1),
+						"period_start":      "2025-02-01T00:00:00+05:30",
+						"period_end":        "2025-03-01T00:00:00+05:30",
+						"due_date":          "2025-03-11T00:00:00+05:30",
+						"opening_balance":   float64(-20),
+						"purchases":         float64(-50),
+						"withdrawals":       float64(0),
+						"vouchers":          float64(30),
+						"reversals":         float64(0),
+						"interest":          float64(0),
+						"fees":              float64(0),
+						"other_debits":      float64(0),
+						"other_credits":     float64(0),
+						"closing_balance":   float64(-40),
+						"transaction_count": float64(2),
+					},
+				},
+				"msg": "Statements fetched successfully",
+			},
+		},
+		{
+			name:      "CSV Through Query",
+			accountID: "1",
+			query:     "?format=csv",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(1)).Return(&model.Account{ID: 1, BillingDay: 1}, nil)
+				m.EXPECT().ListStatements(gomock.Any(), uint(1)).Return([]model.Statement{testStatement()}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCSV: [][]string{
+				statementColumns,
+				{"3", "1", "2025-02-01T00:00:00+05:30", "2025-03-01T00:00:00+05:30", "2025-03-11T00:00:00+05:30",
+					"-20.00", "-50.00", "0.00", "30.00", "0.00", "0.00", "0.00", "0.00", "0.00", "-40.00", "2"},
+			},
+		},
+		{
+			name:      "CSV Through Accept Header",
+			accountID: "1",
+			accept:    "text/csv",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(1)).Return(&model.Account{ID: 1, BillingDay: 1}, nil)
+				m.EXPECT().ListStatements(gomock.Any(), uint(1)).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCSV:    [][]string{statementColumns},
+		},
+		{
+			name:           "Invalid Account ID",
+			accountID:      "abc",
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Not valid accountId",
+				"msg":       "Not able to fetch statements",
+			},
+		},
+		{
+			name:      "Account Not Found",
+			accountID: "7",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(7)).Return(nil, errors.New("record not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Account not found",
+			},
+		},
+		{
+			name:      "Database Error",
+			accountID: "1",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetAccount(gomock.Any(), uint(1)).Return(&model.Account{ID: 1}, nil)
+				m.EXPECT().ListStatements(gomock.Any(), uint(1)).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"error":     "database error",
+				"error_msg": "Internal Server Error",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+			controller := NewController(mockRepo)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/accounts/"+tt.accountID+"/statements"+tt.query, nil)
+			if tt.accept != "" {
+				c.Request.Header.Set("Accept", tt.accept)
+			}
+			c.Params = []gin.Param{{Key: "accountId", Value: tt.accountID}}
+
+			controller.ListStatements(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedCSV != nil {
+				assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+				assert.Equal(t, `attachment; filename="account-1-statements.csv"`, w.Header().Get("Content-Disposition"))
+				rows, err := csv.NewReader(w.Body).ReadAll()
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedCSV, rows)
+				return
+			}
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key], "mismatch in field: %s", key)
+			}
+		})
+	}
+}
+
+func TestController_GetStatement(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	statement := testStatement()
+	dueDate := time.Date(2025, 2, 10, 0, 0, 0, 0, model.IST)
+	transactions := []model.Transaction{
+		{ID: 4, AccountID: 1, OperationTypeId: model.NormalPurchase, Amount: model.MustParseMoney("-30"), EventDate: "2025-02-03T10:00:00"},
+		{ID: 6, AccountID: 1, OperationTypeId: model.PurchaseInstallments, Amount: model.MustParseMoney("-20"), EventDate: "2025-01-10T10:00:00", InstallmentNumber: 2, DueDate: &dueDate},
+		{ID: 9, AccountID: 1, OperationTypeId: model.CreditVoucher, Amount: model.MustParseMoney("30"), EventDate: "2025-02-20T18:30:00"},
+	}
+
+	tests := []struct {
+		name           string
+		accountID      string
+		statementID    string
+		query          string
+		mockBehavior   func(m *mock.MockIRepository)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+		expectedLines  int
+		expectedCSV    [][]string
+	}{
+		{
+			name:        "JSON With Transactions",
+			accountID:   "1",
+			statementID: "3",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetStatement(gomock.Any(), uint(1), uint(3)).Return(&statement, nil)
+				m.EXPECT().ListStatementTransactions(gomock.Any(), statement).Return(transactions, nil)
+				expectOperationTypes(m)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"msg": "Statement fetched successfully",
+			},
+			expectedLines: 3,
+		},
+		{
+			name:        "CSV With One Row Per Transaction",
+			accountID:   "1",
+			statementID: "3",
+			query:       "?format=csv",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetStatement(gomock.Any(), uint(1), uint(3)).Return(&statement, nil)
+				m.EXPECT().ListStatementTransactions(gomock.Any(), statement).Return(transactions, nil)
+				expectOperationTypes(m)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCSV: [][]string{
+				statementLineColumns,
+				{"4", "1", "Normal Purchase", "-30.00", "2025-02-03T10:00:00", "2025-02-03T10:00:00", "0"},
+				{"6", "2", "Purchase with Installments", "-20.00", "2025-02-10T00:00:00", "2025-01-10T10:00:00", "2"},
+				{"9", "4", "Credit Voucher", "30.00", "2025-02-20T18:30:00", "2025-02-20T18:30:00", "0"},
+			},
+		},
+		{
+			name:           "Invalid Statement ID",
+			accountID:      "1",
+			statementID:    "abc",
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Not valid statementId",
+				"msg":       "Not able to fetch statement",
+			},
+		},
+		{
+			name:        "Statement Not Found",
+			accountID:   "1",
+			statementID: "8",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetStatement(gomock.Any(), uint(1), uint(8)).Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Statement not found",
+			},
+		},
+		{
+			name:        "Database Error",
+			accountID:   "1",
+			statementID: "3",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetStatement(gomock.Any(), uint(1), uint(3)).Return(&statement, nil)
+				m.EXPECT().ListStatementTransactions(gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"error":     "database error",
+				"error_msg": "Internal Server Error",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+			controller := NewController(mockRepo)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/accounts/"+tt.accountID+"/statements/"+tt.statementID+tt.query, nil)
+			c.Params = []gin.Param{{Key: "accountId", Value: tt.accountID}, {Key: "statementId", Value: tt.statementID}}
+
+			controller.GetStatement(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedCSV != nil {
+				rows, err := csv.NewReader(w.Body).ReadAll()
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedCSV, rows)
+				return
+			}
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key], "mismatch in field: %s", key)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				body := response["statement"].(map[string]interface{})
+				assert.Equal(t, float64(3), body["statement_id"])
+				assert.Len(t, response["transactions"], tt.expectedLines)
+			}
+		})
+	}
+}
+
+func TestController_UpdateBillingCycle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		accountID      string
+		body           string
+		mockBehavior   func(m *mock.MockIRepository)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:      "Success",
+			accountID: "1",
+			body:      `{"billing_day":15}`,
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().UpdateBillingDay(gomock.Any(), uint(1), uint(15)).Return(&model.Account{ID: 1, BillingDay: 15}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"account_id":  float64(1),
+				"billing_day": float64(15),
+				"msg":         "Billing cycle updated successfully",
+			},
+		},
+		{
+			name:           "Missing Billing Day",
+			accountID:      "1",
+			body:           `{}`,
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Invalid request body",
+			},
+		},
+		{
+			name:           "Billing Day Out Of Range",
+			accountID:      "1",
+			body:           `{"billing_day":29}`,
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Billing day should be between 1 and 28",
+				"msg":       "Not able to update billing cycle",
+			},
+		},
+		{
+			name:      "Account Not Found",
+			accountID: "7",
+			body:      `{"billing_day":15}`,
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().UpdateBillingDay(gomock.Any(), uint(7), uint(15)).Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Account not found",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+			controller := NewController(mockRepo)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/accounts/"+tt.accountID+"/billing-cycle", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = []gin.Param{{Key: "accountId", Value: tt.accountID}}
+
+			controller.UpdateBillingCycle(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key], "mismatch in field: %s", key)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+	"github.com/vamshi1997/pismo-assessment/internal/webhook"
+	"gorm.io/gorm"
+)
+
+const defaultDeliveryPageSize = 50
+
+// webhookRequest is the body of a webhook subscription
+type webhookRequest struct {
+	URL        string   `json:"url" binding:"required,max=2048"`
+	EventTypes []string `json:"event_types" binding:"required,min=1"`
+}
+
+// CreateWebhook method registers a callback URL for the given event types ("*" for all of them). The
+// signing secret is only returned in this response.
+func (c *Controller) CreateWebhook(ctx *gin.Context) {
+	var request webhookRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Invalid request body",
+			"msg":       "Not able to create webhook",
+		})
+		return
+	}
+
+	if err := validateWebhookRequest(request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Invalid webhook subscription",
+			"msg":       "Not able to create webhook",
+		})
+		return
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to create webhook",
+		})
+		return
+	}
+
+	subscription, err := c.repo.CreateWebhookSubscription(ctx.Request.Context(), model.WebhookSubscription{
+		URL:        request.URL,
+		EventTypes: strings.Join(request.EventTypes, ","),
+		Secret:     secret,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to create webhook",
+		})
+		return
+	}
+
+	response := webhookResponse(*subscription)
+	response["secret"] = subscription.Secret
+	response["msg"] = "Webhook created successfully"
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ListWebhooks method returns every webhook subscription
+func (c *Controller) ListWebhooks(ctx *gin.Context) {
+	subscriptions, err := c.repo.ListWebhookSubscriptions(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to fetch webhooks",
+		})
+		return
+	}
+
+	items := make([]gin.H, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		items = append(items, webhookResponse(subscription))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"webhooks": items,
+		"msg":      "Webhooks fetched successfully",
+	})
+}
+
+// GetWebhook method returns a single webhook subscription
+func (c *Controller) GetWebhook(ctx *gin.Context) {
+	subscriptionID, err := strconv.Atoi(ctx.Param("webhookId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid webhookId",
+			"msg":       "Not able to fetch webhook",
+		})
+		return
+	}
+
+	subscription, err := c.repo.GetWebhookSubscription(ctx.Request.Context(), uint(subscriptionID))
+	if err != nil {
+		webhookLookupFailure(ctx, err, "Not able to fetch webhook")
+		return
+	}
+
+	response := webhookResponse(*subscription)
+	response["msg"] = "Webhook fetched successfully"
+	ctx.JSON(http.StatusOK, response)
+}
+
+// DeleteWebhook method removes a webhook subscription. Deliveries still pending for it are dead-lettered.
+func (c *Controller) DeleteWebhook(ctx *gin.Context) {
+	subscriptionID, err := strconv.Atoi(ctx.Param("webhookId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid webhookId",
+			"msg":       "Not able to delete webhook",
+		})
+		return
+	}
+
+	if err = c.repo.DeleteWebhookSubscription(ctx.Request.Context(), uint(subscriptionID)); err != nil {
+		webhookLookupFailure(ctx, err, "Not able to delete webhook")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"id":  subscriptionID,
+		"msg": "Webhook deleted successfully",
+	})
+}
+
+// ListWebhookDeliveries method returns the latest deliveries of a webhook subscription with every attempt
+// made for them, optionally only those with the given status, e.g. status=dead for the dead letters
+func (c *Controller) ListWebhookDeliveries(ctx *gin.Context) {
+	subscriptionID, err := strconv.Atoi(ctx.Param("webhookId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid webhookId",
+			"msg":       "Not able to fetch webhook deliveries",
+		})
+		return
+	}
+
+	status := ctx.Query("status")
+	if status != "" && status != model.DeliveryPending && status != model.DeliveryDelivered && status != model.DeliveryDead {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error_msg": "status should be pending, delivered or dead",
+			"msg":       "Not able to fetch webhook deliveries",
+		})
+		return
+	}
+
+	limit := defaultDeliveryPageSize
+	if value := ctx.Query("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > maxPageSize {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error_msg": fmt.Sprintf("limit should be between 1 and %d", maxPageSize),
+				"msg":       "Not able to fetch webhook deliveries",
+			})
+			return
+		}
+	}
+
+	if _, err = c.repo.GetWebhookSubscription(ctx.Request.Context(), uint(subscriptionID)); err != nil {
+		webhookLookupFailure(ctx, err, "Not able to fetch webhook deliveries")
+		return
+	}
+
+	deliveries, err := c.repo.ListWebhookDeliveries(ctx.Request.Context(), uint(subscriptionID), status, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to fetch webhook deliveries",
+		})
+		return
+	}
+
+	if deliveries == nil {
+		deliveries = []model.WebhookDelivery{}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"webhook_id": subscriptionID,
+		"deliveries": deliveries,
+		"msg":        "Webhook deliveries fetched successfully",
+	})
+}
+
+// RequeueWebhookDelivery method sends a dead-lettered delivery again, with a new series of attempts
+func (c *Controller) RequeueWebhookDelivery(ctx *gin.Context) {
+	subscriptionID, err := strconv.Atoi(ctx.Param("webhookId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid webhookId",
+			"msg":       "Not able to requeue webhook delivery",
+		})
+		return
+	}
+
+	deliveryID, err := strconv.Atoi(ctx.Param("deliveryId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Not valid deliveryId",
+			"msg":       "Not able to requeue webhook delivery",
+		})
+		return
+	}
+
+	delivery, err := c.repo.RequeueWebhookDelivery(ctx.Request.Context(), uint(subscriptionID), uint(deliveryID))
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error_msg": "Webhook delivery not found",
+			"msg":       "Not able to requeue webhook delivery",
+		})
+		return
+	case errors.Is(err, repo.ErrDeliveryNotDead):
+		ctx.JSON(http.StatusConflict, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Webhook delivery is not dead-lettered",
+			"msg":       "Not able to requeue webhook delivery",
+		})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":     err.Error(),
+			"error_msg": "Internal Server Error",
+			"msg":       "Not able to requeue webhook delivery",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"delivery_id": delivery.ID,
+		"status":      delivery.Status,
+		"msg":         "Webhook delivery requeued successfully",
+	})
+}
+
+// validateWebhookRequest checks the callback URL is an absolute http(s) URL and every event type is known
+func validateWebhookRequest(request webhookRequest) error {
+	callback, err := url.Parse(request.URL)
+	if err != nil || (callback.Scheme != "http" && callback.Scheme != "https") || callback.Host == "" {
+		return fmt.Errorf("url should be an absolute http or https URL")
+	}
+
+	for _, eventType := range request.EventTypes {
+		if eventType != "*" && !model.IsKnownEventType(eventType) {
+			return fmt.Errorf("unknown event type %q, expected one of %s or *", eventType, strings.Join(model.EventTypes, ", "))
+		}
+	}
+	return nil
+}
+
+// webhookResponse renders a subscription without its secret
+func webhookResponse(subscription model.WebhookSubscription) gin.H {
+	return gin.H{
+		"id":          subscription.ID,
+		"url":         subscription.URL,
+		"event_types": strings.Split(subscription.EventTypes, ","),
+		"created_at":  subscription.CreatedAt,
+	}
+}
+
+// webhookLookupFailure responds to a failed lookup of a webhook subscription
+func webhookLookupFailure(ctx *gin.Context, err error, msg string) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error_msg": "Webhook not found",
+			"msg":       msg,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusInternalServerError, gin.H{
+		"error":     err.Error(),
+		"error_msg": "Internal Server Error",
+		"msg":       msg,
+	})
+}
@@ -0,0 +1,390 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+	"github.com/vamshi1997/pismo-assessment/internal/repo/mock"
+	"gorm.io/gorm"
+)
+
+func TestController_CreateWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		mockBehavior   func(m *mock.MockIRepository)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name: "Success",
+			body: `{"url": "https://partner.example.com/hooks", "event_types": ["account.created", "transaction.created"]}`,
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().
+					CreateWebhookSubscription(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, subscription model.WebhookSubscription) (*model.WebhookSubscription, error) {
+						assert.Equal(t, "account.created,transaction.created", subscription.EventTypes)
+						assert.True(t, strings.HasPrefix(subscription.Secret, "whsec_"))
+						subscription.ID = 1
+						return &subscription, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"id":          float64(1),
+				"url":         "https://partner.example.com/hooks",
+				"event_types": []interface{}{"account.created", "transaction.created"},
+				"msg":         "Webhook created successfully",
+			},
+		},
+		{
+			name:           "Not An HTTP URL",
+			body:           `{"url": "ftp://partner.example.com", "event_types": ["*"]}`,
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error":     "url should be an absolute http or https URL",
+				"error_msg": "Invalid webhook subscription",
+			},
+		},
+		{
+			name:           "Unknown Event Type",
+			body:           `{"url": "https://partner.example.com", "event_types": ["account.deleted"]}`,
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Invalid webhook subscription",
+			},
+		},
+		{
+			name:           "No Event Types",
+			body:           `{"url": "https://partner.example.com", "event_types": []}`,
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Invalid request body",
+				"msg":       "Not able to create webhook",
+			},
+		},
+		{
+			name: "Database Error",
+			body: `{"url": "https://partner.example.com", "event_types": ["*"]}`,
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().CreateWebhookSubscription(gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"error":     "database error",
+				"error_msg": "Internal Server Error",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+			controller := NewController(mockRepo)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			controller.CreateWebhook(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key], "mismatch in field: %s", key)
+			}
+			if tt.expectedStatus == http.StatusOK {
+				assert.NotEmpty(t, response["secret"])
+			}
+		})
+	}
+}
+
+func TestController_ListWebhooks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockIRepository(ctrl)
+	mockRepo.EXPECT().ListWebhookSubscriptions(gomock.Any()).Return([]model.WebhookSubscription{
+		{ID: 1, URL: "https://partner.example.com/hooks", EventTypes: "*", Secret: "whsec_secret"},
+	}, nil)
+	controller := NewController(mockRepo)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/webhooks", nil)
+
+	controller.ListWebhooks(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "whsec_secret")
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response["webhooks"], 1)
+}
+
+func TestController_DeleteWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		webhookID      string
+		mockBehavior   func(m *mock.MockIRepository)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:      "Success",
+			webhookID: "1",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().DeleteWebhookSubscription(gomock.Any(), uint(1)).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"msg": "Webhook deleted successfully",
+			},
+		},
+		{
+			name:      "Not Found",
+			webhookID: "2",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().DeleteWebhookSubscription(gomock.Any(), uint(2)).Return(gorm.ErrRecordNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Webhook not found",
+				"msg":       "Not able to delete webhook",
+			},
+		},
+		{
+			name:           "Invalid Webhook ID",
+			webhookID:      "abc",
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Not valid webhookId",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+			controller := NewController(mockRepo)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/webhooks/"+tt.webhookID, nil)
+			c.Params = []gin.Param{{Key: "webhookId", Value: tt.webhookID}}
+
+			controller.DeleteWebhook(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key], "mismatch in field: %s", key)
+			}
+		})
+	}
+}
+
+func TestController_ListWebhookDeliveries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	attemptedAt := time.Date(2025, 2, 10, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		mockBehavior   func(m *mock.MockIRepository)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+		expectedCount  int
+	}{
+		{
+			name:  "Dead Letters With Attempts",
+			query: "?status=dead",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetWebhookSubscription(gomock.Any(), uint(1)).Return(&model.WebhookSubscription{ID: 1}, nil)
+				m.EXPECT().ListWebhookDeliveries(gomock.Any(), uint(1), model.DeliveryDead, defaultDeliveryPageSize).Return([]model.WebhookDelivery{
+					{ID: 3, SubscriptionID: 1, EventID: 9, EventType: model.EventTransactionCreated, Status: model.DeliveryDead, Attempts: 2,
+						AttemptLog: []model.WebhookAttempt{
+							{ID: 1, DeliveryID: 3, AttemptNumber: 1, StatusCode: 500, Error: "unexpected status 500: ", CreatedAt: attemptedAt},
+							{ID: 2, DeliveryID: 3, AttemptNumber: 2, Error: "connection refused", CreatedAt: attemptedAt},
+						}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"webhook_id": float64(1),
+				"msg":        "Webhook deliveries fetched successfully",
+			},
+			expectedCount: 1,
+		},
+		{
+			name:           "Invalid Status",
+			query:          "?status=failed",
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"error_msg": "status should be pending, delivered or dead",
+			},
+		},
+		{
+			name:  "Webhook Not Found",
+			query: "",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetWebhookSubscription(gomock.Any(), uint(1)).Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Webhook not found",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+			controller := NewController(mockRepo)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/webhooks/1/deliveries"+tt.query, nil)
+			c.Params = []gin.Param{{Key: "webhookId", Value: "1"}}
+
+			controller.ListWebhookDeliveries(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key], "mismatch in field: %s", key)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				deliveries := response["deliveries"].([]interface{})
+				assert.Len(t, deliveries, tt.expectedCount)
+				delivery := deliveries[0].(map[string]interface{})
+				assert.Equal(t, "dead", delivery["status"])
+				assert.Len(t, delivery["attempt_log"], 2)
+			}
+		})
+	}
+}
+
+func TestController_RequeueWebhookDelivery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		mockBehavior   func(m *mock.MockIRepository)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().RequeueWebhookDelivery(gomock.Any(), uint(1), uint(3)).
+					Return(&model.WebhookDelivery{ID: 3, Status: model.DeliveryPending}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"delivery_id": float64(3),
+				"status":      "pending",
+			},
+		},
+		{
+			name: "Delivery Not Dead",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().RequeueWebhookDelivery(gomock.Any(), uint(1), uint(3)).Return(nil, repo.ErrDeliveryNotDead)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Webhook delivery is not dead-lettered",
+			},
+		},
+		{
+			name: "Delivery Not Found",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().RequeueWebhookDelivery(gomock.Any(), uint(1), uint(3)).Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Webhook delivery not found",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+			controller := NewController(mockRepo)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/webhooks/1/deliveries/3/requeue", nil)
+			c.Params = []gin.Param{{Key: "webhookId", Value: "1"}, {Key: "deliveryId", Value: "3"}}
+
+			controller.RequeueWebhookDelivery(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key], "mismatch in field: %s", key)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package document
+
+import (
+	"strings"
+)
+
+// Type is the kind of a Brazilian taxpayer document
+type Type string
+
+const (
+	// CPF identifies a person, it has 11 digits
+	CPF Type = "CPF"
+	// CNPJ identifies a company, it has 14 digits
+	CNPJ Type = "CNPJ"
+)
+
+const (
+	cpfLength  = 11
+	cnpjLength = 14
+)
+
+// Error codes returned by Validate, one for each reason a document can be rejected
+const (
+	CodeEmpty             = "document_empty"
+	CodeInvalidCharacters = "document_invalid_characters"
+	CodeInvalidLength     = "document_invalid_length"
+	CodeRepeatedDigits    = "document_repeated_digits"
+	CodeInvalidCheckDigit = "document_invalid_check_digit"
+)
+
+// ValidationError tells why a document number was rejected. Code is stable and meant for clients,
+// Message is meant for people.
+type ValidationError struct {
+	Code    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Normalize removes the formatting characters allowed in a document number, i.e. spaces, dots,
+// dashes and slashes, so "123.456.789-09" becomes "12345678909"
+func Normalize(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '-', '/', ' ':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(value))
+}
+
+// Validate normalizes a CPF or CNPJ number and checks its check digits. The document type is told
+// apart by the number of digits. It returns the normalized number and its type, or a *ValidationError.
+func Validate(value string) (string, Type, error) {
+	number := Normalize(value)
+	if number == "" {
+		return "", "", &ValidationError{Code: CodeEmpty, Message: "document number is empty"}
+	}
+
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return "", "", &ValidationError{Code: CodeInvalidCharacters, Message: "document number should only contain digits"}
+		}
+	}
+
+	var documentType Type
+	switch len(number) {
+	case cpfLength:
+		documentType = CPF
+	case cnpjLength:
+		documentType = CNPJ
+	default:
+		return "", "", &ValidationError{
+			Code:    CodeInvalidLength,
+			Message: "document number should have 11 digits for a CPF or 14 digits for a CNPJ",
+		}
+	}
+
+	// numbers such as 111.111.111-11 pass the check digit test but are never issued
+	if strings.Count(number, number[:1]) == len(number) {
+		return "", "", &ValidationError{Code: CodeRepeatedDigits, Message: "document number can not have all digits equal"}
+	}
+
+	if !hasValidCheckDigits(number, documentType) {
+		return "", "", &ValidationError{Code: CodeInvalidCheckDigit, Message: "document number check digits do not match"}
+	}
+
+	return number, documentType, nil
+}
+
+// hasValidCheckDigits computes the two trailing check digits of a CPF or CNPJ and compares them
+func hasValidCheckDigits(number string, documentType Type) bool {
+	base := len(number) - 2
+	first := checkDigit(number[:base], documentType)
+	second := checkDigit(number[:base+1], documentType)
+	return int(number[base]-'0') == first && int(number[base+1]-'0') == second
+}
+
+// checkDigit computes the modulo 11 check digit of the given digits. A CPF weighs digits from 2 upwards
+// without limit, a CNPJ restarts at 2 after weight 9, both starting from the rightmost digit.
+func checkDigit(digits string, documentType Type) int {
+	sum := 0
+	weight := 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if documentType == CNPJ && weight > 9 {
+			weight = 2
+		}
+	}
+
+	remainder := sum % 11
+	if remainder < 2 {
+		return 0
+	}
+	return 11 - remainder
+}
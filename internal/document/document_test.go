@@ -0,0 +1,55 @@
+package document
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		expected     string
+		expectedType Type
+		expectedCode string
+	}{
+		{name: "Valid CPF", input: "12345678909", expected: "12345678909", expectedType: CPF},
+		{name: "Formatted CPF", input: " 123.456.789-09 ", expected: "12345678909", expectedType: CPF},
+		{name: "CPF With Zero First Check Digit", input: "390.533.447-05", expected: "39053344705", expectedType: CPF},
+		{name: "CPF With Zero Second Check Digit", input: "935.411.347-80", expected: "93541134780", expectedType: CPF},
+		{name: "CPF With Zero Remainders", input: "100.000.037-00", expected: "10000003700", expectedType: CPF},
+		{name: "Valid CNPJ", input: "11222333000181", expected: "11222333000181", expectedType: CNPJ},
+		{name: "Formatted CNPJ", input: "11.222.333/0001-81", expected: "11222333000181", expectedType: CNPJ},
+		{name: "Empty", input: " ", expectedCode: CodeEmpty},
+		{name: "Letters", input: "1234567890a", expectedCode: CodeInvalidCharacters},
+		{name: "Too Short", input: "123", expectedCode: CodeInvalidLength},
+		{name: "Between CPF And CNPJ", input: "123456789012", expectedCode: CodeInvalidLength},
+		{name: "Repeated Digits CPF", input: "111.111.111-11", expectedCode: CodeRepeatedDigits},
+		{name: "Repeated Digits CNPJ", input: "00000000000000", expectedCode: CodeRepeatedDigits},
+		{name: "Wrong CPF Check Digit", input: "12345678901", expectedCode: CodeInvalidCheckDigit},
+		{name: "Wrong Zero First CPF Check Digit", input: "39053344715", expectedCode: CodeInvalidCheckDigit},
+		{name: "Wrong Zero Second CPF Check Digit", input: "93541134781", expectedCode: CodeInvalidCheckDigit},
+		{name: "Wrong CPF Check Digit For Zero Remainder", input: "10000003710", expectedCode: CodeInvalidCheckDigit},
+		{name: "Wrong CNPJ Check Digit", input: "11222333000182", expectedCode: CodeInvalidCheckDigit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			number, documentType, err := Validate(tt.input)
+
+			if tt.expectedCode != "" {
+				var validationErr *ValidationError
+				assert.True(t, errors.As(err, &validationErr))
+				assert.Equal(t, tt.expectedCode, validationErr.Code)
+				assert.Empty(t, number)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, number)
+			assert.Equal(t, tt.expectedType, documentType)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// FilePublisher appends every event as one JSON line to a file, which downstream tools can tail during
+// local development. Each event is synced to disk before Publish returns.
+type FilePublisher struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFilePublisher opens, or creates, the file events are appended to
+func NewFilePublisher(path string) (*FilePublisher, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FilePublisher{file: file}, nil
+}
+
+// Publish writes the event as a JSON line
+func (p *FilePublisher) Publish(ctx context.Context, event Envelope) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err = p.file.Write(line); err != nil {
+		return err
+	}
+	return p.file.Sync()
+}
+
+// Close closes the underlying file
+func (p *FilePublisher) Close() error {
+	return p.file.Close()
+}
@@ -0,0 +1,43 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// Handler consumes an event published in process
+type Handler func(event Envelope) error
+
+// MemoryPublisher delivers events to handlers subscribed in the same process. It is meant for local
+// use and tests. An event counts as published only if every handler accepted it.
+type MemoryPublisher struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+// NewMemoryPublisher creates a publisher without subscribers
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{handlers: map[string][]Handler{}}
+}
+
+// Subscribe registers a handler for an event type, or for every event type when eventType is "*"
+func (p *MemoryPublisher) Subscribe(eventType string, handler Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.handlers[eventType] = append(p.handlers[eventType], handler)
+}
+
+// Publish calls the handlers of the event's type and the catch-all handlers, stopping at the first error
+func (p *MemoryPublisher) Publish(ctx context.Context, event Envelope) error {
+	p.mu.RLock()
+	handlers := append(append([]Handler(nil), p.handlers[event.Type]...), p.handlers["*"]...)
+	p.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
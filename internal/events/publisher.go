@@ -0,0 +1,71 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+)
+
+// Publisher delivers events to downstream consumers. Publish should only return nil once the event is
+// safely handed over, since the event is then marked as published and never offered again. Events can
+// be delivered more than once, so consumers should use Envelope.ID to drop duplicates.
+type Publisher interface {
+	Publish(ctx context.Context, event Envelope) error
+}
+
+// Envelope is the published form of an outbox event
+type Envelope struct {
+	ID            uint            `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uint            `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// NewEnvelope wraps an outbox event for publishing
+func NewEnvelope(event model.OutboxEvent) Envelope {
+	return Envelope{
+		ID:            event.ID,
+		Type:          event.EventType,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		OccurredAt:    event.CreatedAt,
+		Payload:       json.RawMessage(event.Payload),
+	}
+}
+
+// MultiPublisher hands every event to each of its publishers in turn. An event counts as published
+// only if all of them accepted it, so a publisher may see an event again when a later one failed.
+type MultiPublisher []Publisher
+
+// Publish publishes the event with every publisher, stopping at the first error
+func (m MultiPublisher) Publish(ctx context.Context, event Envelope) error {
+	for _, publisher := range m {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Publisher kinds which can be chosen in the configuration
+const (
+	PublisherMemory = "memory"
+	PublisherFile   = "file"
+)
+
+// NewPublisher creates the publisher of the given kind. filePath is only used by the file publisher.
+func NewPublisher(kind string, filePath string) (Publisher, error) {
+	switch kind {
+	case PublisherMemory:
+		return NewMemoryPublisher(), nil
+	case PublisherFile:
+		return NewFilePublisher(filePath)
+	default:
+		return nil, fmt.Errorf("unknown event publisher %q", kind)
+	}
+}
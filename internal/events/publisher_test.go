@@ -0,0 +1,70 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+)
+
+func TestMemoryPublisher(t *testing.T) {
+	publisher := NewMemoryPublisher()
+
+	var accounts, all int
+	publisher.Subscribe(model.EventAccountCreated, func(event Envelope) error {
+		accounts++
+		return nil
+	})
+	publisher.Subscribe("*", func(event Envelope) error {
+		all++
+		return nil
+	})
+
+	assert.NoError(t, publisher.Publish(context.Background(), Envelope{ID: 1, Type: model.EventAccountCreated}))
+	assert.NoError(t, publisher.Publish(context.Background(), Envelope{ID: 2, Type: model.EventTransactionCreated}))
+	assert.Equal(t, 1, accounts)
+	assert.Equal(t, 2, all)
+
+	publisher.Subscribe(model.EventTransactionCreated, func(event Envelope) error {
+		return errors.New("consumer failed")
+	})
+	assert.Error(t, publisher.Publish(context.Background(), Envelope{ID: 3, Type: model.EventTransactionCreated}))
+}
+
+func TestFilePublisher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+
+	publisher, err := NewPublisher(PublisherFile, path)
+	assert.NoError(t, err)
+
+	assert.NoError(t, publisher.Publish(context.Background(), Envelope{ID: 1, Type: model.EventAccountCreated, Payload: json.RawMessage(`{"account_id":1}`)}))
+	assert.NoError(t, publisher.Publish(context.Background(), Envelope{ID: 2, Type: model.EventTransactionCreated, Payload: json.RawMessage(`{"transaction_id":7}`)}))
+	assert.NoError(t, publisher.(*FilePublisher).Close())
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	var lines []Envelope
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event Envelope
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		lines = append(lines, event)
+	}
+
+	assert.Len(t, lines, 2)
+	assert.Equal(t, model.EventAccountCreated, lines[0].Type)
+	assert.JSONEq(t, `{"transaction_id":7}`, string(lines[1].Payload))
+}
+
+func TestNewPublisher_Unknown(t *testing.T) {
+	_, err := NewPublisher("kafka", "")
+	assert.EqualError(t, err, `unknown event publisher "kafka"`)
+}
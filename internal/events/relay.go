@@ -0,0 +1,92 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/vamshi1997/pismo-assessment/internal/logging"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+	"github.com/vamshi1997/pismo-assessment/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 100
+
+	// workerName tags the logs and spans of the relay
+	workerName = "outbox_relay"
+)
+
+// Relay moves events from the outbox to a publisher in the background. Since an event is only marked as
+// published after the publisher accepted it, delivery is at least once.
+type Relay struct {
+	repo         repo.IRepository
+	publisher    Publisher
+	pollInterval time.Duration
+	batchSize    int
+}
+
+// NewRelay creates a relay polling the outbox every pollInterval for up to batchSize events. Zero values
+// fall back to a one second interval and batches of 100 events.
+func NewRelay(repo repo.IRepository, publisher Publisher, pollInterval time.Duration, batchSize int) *Relay {
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	return &Relay{
+		repo:         repo,
+		publisher:    publisher,
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+	}
+}
+
+// Run publishes pending events until stop is closed. A full batch is followed right away by the next
+// one, otherwise the relay waits for the poll interval.
+func (r *Relay) Run(stop <-chan struct{}) {
+	ctx := logging.WithLogger(context.Background(), slog.With("worker", workerName))
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		published, err := r.RunOnce(ctx)
+		if err == nil && published == r.batchSize {
+			select {
+			case <-stop:
+				return
+			default:
+				continue
+			}
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce publishes one batch of pending events and returns how many were published
+func (r *Relay) RunOnce(ctx context.Context) (int, error) {
+	ctx, span := tracing.Tracer().Start(ctx, workerName+".run_once")
+	defer span.End()
+
+	published, err := r.repo.PublishPendingEvents(ctx, r.batchSize, func(event model.OutboxEvent) error {
+		return r.publisher.Publish(ctx, NewEnvelope(event))
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("error while relaying outbox events", "error", err)
+		tracing.RecordError(span, err)
+		return 0, err
+	}
+
+	span.SetAttributes(attribute.Int("published", published))
+	return published, nil
+}
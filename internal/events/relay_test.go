@@ -0,0 +1,132 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo/mock"
+	"github.com/vamshi1997/pismo-assessment/internal/tracing/tracingtest"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestRelay_RunOnce(t *testing.T) {
+	createdAt := time.Date(2025, 2, 10, 10, 0, 0, 0, time.UTC)
+	pending := []model.OutboxEvent{
+		{ID: 1, EventType: model.EventAccountCreated, AggregateType: model.AggregateAccount, AggregateID: 1, Payload: []byte(`{"account_id":1}`), CreatedAt: createdAt},
+		{ID: 2, EventType: model.EventTransactionCreated, AggregateType: model.AggregateTransaction, AggregateID: 7, Payload: []byte(`{"transaction_id":7}`), CreatedAt: createdAt},
+	}
+
+	// publishPending behaves like the repository: events are handed over in order until one fails
+	publishPending := func(_ context.Context, limit int, publish func(event model.OutboxEvent) error) (int, error) {
+		published := 0
+		for _, event := range pending[:min(limit, len(pending))] {
+			if err := publish(event); err != nil {
+				return published, nil
+			}
+			published++
+		}
+		return published, nil
+	}
+
+	tests := []struct {
+		name              string
+		failOn            string
+		repoErr           error
+		expectedPublished int
+		expectedTypes     []string
+		expectedErr       error
+	}{
+		{
+			name:              "Publishes Every Pending Event",
+			expectedPublished: 2,
+			expectedTypes:     []string{model.EventAccountCreated, model.EventTransactionCreated},
+		},
+		{
+			name:              "Stops At Publisher Failure",
+			failOn:            model.EventTransactionCreated,
+			expectedPublished: 1,
+			expectedTypes:     []string{model.EventAccountCreated},
+		},
+		{
+			name:        "Repository Failure",
+			repoErr:     errors.New("database error"),
+			expectedErr: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			exporter := tracingtest.Install(t)
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			if tt.repoErr != nil {
+				mockRepo.EXPECT().PublishPendingEvents(gomock.Any(), 10, gomock.Any()).Return(0, tt.repoErr)
+			} else {
+				mockRepo.EXPECT().PublishPendingEvents(gomock.Any(), 10, gomock.Any()).DoAndReturn(publishPending)
+			}
+
+			var received []Envelope
+			publisher := NewMemoryPublisher()
+			publisher.Subscribe("*", func(event Envelope) error {
+				if event.Type == tt.failOn {
+					return errors.New("broker unavailable")
+				}
+				received = append(received, event)
+				return nil
+			})
+
+			published, err := NewRelay(mockRepo, publisher, time.Second, 10).RunOnce(context.Background())
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedPublished, published)
+
+			var types []string
+			for _, event := range received {
+				types = append(types, event.Type)
+			}
+			assert.Equal(t, tt.expectedTypes, types)
+			if len(received) > 0 {
+				assert.Equal(t, uint(1), received[0].ID)
+				assert.Equal(t, createdAt, received[0].OccurredAt)
+				assert.JSONEq(t, `{"account_id":1}`, string(received[0].Payload))
+			}
+
+			spans := exporter.GetSpans()
+			assert.Equal(t, []string{"outbox_relay.run_once"}, tracingtest.Names(spans))
+			if tt.expectedErr != nil {
+				assert.Equal(t, codes.Error, spans[0].Status.Code)
+			}
+		})
+	}
+}
+
+func TestRelay_RunStops(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockIRepository(ctrl)
+	mockRepo.EXPECT().PublishPendingEvents(gomock.Any(), gomock.Any(), gomock.Any()).Return(0, nil).MinTimes(1)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		NewRelay(mockRepo, NewMemoryPublisher(), 10*time.Millisecond, 0).Run(stop)
+		close(done)
+	}()
+
+	time.Sleep(30 * time.Millisecond)
+	close(stop)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("relay did not stop")
+	}
+}
@@ -0,0 +1,90 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+)
+
+// csvColumns are the CSV columns, in the order CSVWriter writes them
+var csvColumns = []string{
+	"transaction_id", "account_id", "operation_type_id", "operation_type", "amount", "balance", "event_date",
+	"due_date", "installments", "installment_number", "parent_transaction_id", "reversed_transaction_id",
+	"accrued_transaction_id",
+}
+
+// CSVWriter writes one CSV row per record after a header row. Missing values are left empty.
+type CSVWriter struct {
+	output
+	csv           *csv.Writer
+	headerWritten bool
+}
+
+// NewCSVWriter creates a CSV writer on w
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	out := newOutput(w)
+	return &CSVWriter{output: out, csv: csv.NewWriter(out.buf)}
+}
+
+// Write writes the record as a CSV row
+func (w *CSVWriter) Write(record Record) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	dueDate := ""
+	if record.DueDate != nil {
+		dueDate = record.DueDate.In(model.IST).Format(time.RFC3339)
+	}
+
+	return w.csv.Write([]string{
+		strconv.FormatUint(uint64(record.TransactionID), 10),
+		strconv.FormatUint(uint64(record.AccountID), 10),
+		strconv.FormatUint(uint64(record.OperationTypeID), 10),
+		record.OperationType,
+		record.Amount.String(),
+		record.Balance.String(),
+		record.EventDate,
+		dueDate,
+		strconv.FormatUint(uint64(record.Installments), 10),
+		strconv.FormatUint(uint64(record.InstallmentNumber), 10),
+		formatID(record.ParentTransactionID),
+		formatID(record.ReversedTransactionID),
+		formatID(record.AccruedTransactionID),
+	})
+}
+
+// Flush pushes the buffered rows to the underlying writer
+func (w *CSVWriter) Flush() error {
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return err
+	}
+	return w.output.Flush()
+}
+
+// Close writes the header if no record was written, and flushes
+func (w *CSVWriter) Close() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+func (w *CSVWriter) writeHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+	w.headerWritten = true
+	return w.csv.Write(csvColumns)
+}
+
+func formatID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
@@ -0,0 +1,161 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+)
+
+// Format is a file format transactions can be exported in
+type Format string
+
+const (
+	CSV    Format = "csv"
+	NDJSON Format = "ndjson"
+	OFX    Format = "ofx"
+)
+
+// Formats lists every supported export format
+var Formats = []Format{CSV, NDJSON, OFX}
+
+// ParseFormat reads a format name, ignoring case
+func ParseFormat(value string) (Format, error) {
+	for _, format := range Formats {
+		if strings.EqualFold(value, string(format)) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown export format %q, expected csv, ndjson or ofx", value)
+}
+
+// ContentType returns the media type of files in the format
+func (f Format) ContentType() string {
+	switch f {
+	case NDJSON:
+		return "application/x-ndjson"
+	case OFX:
+		return "application/x-ofx"
+	default:
+		return "text/csv"
+	}
+}
+
+// Record is one exported transaction. Event dates are written in RFC 3339 in IST.
+type Record struct {
+	TransactionID         uint        `json:"transaction_id"`
+	AccountID             uint        `json:"account_id"`
+	OperationTypeID       uint        `json:"operation_type_id"`
+	OperationType         string      `json:"operation_type"`
+	Amount                model.Money `json:"amount"`
+	Balance               model.Money `json:"balance"`
+	EventDate             string      `json:"event_date"`
+	DueDate               *time.Time  `json:"due_date,omitempty"`
+	Installments          uint        `json:"installments,omitempty"`
+	InstallmentNumber     uint        `json:"installment_number,omitempty"`
+	ParentTransactionID   *uint       `json:"parent_transaction_id,omitempty"`
+	ReversedTransactionID *uint       `json:"reversed_transaction_id,omitempty"`
+	AccruedTransactionID  *uint       `json:"accrued_transaction_id,omitempty"`
+}
+
+// NewRecord builds the exported form of a transaction, naming its operation type from names
+func NewRecord(transaction model.Transaction, names map[uint]string) Record {
+	eventDate := transaction.EventDate
+	if parsed, err := model.ParseEventDate(transaction.EventDate); err == nil {
+		eventDate = parsed.In(model.IST).Format(time.RFC3339Nano)
+	}
+
+	return Record{
+		TransactionID:         transaction.ID,
+		AccountID:             transaction.AccountID,
+		OperationTypeID:       transaction.OperationTypeId,
+		OperationType:         names[transaction.OperationTypeId],
+		Amount:                transaction.Amount,
+		Balance:               transaction.Balance,
+		EventDate:             eventDate,
+		DueDate:               transaction.DueDate,
+		Installments:          transaction.Installments,
+		InstallmentNumber:     transaction.InstallmentNumber,
+		ParentTransactionID:   transaction.ParentTransactionID,
+		ReversedTransactionID: transaction.ReversedTransactionID,
+		AccruedTransactionID:  transaction.AccruedTransactionID,
+	}
+}
+
+// Writer writes records in one format. Records are buffered until Flush, and Close writes whatever
+// ends the document and flushes it. Neither closes the underlying writer.
+type Writer interface {
+	Write(record Record) error
+	Flush() error
+	Close() error
+}
+
+// NewWriter creates a writer of the given format on w
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case CSV:
+		return NewCSVWriter(w), nil
+	case NDJSON:
+		return NewNDJSONWriter(w), nil
+	case OFX:
+		return NewOFXWriter(w, time.Now()), nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// Export streams the transactions matching the filter to the writer, reading batchSize transactions
+// from the repository at a time and flushing after each batch. It returns how many transactions were
+// written.
+func Export(ctx context.Context, r repo.IRepository, filter repo.ExportFilter, writer Writer, batchSize int) (int, error) {
+	operationTypes, err := r.ListOperationTypes(ctx)
+	if err != nil {
+		return 0, err
+	}
+	names := make(map[uint]string, len(operationTypes))
+	for _, operationType := range operationTypes {
+		names[operationType.ID] = operationType.Description
+	}
+
+	written := 0
+	err = r.StreamTransactions(ctx, filter, batchSize, func(batch []model.Transaction) error {
+		for _, transaction := range batch {
+			if err := writer.Write(NewRecord(transaction, names)); err != nil {
+				return err
+			}
+			written++
+		}
+		return writer.Flush()
+	})
+	if err != nil {
+		return written, err
+	}
+
+	return written, writer.Close()
+}
+
+// output buffers what a writer writes, and pushes it on to HTTP clients on every flush
+type output struct {
+	buf *bufio.Writer
+	out io.Writer
+}
+
+func newOutput(w io.Writer) output {
+	return output{buf: bufio.NewWriter(w), out: w}
+}
+
+func (o output) Flush() error {
+	if err := o.buf.Flush(); err != nil {
+		return err
+	}
+	if flusher, ok := o.out.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
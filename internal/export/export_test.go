@@ -0,0 +1,168 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+	"github.com/vamshi1997/pismo-assessment/internal/repo/mock"
+)
+
+func exportTransactions() []model.Transaction {
+	parentID := uint(2)
+	dueDate := time.Date(2025, 3, 10, 0, 0, 0, 0, model.IST)
+
+	return []model.Transaction{
+		{ID: 1, AccountID: 1, OperationTypeId: model.NormalPurchase, Amount: model.MustParseMoney("-50"), Balance: model.MustParseMoney("-20"), EventDate: "2025-02-10T10:00:00+05:30"},
+		{ID: 3, AccountID: 1, OperationTypeId: model.PurchaseInstallments, Amount: model.MustParseMoney("-25"), Balance: model.MustParseMoney("-25"), EventDate: "2025-02-10T11:00:00.5+05:30", InstallmentNumber: 2, ParentTransactionID: &parentID, DueDate: &dueDate},
+		{ID: 4, AccountID: 2, OperationTypeId: model.CreditVoucher, Amount: model.MustParseMoney("30"), Balance: model.MustParseMoney("0"), EventDate: "2025-02-11T09:00:00+05:30"},
+	}
+}
+
+// expectStream makes the repository pass the transactions in batches of batchSize
+func expectStream(m *mock.MockIRepository, filter repo.ExportFilter, batchSize int, transactions []model.Transaction) {
+	m.EXPECT().ListOperationTypes(gomock.Any()).Return(model.DefaultOperationTypes, nil)
+	m.EXPECT().
+		StreamTransactions(gomock.Any(), filter, batchSize, gomock.Any()).
+		DoAndReturn(func(_ context.Context, filter repo.ExportFilter, batchSize int, fn func(batch []model.Transaction) error) error {
+			for start := 0; start < len(transactions); start += batchSize {
+				end := min(start+batchSize, len(transactions))
+				if err := fn(transactions[start:end]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+}
+
+func TestExport_CSV(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockIRepository(ctrl)
+	expectStream(mockRepo, repo.ExportFilter{AccountID: 1}, 2, exportTransactions())
+
+	var out bytes.Buffer
+	written, err := Export(context.Background(), mockRepo, repo.ExportFilter{AccountID: 1}, NewCSVWriter(&out), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, written)
+
+	rows, err := csv.NewReader(&out).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		csvColumns,
+		{"1", "1", "1", "Normal Purchase", "-50.00", "-20.00", "2025-02-10T10:00:00+05:30", "", "0", "0", "", "", ""},
+		{"3", "1", "2", "Purchase with Installments", "-25.00", "-25.00", "2025-02-10T11:00:00.5+05:30", "2025-03-10T00:00:00+05:30", "0", "2", "2", "", ""},
+		{"4", "2", "4", "Credit Voucher", "30.00", "0.00", "2025-02-11T09:00:00+05:30", "", "0", "0", "", "", ""},
+	}, rows)
+}
+
+func TestExport_CSVWithoutTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockIRepository(ctrl)
+	expectStream(mockRepo, repo.ExportFilter{}, 100, nil)
+
+	var out bytes.Buffer
+	written, err := Export(context.Background(), mockRepo, repo.ExportFilter{}, NewCSVWriter(&out), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, written)
+	assert.Equal(t, strings.Join(csvColumns, ",")+"\n", out.String())
+}
+
+func TestExport_NDJSON(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockIRepository(ctrl)
+	expectStream(mockRepo, repo.ExportFilter{}, 2, exportTransactions())
+
+	var out bytes.Buffer
+	written, err := Export(context.Background(), mockRepo, repo.ExportFilter{}, NewNDJSONWriter(&out), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, written)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 3)
+
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Equal(t, map[string]interface{}{
+		"transaction_id":        float64(3),
+		"account_id":            float64(1),
+		"operation_type_id":     float64(2),
+		"operation_type":        "Purchase with Installments",
+		"amount":                float64(-25),
+		"balance":               float64(-25),
+		"event_date":            "2025-02-10T11:00:00.5+05:30",
+		"due_date":              "2025-03-10T00:00:00+05:30",
+		"installment_number":    float64(2),
+		"parent_transaction_id": float64(2),
+	}, record)
+}
+
+func TestExport_OFX(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockIRepository(ctrl)
+	expectStream(mockRepo, repo.ExportFilter{}, 2, exportTransactions())
+
+	var out bytes.Buffer
+	generatedAt := time.Date(2025, 2, 12, 8, 0, 0, 0, model.IST)
+	written, err := Export(context.Background(), mockRepo, repo.ExportFilter{}, NewOFXWriter(&out, generatedAt), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, written)
+
+	document := out.String()
+	assert.True(t, strings.HasPrefix(document, "<?xml"))
+	assert.Equal(t, 2, strings.Count(document, "<CCSTMTTRNRS>"))
+	assert.Contains(t, document, "<ACCTID>1</ACCTID>")
+	assert.Contains(t, document, "<DTSTART>20250210100000.000[+5.30:IST]</DTSTART><DTEND>20250212080000.000[+5.30:IST]</DTEND>")
+	assert.Contains(t, document, "<STMTTRN><TRNTYPE>DEBIT</TRNTYPE><DTPOSTED>20250210110000.500[+5.30:IST]</DTPOSTED><TRNAMT>-25.00</TRNAMT><FITID>3</FITID><NAME>Purchase with Installments</NAME></STMTTRN>")
+	assert.Contains(t, document, "<LEDGERBAL><BALAMT>-45.00</BALAMT>")
+	assert.Contains(t, document, "<TRNTYPE>CREDIT</TRNTYPE>")
+
+	// the document has to be well formed XML
+	decoder := xml.NewDecoder(strings.NewReader(document))
+	for {
+		if _, err := decoder.Token(); err != nil {
+			assert.Equal(t, "EOF", err.Error())
+			break
+		}
+	}
+}
+
+func TestExport_StopsOnRepositoryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockIRepository(ctrl)
+	mockRepo.EXPECT().ListOperationTypes(gomock.Any()).Return(model.DefaultOperationTypes, nil)
+	mockRepo.EXPECT().StreamTransactions(gomock.Any(), gomock.Any(), 10, gomock.Any()).Return(errors.New("database error"))
+
+	var out bytes.Buffer
+	_, err := Export(context.Background(), mockRepo, repo.ExportFilter{}, NewNDJSONWriter(&out), 10)
+	assert.Equal(t, errors.New("database error"), err)
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("OFX")
+	assert.NoError(t, err)
+	assert.Equal(t, OFX, format)
+	assert.Equal(t, "application/x-ofx", format.ContentType())
+
+	_, err = ParseFormat("xlsx")
+	assert.EqualError(t, err, `unknown export format "xlsx", expected csv, ndjson or ofx`)
+}
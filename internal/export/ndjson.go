@@ -0,0 +1,28 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// NDJSONWriter writes every record as one JSON object per line
+type NDJSONWriter struct {
+	output
+	encoder *json.Encoder
+}
+
+// NewNDJSONWriter creates an NDJSON writer on w
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	out := newOutput(w)
+	return &NDJSONWriter{output: out, encoder: json.NewEncoder(out.buf)}
+}
+
+// Write writes the record as a JSON line
+func (w *NDJSONWriter) Write(record Record) error {
+	return w.encoder.Encode(record)
+}
+
+// Close flushes the buffered lines
+func (w *NDJSONWriter) Close() error {
+	return w.Flush()
+}
@@ -0,0 +1,130 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+)
+
+const (
+	// ofxCurrency is the currency amounts are reported in
+	ofxCurrency = "BRL"
+
+	ofxHeader = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n" +
+		`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n"
+	ofxStatusOK = "<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>"
+)
+
+// OFXWriter writes an OFX 2.2 credit card statement response with one statement per account. Records
+// have to arrive grouped by account, as Export passes them. The ledger balance of each statement is the
+// outstanding balance of the exported transactions of the account.
+type OFXWriter struct {
+	output
+	generatedAt time.Time
+	started     bool
+	inAccount   bool
+	accountID   uint
+	ledger      model.Money
+	statements  int
+}
+
+// NewOFXWriter creates an OFX writer on w for a document generated at the given time
+func NewOFXWriter(w io.Writer, generatedAt time.Time) *OFXWriter {
+	return &OFXWriter{output: newOutput(w), generatedAt: generatedAt}
+}
+
+// Write writes the record as a statement transaction, starting the statement of its account first
+// when it belongs to another account than the previous record
+func (w *OFXWriter) Write(record Record) error {
+	w.start()
+
+	postedAt, err := model.ParseEventDate(record.EventDate)
+	if err != nil {
+		return err
+	}
+
+	if !w.inAccount || record.AccountID != w.accountID {
+		w.endAccount()
+		w.startAccount(record.AccountID, postedAt)
+	}
+	w.ledger += record.Balance
+
+	name := record.OperationType
+	if name == "" {
+		name = "Operation type " + strconv.FormatUint(uint64(record.OperationTypeID), 10)
+	}
+
+	fmt.Fprintf(w.buf, "<STMTTRN><TRNTYPE>%s</TRNTYPE><DTPOSTED>%s</DTPOSTED><TRNAMT>%s</TRNAMT><FITID>%d</FITID><NAME>%s</NAME></STMTTRN>\n",
+		ofxTransactionType(record), ofxDate(postedAt), record.Amount.String(), record.TransactionID, ofxText(name))
+	return nil
+}
+
+// Close ends the open statement and the document, and flushes
+func (w *OFXWriter) Close() error {
+	w.start()
+	w.endAccount()
+	w.buf.WriteString("</CREDITCARDMSGSRSV1>\n</OFX>\n")
+	return w.Flush()
+}
+
+func (w *OFXWriter) start() {
+	if w.started {
+		return
+	}
+	w.started = true
+
+	w.buf.WriteString(ofxHeader)
+	fmt.Fprintf(w.buf, "<OFX>\n<SIGNONMSGSRSV1><SONRS>%s<DTSERVER>%s</DTSERVER><LANGUAGE>ENG</LANGUAGE></SONRS></SIGNONMSGSRSV1>\n<CREDITCARDMSGSRSV1>\n",
+		ofxStatusOK, ofxDate(w.generatedAt))
+}
+
+func (w *OFXWriter) startAccount(accountID uint, firstPostedAt time.Time) {
+	w.inAccount = true
+	w.accountID = accountID
+	w.ledger = 0
+	w.statements++
+
+	fmt.Fprintf(w.buf, "<CCSTMTTRNRS><TRNUID>%d</TRNUID>%s<CCSTMTRS><CURDEF>%s</CURDEF><CCACCTFROM><ACCTID>%d</ACCTID></CCACCTFROM>\n<BANKTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>\n",
+		w.statements, ofxStatusOK, ofxCurrency, accountID, ofxDate(firstPostedAt), ofxDate(w.generatedAt))
+}
+
+func (w *OFXWriter) endAccount() {
+	if !w.inAccount {
+		return
+	}
+	w.inAccount = false
+
+	fmt.Fprintf(w.buf, "</BANKTRANLIST><LEDGERBAL><BALAMT>%s</BALAMT><DTASOF>%s</DTASOF></LEDGERBAL></CCSTMTRS></CCSTMTTRNRS>\n",
+		w.ledger.String(), ofxDate(w.generatedAt))
+}
+
+// ofxTransactionType maps the operation type of a record to an OFX transaction type
+func ofxTransactionType(record Record) string {
+	switch {
+	case record.OperationTypeID == model.Interest:
+		return "INT"
+	case record.OperationTypeID == model.LateFee:
+		return "FEE"
+	case record.Amount < 0:
+		return "DEBIT"
+	default:
+		return "CREDIT"
+	}
+}
+
+// ofxDate formats a time as an OFX date time in IST
+func ofxDate(t time.Time) string {
+	return t.In(model.IST).Format("20060102150405.000") + "[+5.30:IST]"
+}
+
+// ofxText escapes text for use in an OFX element
+func ofxText(value string) string {
+	var escaped strings.Builder
+	_ = xml.EscapeText(&escaped, []byte(value))
+	return escaped.String()
+}
@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Supported log formats
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// sensitiveKeys are attribute keys whose values are always redacted, in any group. Document numbers
+// keep their last characters, credentials are masked entirely.
+var sensitiveKeys = map[string]func(string) string{
+	"document_number": Redact,
+	"password":        mask,
+	"secret":          mask,
+	"authorization":   mask,
+}
+
+type contextKey struct{}
+
+// New returns a logger writing to out in the given format, logging records at level or above.
+// Attributes with a sensitive key are redacted.
+func New(out io.Writer, format string, level string) (*slog.Logger, error) {
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("unknown log level %q", level)
+	}
+
+	options := &slog.HandlerOptions{Level: logLevel, ReplaceAttr: redactAttr}
+	switch format {
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(out, options)), nil
+	case FormatText:
+		return slog.New(slog.NewTextHandler(out, options)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+}
+
+// WithLogger returns a copy of ctx carrying the logger
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or the default logger when it carries none
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// Redact masks a sensitive value. Only the last two characters of long enough values are kept, which
+// is enough to tell values apart while investigating without revealing them.
+func Redact(value string) string {
+	if len(value) < 6 {
+		return mask(value)
+	}
+	return strings.Repeat("*", len(value)-2) + value[len(value)-2:]
+}
+
+func mask(value string) string {
+	return strings.Repeat("*", len(value))
+}
+
+func redactAttr(groups []string, attr slog.Attr) slog.Attr {
+	redact, ok := sensitiveKeys[strings.ToLower(attr.Key)]
+	if ok && attr.Value.Kind() != slog.KindGroup {
+		return slog.String(attr.Key, redact(attr.Value.Resolve().String()))
+	}
+	return attr
+}
@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := New(&out, FormatJSON, "warn")
+	require.NoError(t, err)
+
+	logger.Info("not logged")
+	logger.Warn("account created", "document_number", "12345678900", "password", "s3cret",
+		slog.Group("request", "Authorization", "Bearer token"), "account_id", 7)
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &record))
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "account created", record["msg"])
+	assert.Equal(t, "*********00", record["document_number"])
+	assert.Equal(t, "******", record["password"])
+	assert.Equal(t, map[string]interface{}{"Authorization": "************"}, record["request"])
+	assert.Equal(t, float64(7), record["account_id"])
+
+	out.Reset()
+	logger, err = New(&out, FormatText, "debug")
+	require.NoError(t, err)
+	logger.Debug("looking up account", "document_number", "12345678900")
+	assert.True(t, strings.HasSuffix(out.String(), "level=DEBUG msg=\"looking up account\" document_number=*********00\n"), out.String())
+}
+
+func TestNew_Invalid(t *testing.T) {
+	_, err := New(&bytes.Buffer{}, FormatJSON, "verbose")
+	assert.EqualError(t, err, `unknown log level "verbose"`)
+
+	_, err = New(&bytes.Buffer{}, "xml", "info")
+	assert.EqualError(t, err, `unknown log format "xml"`)
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Same(t, slog.Default(), FromContext(context.Background()))
+
+	logger := slog.New(slog.NewJSONHandler(&bytes.Buffer{}, nil))
+	assert.Same(t, logger, FromContext(WithLogger(context.Background(), logger)))
+}
+
+func TestRedact(t *testing.T) {
+	assert.Equal(t, "", Redact(""))
+	assert.Equal(t, "*****", Redact("12345"))
+	assert.Equal(t, "****56", Redact("123456"))
+	assert.Equal(t, "************34", Redact("12345678000134"))
+}
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+)
+
+// Namespace prefixes the name of every metric of the application
+const Namespace = "pismo"
+
+// Registry holds the metrics of the application together with the Go runtime and process metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken to handle HTTP requests, by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Time taken by database queries, by operation, table and whether they failed.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table", "status"})
+
+	transactionsCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "transactions_created_total",
+		Help:      "Transactions created, by operation type. A purchase with installments counts once.",
+	}, []string{"operation_type_id"})
+
+	amountDischarged = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "amount_discharged_total",
+		Help:      "Debt paid off by credit vouchers, in major currency units.",
+	})
+
+	accountsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "accounts_created_total",
+		Help:      "Accounts created.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestDuration,
+		dbQueryDuration,
+		transactionsCreated,
+		amountDischarged,
+		accountsCreated,
+	)
+}
+
+// Handler serves the metrics of Registry in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObserveHTTPRequest records the duration of a handled request. Route is the route pattern, not the
+// path, so that IDs in paths do not create a series per resource.
+func ObserveHTTPRequest(method string, route string, status int, duration time.Duration) {
+	httpRequestDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+// ObserveQuery records the duration of a database query
+func ObserveQuery(operation string, table string, failed bool, duration time.Duration) {
+	status := "ok"
+	if failed {
+		status = "error"
+	}
+	dbQueryDuration.WithLabelValues(operation, table, status).Observe(duration.Seconds())
+}
+
+// TransactionsCreated counts transactions once they are committed
+func TransactionsCreated(transactions ...model.Transaction) {
+	for _, transaction := range transactions {
+		transactionsCreated.WithLabelValues(strconv.FormatUint(uint64(transaction.OperationTypeId), 10)).Inc()
+	}
+}
+
+// Discharged adds debt paid off by a credit voucher once it is committed
+func Discharged(amount model.Money) {
+	if amount > 0 {
+		amountDischarged.Add(float64(amount.MinorUnits()) / math.Pow10(model.MoneyScale))
+	}
+}
+
+// AccountCreated counts an account once it is committed
+func AccountCreated() {
+	accountsCreated.Inc()
+}
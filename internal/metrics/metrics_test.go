@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"gorm.io/gorm"
+)
+
+func TestBusinessCounters(t *testing.T) {
+	purchases := testutil.ToFloat64(transactionsCreated.WithLabelValues("1"))
+	vouchers := testutil.ToFloat64(transactionsCreated.WithLabelValues("4"))
+	discharged := testutil.ToFloat64(amountDischarged)
+	accounts := testutil.ToFloat64(accountsCreated)
+
+	TransactionsCreated(
+		model.Transaction{OperationTypeId: model.NormalPurchase},
+		model.Transaction{OperationTypeId: model.NormalPurchase},
+		model.Transaction{OperationTypeId: model.CreditVoucher},
+	)
+	Discharged(model.MustParseMoney("60.25"))
+	Discharged(0)
+	AccountCreated()
+
+	assert.Equal(t, purchases+2, testutil.ToFloat64(transactionsCreated.WithLabelValues("1")))
+	assert.Equal(t, vouchers+1, testutil.ToFloat64(transactionsCreated.WithLabelValues("4")))
+	assert.InDelta(t, discharged+60.25, testutil.ToFloat64(amountDischarged), 1e-9)
+	assert.Equal(t, accounts+1, testutil.ToFloat64(accountsCreated))
+}
+
+type card struct {
+	ID     uint
+	Number string
+}
+
+func TestGormPlugin(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	require.NoError(t, db.Use(GormPlugin{}))
+
+	require.NoError(t, db.Exec("CREATE TABLE cards (id integer PRIMARY KEY, number varchar(19))").Error)
+
+	before := testutil.CollectAndCount(dbQueryDuration)
+	require.NoError(t, db.Create(&card{ID: 1, Number: "4111111111111111"}).Error)
+
+	var found card
+	require.NoError(t, db.First(&found, 1).Error)
+	assert.ErrorIs(t, db.First(&found, 2).Error, gorm.ErrRecordNotFound)
+	assert.Error(t, db.Create(&card{ID: 1}).Error)
+
+	// the create, the queries and the failed create add one series each, not found is not a failure
+	assert.Equal(t, before+3, testutil.CollectAndCount(dbQueryDuration))
+
+	body := scrape(t)
+	assert.Contains(t, body, `pismo_db_query_duration_seconds_count{operation="create",status="ok",table="cards"} 1`)
+	assert.Contains(t, body, `pismo_db_query_duration_seconds_count{operation="query",status="ok",table="cards"} 2`)
+	assert.Contains(t, body, `pismo_db_query_duration_seconds_count{operation="create",status="error",table="cards"} 1`)
+	assert.Contains(t, body, `pismo_db_query_duration_seconds_count{operation="raw",status="ok",table="unknown"} 1`)
+}
+
+func TestHandler(t *testing.T) {
+	ObserveHTTPRequest(http.MethodGet, "/accounts/:accountId", http.StatusOK, 20*time.Millisecond)
+	ObserveHTTPRequest(http.MethodGet, "/accounts/:accountId", http.StatusOK, 3*time.Second)
+
+	body := scrape(t)
+	assert.Contains(t, body, "# TYPE pismo_http_request_duration_seconds histogram")
+	assert.Contains(t, body, `pismo_http_request_duration_seconds_bucket{method="GET",route="/accounts/:accountId",status="200",le="0.025"} 1`)
+	assert.Contains(t, body, `pismo_http_request_duration_seconds_count{method="GET",route="/accounts/:accountId",status="200"} 2`)
+	assert.Contains(t, body, "# TYPE pismo_accounts_created_total counter")
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func scrape(t *testing.T) string {
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	body, err := io.ReadAll(w.Body)
+	require.NoError(t, err)
+	return string(body)
+}
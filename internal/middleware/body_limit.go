@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxBodySize rejects requests declaring a body larger than limit bytes. Bodies sent without a
+// Content-Length are cut off at the limit, so reading past it fails and the request is rejected when
+// its body is parsed.
+func MaxBodySize(limit int64) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.Request.ContentLength > limit {
+			ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error_msg": fmt.Sprintf("Request body can not be larger than %d bytes", limit),
+				"msg":       "Not able to process request",
+			})
+			return
+		}
+
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, limit)
+		ctx.Next()
+	}
+}
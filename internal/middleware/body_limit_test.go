@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxBodySize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		chunked        bool
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:           "Body Within Limit",
+			body:           `{"amount":10}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   map[string]interface{}{"read": float64(13)},
+		},
+		{
+			name:           "Declared Body Too Large",
+			body:           `{"amount":10,"description":"too long"}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Request body can not be larger than 16 bytes",
+				"msg":       "Not able to process request",
+			},
+		},
+		{
+			name:           "Chunked Body Too Large",
+			body:           `{"amount":10,"description":"too long"}`,
+			chunked:        true,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]interface{}{"error": "http: request body too large"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.POST("/transactions", MaxBodySize(16), func(ctx *gin.Context) {
+				body, err := io.ReadAll(ctx.Request.Body)
+				if err != nil {
+					ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+				ctx.JSON(http.StatusOK, gin.H{"read": len(body)})
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/transactions", strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedBody, response)
+		})
+	}
+}
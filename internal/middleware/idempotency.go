@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vamshi1997/pismo-assessment/internal/logging"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client's idempotency key
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotencyReplayedHeader is set on responses which were replayed from the key store
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+
+	// idempotencyKeyTTL is how long a completed key is kept before it can be used again
+	idempotencyKeyTTL = 24 * time.Hour
+
+	// idempotencyKeyLease is how long a key stays reserved for a request which did not finish. It
+	// outlasts any request, so a key still pending after it belongs to a request which never will,
+	// e.g. because the process crashed, and a retry can take it over.
+	idempotencyKeyLease = 5 * time.Minute
+)
+
+// responseRecorder keeps a copy of everything written to the response
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency makes requests carrying an Idempotency-Key header safe to retry. The first request with
+// a key is processed and its response stored. A retry with the same key, path and body gets the stored
+// response back, while a retry with the same key but a different path or body, or one sent while the
+// first request is still running, gets a conflict error. Requests without the header are processed as
+// usual. Server errors and panics are not stored, so the client can retry them with the same key, and
+// a key left pending by a request which never finished can be taken over once its lease is over.
+func Idempotency(r repo.IRepository) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			ctx.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error_msg": "Idempotency key can not be longer than 255 characters",
+				"msg":       "Not able to process request",
+			})
+			return
+		}
+
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":     err.Error(),
+				"error_msg": "Invalid request body",
+				"msg":       "Not able to process request",
+			})
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		requestHash := hashRequest(ctx.Request.Method, ctx.Request.URL.Path, body)
+		requestCtx := ctx.Request.Context()
+
+		existing, err := r.GetIdempotencyKey(requestCtx, key)
+		if err != nil {
+			abortInternalError(ctx, err)
+			return
+		}
+
+		// expired keys are forgotten and can be used for a new request
+		if existing != nil && expired(existing) {
+			if err = r.DeleteIdempotencyKey(requestCtx, key); err != nil {
+				abortInternalError(ctx, err)
+				return
+			}
+			existing = nil
+		}
+
+		if existing == nil {
+			_, err = r.CreateIdempotencyKey(requestCtx, model.IdempotencyKey{Key: key, RequestHash: requestHash})
+			if err != nil {
+				// another request may have reserved the same key in the meantime
+				if existing, _ = r.GetIdempotencyKey(requestCtx, key); existing == nil {
+					abortInternalError(ctx, err)
+					return
+				}
+			}
+		}
+
+		if existing != nil {
+			replay(ctx, existing, requestHash)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+
+		// a handler which panics never completes the key, so it is released for the client to retry
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				doneCtx := context.WithoutCancel(requestCtx)
+				if err := r.DeleteIdempotencyKey(doneCtx, key); err != nil {
+					logging.FromContext(doneCtx).Error("error while releasing idempotency key", "error", err)
+				}
+				panic(recovered)
+			}
+		}()
+
+		ctx.Next()
+
+		// the request is done, so a client which went away must not keep the key from being released
+		// or completed
+		doneCtx := context.WithoutCancel(requestCtx)
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			if err = r.DeleteIdempotencyKey(doneCtx, key); err != nil {
+				logging.FromContext(doneCtx).Error("error while releasing idempotency key", "error", err)
+			}
+			return
+		}
+
+		if err = r.CompleteIdempotencyKey(doneCtx, key, status, recorder.body.Bytes()); err != nil {
+			logging.FromContext(doneCtx).Error("error while storing idempotent response", "error", err)
+		}
+	}
+}
+
+// expired tells if a stored key can be forgotten: a completed key once its TTL is over, and a pending
+// key once its lease is
+func expired(key *model.IdempotencyKey) bool {
+	if key.StatusCode == 0 {
+		return time.Since(key.CreatedAt) > idempotencyKeyLease
+	}
+	return time.Since(key.CreatedAt) > idempotencyKeyTTL
+}
+
+// replay answers a request whose key was already used
+func replay(ctx *gin.Context, existing *model.IdempotencyKey, requestHash string) {
+	if existing.RequestHash != requestHash {
+		ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"error_msg": "Idempotency key was already used with a different request",
+			"msg":       "Not able to process request",
+		})
+		return
+	}
+
+	if existing.StatusCode == 0 {
+		ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"error_msg": "A request with this idempotency key is still being processed",
+			"msg":       "Not able to process request",
+		})
+		return
+	}
+
+	ctx.Header(IdempotencyReplayedHeader, "true")
+	ctx.Data(existing.StatusCode, "application/json; charset=utf-8", existing.ResponseBody)
+	ctx.Abort()
+}
+
+func abortInternalError(ctx *gin.Context, err error) {
+	ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+		"error":     err.Error(),
+		"error_msg": "Internal Server Error",
+		"msg":       "Not able to process request",
+	})
+}
+
+// hashRequest fingerprints the method, path and body a key was used with. The path is the one requested,
+// not the route, so a key used on /transactions/1/reversal does not match /transactions/2/reversal.
+func hashRequest(method string, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(path))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,313 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo/mock"
+	"gorm.io/gorm"
+)
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const (
+		body      = `{"account_id":1,"operation_type_id":4,"amount":10}`
+		otherBody = `{"account_id":1,"operation_type_id":4,"amount":20}`
+	)
+	requestHash := hashRequest(http.MethodPost, "/transactions", []byte(body))
+
+	tests := []struct {
+		name           string
+		key            string
+		body           string
+		handlerStatus  int
+		mockBehavior   func(m *mock.MockIRepository)
+		expectedStatus int
+		expectedCalls  int
+		expectedReplay bool
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:           "Request Without Key",
+			key:            "",
+			body:           body,
+			handlerStatus:  http.StatusOK,
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusOK,
+			expectedCalls:  1,
+			expectedBody:   map[string]interface{}{"transaction_id": float64(1)},
+		},
+		{
+			name:          "First Request Stores Response",
+			key:           "key-1",
+			body:          body,
+			handlerStatus: http.StatusOK,
+			mockBehavior: func(m *mock.MockIRepository) {
+				gomock.InOrder(
+					m.EXPECT().GetIdempotencyKey(gomock.Any(), "key-1").Return(nil, nil),
+					m.EXPECT().
+						CreateIdempotencyKey(gomock.Any(), model.IdempotencyKey{Key: "key-1", RequestHash: requestHash}).
+						Return(&model.IdempotencyKey{Key: "key-1", RequestHash: requestHash}, nil),
+					m.EXPECT().
+						CompleteIdempotencyKey(gomock.Any(), "key-1", http.StatusOK, []byte(`{"transaction_id":1}`)).
+						Return(nil),
+				)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCalls:  1,
+			expectedBody:   map[string]interface{}{"transaction_id": float64(1)},
+		},
+		{
+			name: "Replay Returns Stored Response",
+			key:  "key-1",
+			body: body,
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetIdempotencyKey(gomock.Any(), "key-1").Return(&model.IdempotencyKey{
+					Model:        gorm.Model{CreatedAt: time.Now()},
+					Key:          "key-1",
+					RequestHash:  requestHash,
+					StatusCode:   http.StatusOK,
+					ResponseBody: []byte(`{"transaction_id":1}`),
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCalls:  0,
+			expectedReplay: true,
+			expectedBody:   map[string]interface{}{"transaction_id": float64(1)},
+		},
+		{
+			name: "Same Key With Different Body",
+			key:  "key-1",
+			body: otherBody,
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().GetIdempotencyKey(gomock.Any(), "key-1").Return(&model.IdempotencyKey{
+					Model:        gorm.Model{CreatedAt: time.Now()},
+					Key:          "key-1",
+					RequestHash:  requestHash,
+					StatusCode:   http.StatusOK,
+					ResponseBody: []byte(`{"transaction_id":1}`),
+				}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedCalls:  0,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Idempotency key was already used with a different request",
+				"msg":       "Not able to process request",
+			},
+		},
+		{
+			name: "Same Key While First Request Is Running",
+			key:  "key-1",
+			body: body,
+			mockBehavior: func(m *mock.MockIRepository) {
+				gomock.InOrder(
+					m.EXPECT().GetIdempotencyKey(gomock.Any(), "key-1").Return(nil, nil),
+					m.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(nil, errors.New("duplicate entry")),
+					m.EXPECT().GetIdempotencyKey(gomock.Any(), "key-1").Return(&model.IdempotencyKey{
+						Model:       gorm.Model{CreatedAt: time.Now()},
+						Key:         "key-1",
+						RequestHash: requestHash,
+					}, nil),
+				)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedCalls:  0,
+			expectedBody: map[string]interface{}{
+				"error_msg": "A request with this idempotency key is still being processed",
+				"msg":       "Not able to process request",
+			},
+		},
+		{
+			name:          "Expired Key Is Used Again",
+			key:           "key-1",
+			body:          otherBody,
+			handlerStatus: http.StatusOK,
+			mockBehavior: func(m *mock.MockIRepository) {
+				gomock.InOrder(
+					m.EXPECT().GetIdempotencyKey(gomock.Any(), "key-1").Return(&model.IdempotencyKey{
+						Model:       gorm.Model{CreatedAt: time.Now().Add(-48 * time.Hour)},
+						Key:         "key-1",
+						RequestHash: requestHash,
+						StatusCode:  http.StatusOK,
+					}, nil),
+					m.EXPECT().DeleteIdempotencyKey(gomock.Any(), "key-1").Return(nil),
+					m.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(&model.IdempotencyKey{Key: "key-1"}, nil),
+					m.EXPECT().CompleteIdempotencyKey(gomock.Any(), "key-1", http.StatusOK, gomock.Any()).Return(nil),
+				)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCalls:  1,
+			expectedBody:   map[string]interface{}{"transaction_id": float64(1)},
+		},
+		{
+			name:          "Stale Pending Key Is Taken Over",
+			key:           "key-1",
+			body:          body,
+			handlerStatus: http.StatusOK,
+			mockBehavior: func(m *mock.MockIRepository) {
+				gomock.InOrder(
+					m.EXPECT().GetIdempotencyKey(gomock.Any(), "key-1").Return(&model.IdempotencyKey{
+						Model:       gorm.Model{CreatedAt: time.Now().Add(-idempotencyKeyLease - time.Second)},
+						Key:         "key-1",
+						RequestHash: requestHash,
+					}, nil),
+					m.EXPECT().DeleteIdempotencyKey(gomock.Any(), "key-1").Return(nil),
+					m.EXPECT().CreateIdempotencyKey(gomock.Any(), model.IdempotencyKey{Key: "key-1", RequestHash: requestHash}).
+						Return(&model.IdempotencyKey{Key: "key-1", RequestHash: requestHash}, nil),
+					m.EXPECT().CompleteIdempotencyKey(gomock.Any(), "key-1", http.StatusOK, []byte(`{"transaction_id":1}`)).Return(nil),
+				)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCalls:  1,
+			expectedBody:   map[string]interface{}{"transaction_id": float64(1)},
+		},
+		{
+			name:          "Server Error Releases Key",
+			key:           "key-2",
+			body:          body,
+			handlerStatus: http.StatusInternalServerError,
+			mockBehavior: func(m *mock.MockIRepository) {
+				gomock.InOrder(
+					m.EXPECT().GetIdempotencyKey(gomock.Any(), "key-2").Return(nil, nil),
+					m.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(&model.IdempotencyKey{Key: "key-2"}, nil),
+					m.EXPECT().DeleteIdempotencyKey(gomock.Any(), "key-2").Return(nil),
+				)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedCalls:  1,
+		},
+		{
+			name:           "Key Too Long",
+			key:            string(bytes.Repeat([]byte("k"), 256)),
+			body:           body,
+			mockBehavior:   func(m *mock.MockIRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCalls:  0,
+			expectedBody: map[string]interface{}{
+				"error_msg": "Idempotency key can not be longer than 255 characters",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+
+			calls := 0
+			router := gin.New()
+			router.POST("/transactions", Idempotency(mockRepo), func(ctx *gin.Context) {
+				calls++
+				if tt.handlerStatus >= http.StatusInternalServerError {
+					ctx.JSON(tt.handlerStatus, gin.H{"msg": "Not able to create transaction"})
+					return
+				}
+				ctx.JSON(tt.handlerStatus, gin.H{"transaction_id": 1})
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.key != "" {
+				req.Header.Set(IdempotencyKeyHeader, tt.key)
+			}
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedCalls, calls)
+			if tt.expectedReplay {
+				assert.Equal(t, "true", w.Header().Get(IdempotencyReplayedHeader))
+			}
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key])
+			}
+		})
+	}
+}
+
+func TestIdempotency_SameKeyOnAnotherPath(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the key was used to reverse transaction 1, with an empty body just like this request
+	mockRepo := mock.NewMockIRepository(ctrl)
+	mockRepo.EXPECT().GetIdempotencyKey(gomock.Any(), "key-1").Return(&model.IdempotencyKey{
+		Model:        gorm.Model{CreatedAt: time.Now()},
+		Key:          "key-1",
+		RequestHash:  hashRequest(http.MethodPost, "/transactions/1/reversal", []byte{}),
+		StatusCode:   http.StatusCreated,
+		ResponseBody: []byte(`{"transaction_id":3}`),
+	}, nil).Times(2)
+
+	calls := 0
+	router := gin.New()
+	router.POST("/transactions/:transactionId/reversal", Idempotency(mockRepo), func(ctx *gin.Context) {
+		calls++
+		ctx.JSON(http.StatusCreated, gin.H{"transaction_id": 4})
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/transactions/1/reversal", nil)
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "true", w.Header().Get(IdempotencyReplayedHeader))
+	assert.JSONEq(t, `{"transaction_id":3}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/transactions/2/reversal", nil)
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Empty(t, w.Header().Get(IdempotencyReplayedHeader))
+	assert.Contains(t, w.Body.String(), "Idempotency key was already used with a different request")
+	assert.Equal(t, 0, calls)
+}
+
+func TestIdempotency_PanicReleasesKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockIRepository(ctrl)
+	gomock.InOrder(
+		mockRepo.EXPECT().GetIdempotencyKey(gomock.Any(), "key-1").Return(nil, nil),
+		mockRepo.EXPECT().CreateIdempotencyKey(gomock.Any(), gomock.Any()).Return(&model.IdempotencyKey{Key: "key-1"}, nil),
+		mockRepo.EXPECT().DeleteIdempotencyKey(gomock.Any(), "key-1").Return(nil),
+	)
+
+	router := gin.New()
+	router.POST("/transactions", Idempotency(mockRepo), func(ctx *gin.Context) {
+		panic("handler bug")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewBufferString(`{"amount":10}`))
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+
+	// the panic is passed on to whatever recovers from it, once the key is released
+	assert.PanicsWithValue(t, "handler bug", func() {
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	})
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/metrics"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Metrics())
+	router.GET("/statements/:statementId", func(ctx *gin.Context) {
+		if ctx.Param("statementId") == "0" {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		ctx.Status(http.StatusOK)
+	})
+
+	for _, path := range []string{"/statements/1", "/statements/2", "/statements/0", "/unknown/1"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+
+	expected := []string{
+		`pismo_http_request_duration_seconds_count{method="GET",route="/statements/:statementId",status="200"} 2`,
+		`pismo_http_request_duration_seconds_count{method="GET",route="/statements/:statementId",status="404"} 1`,
+		`pismo_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`,
+	}
+	for _, line := range expected {
+		assert.True(t, strings.Contains(body, line), line)
+	}
+}
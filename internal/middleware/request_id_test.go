@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vamshi1997/pismo-assessment/internal/logging"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		requestID  string
+		path       string
+		expectedID string
+		status     int
+		level      string
+	}{
+		{
+			name:       "Valid Incoming ID",
+			requestID:  "req-1234",
+			path:       "/accounts/1",
+			expectedID: "req-1234",
+			status:     http.StatusOK,
+			level:      "INFO",
+		},
+		{
+			name:   "Generated ID",
+			path:   "/accounts/1",
+			status: http.StatusOK,
+			level:  "INFO",
+		},
+		{
+			name:      "Invalid Incoming ID",
+			requestID: "req 1234\n{\"level\":\"ERROR\"}",
+			path:      "/accounts/1",
+			status:    http.StatusOK,
+			level:     "INFO",
+		},
+		{
+			name:      "Too Long Incoming ID",
+			requestID: strings.Repeat("a", maxRequestIDLength+1),
+			path:      "/accounts/1",
+			status:    http.StatusOK,
+			level:     "INFO",
+		},
+		{
+			name:       "Server Error",
+			requestID:  "req-5678",
+			path:       "/accounts/500",
+			expectedID: "req-5678",
+			status:     http.StatusInternalServerError,
+			level:      "ERROR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&out, nil))
+
+			router := gin.New()
+			router.Use(RequestID(logger))
+			router.GET("/accounts/:accountId", func(ctx *gin.Context) {
+				logging.FromContext(ctx.Request.Context()).Info("handling")
+				if ctx.Param("accountId") == "500" {
+					ctx.Status(http.StatusInternalServerError)
+					return
+				}
+				ctx.String(http.StatusOK, ctx.GetString(RequestIDKey))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.requestID != "" {
+				req.Header.Set(RequestIDHeader, tt.requestID)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			requestID := w.Header().Get(RequestIDHeader)
+			if tt.expectedID != "" {
+				assert.Equal(t, tt.expectedID, requestID)
+			} else {
+				assert.Regexp(t, "^[0-9a-f]{32}$", requestID)
+			}
+			if tt.status == http.StatusOK {
+				assert.Equal(t, requestID, w.Body.String())
+			}
+
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			require.Len(t, lines, 2)
+
+			var handling, completed map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(lines[0]), &handling))
+			require.NoError(t, json.Unmarshal([]byte(lines[1]), &completed))
+			assert.Equal(t, requestID, handling[RequestIDKey])
+			assert.Equal(t, requestID, completed[RequestIDKey])
+			assert.Equal(t, "request completed", completed["msg"])
+			assert.Equal(t, tt.level, completed["level"])
+			assert.Equal(t, "/accounts/:accountId", completed["route"])
+			assert.Equal(t, tt.path, completed["path"])
+			assert.Equal(t, float64(tt.status), completed["status"])
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vamshi1997/pismo-assessment/internal/logging"
+	"github.com/vamshi1997/pismo-assessment/internal/tracing/tracingtest"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	tests := []struct {
+		name        string
+		path        string
+		traceparent string
+		spanName    string
+		status      int
+		code        codes.Code
+	}{
+		{
+			name:     "New Trace",
+			path:     "/accounts/1",
+			spanName: "GET /accounts/:accountId",
+			status:   http.StatusOK,
+			code:     codes.Unset,
+		},
+		{
+			name:        "Incoming Trace",
+			path:        "/accounts/1",
+			traceparent: "00-" + traceID + "-00f067aa0ba902b7-01",
+			spanName:    "GET /accounts/:accountId",
+			status:      http.StatusOK,
+			code:        codes.Unset,
+		},
+		{
+			name:     "Server Error",
+			path:     "/accounts/500",
+			spanName: "GET /accounts/:accountId",
+			status:   http.StatusInternalServerError,
+			code:     codes.Error,
+		},
+		{
+			name:     "Unmatched Route",
+			path:     "/cards/1",
+			spanName: "GET unmatched",
+			status:   http.StatusNotFound,
+			code:     codes.Unset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := tracingtest.Install(t)
+			var out bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&out, nil))
+
+			router := gin.New()
+			router.Use(RequestID(logger))
+			router.Use(Tracing())
+			router.GET("/accounts/:accountId", func(ctx *gin.Context) {
+				logging.FromContext(ctx.Request.Context()).Info("handling")
+				if ctx.Param("accountId") == "500" {
+					ctx.Status(http.StatusInternalServerError)
+					return
+				}
+				ctx.String(http.StatusOK, trace.SpanContextFromContext(ctx.Request.Context()).TraceID().String())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set(RequestIDHeader, "req-1234")
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			spans := exporter.GetSpans()
+			require.Len(t, spans, 1)
+			span := spans[0]
+			assert.Equal(t, tt.spanName, span.Name)
+			assert.Equal(t, trace.SpanKindServer, span.SpanKind)
+			assert.Equal(t, tt.code, span.Status.Code)
+			if tt.traceparent != "" {
+				assert.Equal(t, traceID, span.SpanContext.TraceID().String())
+				assert.Equal(t, "00f067aa0ba902b7", span.Parent.SpanID().String())
+			} else {
+				assert.False(t, span.Parent.IsValid())
+			}
+			if tt.status == http.StatusOK {
+				// the handler sees the span of the request in its context
+				assert.Equal(t, span.SpanContext.TraceID().String(), w.Body.String())
+			}
+
+			attributes := map[string]string{}
+			for _, kv := range span.Attributes {
+				attributes[string(kv.Key)] = kv.Value.Emit()
+			}
+			assert.Equal(t, "GET", attributes["http.request.method"])
+			assert.Equal(t, tt.path, attributes["url.path"])
+			assert.Equal(t, "req-1234", attributes[RequestIDKey])
+			assert.Equal(t, strconv.Itoa(tt.status), attributes["http.response.status_code"])
+
+			if tt.status == http.StatusNotFound {
+				return
+			}
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			var handling map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(lines[0]), &handling))
+			assert.Equal(t, span.SpanContext.TraceID().String(), handling["trace_id"])
+			assert.Equal(t, "req-1234", handling[RequestIDKey])
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package migration
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Drivers(t *testing.T) {
+	for _, driver := range []string{"mysql", "postgres", "sqlite"} {
+		t.Run(driver, func(t *testing.T) {
+			migrations, err := Load(driver)
+			require.NoError(t, err)
+			require.NotEmpty(t, migrations)
+
+			for i, migration := range migrations {
+				assert.Equal(t, uint(i+1), migration.Version, "versions should have no gaps")
+				assert.NotEmpty(t, statements(migration.Up))
+				assert.NotEmpty(t, statements(migration.Down))
+			}
+		})
+	}
+
+	_, err := Load("oracle")
+	assert.Error(t, err)
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/0002_add_cards.up.sql":        {Data: []byte("CREATE TABLE cards (id int);")},
+		"m/0002_add_cards.down.sql":      {Data: []byte("DROP TABLE cards;")},
+		"m/0001_initial_schema.up.sql":   {Data: []byte("CREATE TABLE accounts (id int);")},
+		"m/0001_initial_schema.down.sql": {Data: []byte("DROP TABLE accounts;")},
+	}
+
+	migrations, err := load(fsys, "m")
+	require.NoError(t, err)
+	assert.Equal(t, []Migration{
+		{Version: 1, Name: "initial_schema", Up: "CREATE TABLE accounts (id int);", Down: "DROP TABLE accounts;"},
+		{Version: 2, Name: "add_cards", Up: "CREATE TABLE cards (id int);", Down: "DROP TABLE cards;"},
+	}, migrations)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		fsys        fstest.MapFS
+		expectedErr string
+	}{
+		{
+			name:        "Missing Up File",
+			fsys:        fstest.MapFS{"m/0001_initial.down.sql": {Data: []byte("DROP TABLE accounts;")}},
+			expectedErr: "migration 1_initial has no up file",
+		},
+		{
+			name: "Conflicting Names",
+			fsys: fstest.MapFS{
+				"m/0001_initial.up.sql": {Data: []byte("CREATE TABLE accounts (id int);")},
+				"m/0001_other.down.sql": {Data: []byte("DROP TABLE accounts;")},
+			},
+			expectedErr: "migration 1 is named both initial and other",
+		},
+		{
+			name:        "No Direction",
+			fsys:        fstest.MapFS{"m/0001_initial.sql": {Data: []byte("")}},
+			expectedErr: "migration file 0001_initial.sql should end with .up.sql or .down.sql",
+		},
+		{
+			name:        "No Version",
+			fsys:        fstest.MapFS{"m/initial.up.sql": {Data: []byte("")}},
+			expectedErr: "migration file initial.up.sql should be named <version>_<name>.up.sql",
+		},
+		{
+			name:        "Zero Version",
+			fsys:        fstest.MapFS{"m/0000_initial.up.sql": {Data: []byte("")}},
+			expectedErr: "migration file 0000_initial.up.sql should start with a positive version",
+		},
+		{
+			name:        "Not SQL",
+			fsys:        fstest.MapFS{"m/README.md": {Data: []byte("")}},
+			expectedErr: "migration file README.md is not a .sql file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := load(tt.fsys, "m")
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestStatements(t *testing.T) {
+	content := `-- accounts and their cards
+CREATE TABLE accounts (
+    id int,
+    name varchar(255) DEFAULT 'a;b'
+);
+
+CREATE INDEX idx_accounts_name ON accounts (name);
+UPDATE accounts SET name = 'x'`
+
+	assert.Equal(t, []string{
+		"CREATE TABLE accounts (\n    id int,\n    name varchar(255) DEFAULT 'a;b'\n)",
+		"CREATE INDEX idx_accounts_name ON accounts (name)",
+		"UPDATE accounts SET name = 'x'",
+	}, statements(content))
+}
@@ -0,0 +1,217 @@
+CREATE TABLE IF NOT EXISTS `operation_types` (
+    `id` bigint unsigned,
+    `description` varchar(255) NOT NULL,
+    `amount_sign` bigint NOT NULL,
+    `discharge_eligible` boolean NOT NULL DEFAULT false,
+    `allows_installments` boolean NOT NULL DEFAULT false,
+    `internal` boolean NOT NULL DEFAULT false,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `accounts` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `document_number` varchar(255) NOT NULL,
+    `document_type` varchar(4) NOT NULL DEFAULT 'CPF',
+    `status` varchar(16) NOT NULL DEFAULT 'active',
+    `billing_day` bigint unsigned NOT NULL DEFAULT 1,
+    `credit_limit` decimal(19,2),
+    PRIMARY KEY (`id`),
+    INDEX `idx_accounts_deleted_at` (`deleted_at`),
+    UNIQUE INDEX `idx_accounts_document_number` (`document_number`)
+);
+
+CREATE TABLE IF NOT EXISTS `transactions` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `account_id` bigint unsigned NOT NULL,
+    `amount` decimal(19,2) NOT NULL,
+    `balance` decimal(19,2) NOT NULL,
+    `operation_type_id` bigint unsigned NOT NULL,
+    `event_date` timestamp(6) NOT NULL,
+    `reversed_transaction_id` bigint unsigned,
+    `installments` bigint unsigned NOT NULL DEFAULT 0,
+    `installment_number` bigint unsigned NOT NULL DEFAULT 0,
+    `parent_transaction_id` bigint unsigned,
+    `due_date` datetime(3) NULL,
+    `accrued_transaction_id` bigint unsigned,
+    PRIMARY KEY (`id`),
+    INDEX `idx_transactions_deleted_at` (`deleted_at`),
+    INDEX `idx_transactions_account_event_date` (`account_id`,`event_date`),
+    INDEX `idx_transactions_reversed_transaction_id` (`reversed_transaction_id`),
+    INDEX `idx_transactions_parent_transaction_id` (`parent_transaction_id`),
+    INDEX `idx_transactions_due_date` (`due_date`),
+    INDEX `idx_transactions_accrued_transaction_id` (`accrued_transaction_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `allocations` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `voucher_id` bigint unsigned NOT NULL,
+    `debit_id` bigint unsigned NOT NULL,
+    `amount` decimal(19,2) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_allocations_deleted_at` (`deleted_at`),
+    INDEX `idx_allocations_voucher_id` (`voucher_id`),
+    INDEX `idx_allocations_debit_id` (`debit_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `idempotency_keys` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `key` varchar(255) NOT NULL,
+    `request_hash` char(64) NOT NULL,
+    `status_code` bigint NOT NULL DEFAULT 0,
+    `response_body` longblob,
+    PRIMARY KEY (`id`),
+    INDEX `idx_idempotency_keys_deleted_at` (`deleted_at`),
+    UNIQUE INDEX `idx_idempotency_keys_key` (`key`)
+);
+
+CREATE TABLE IF NOT EXISTS `credit_limit_changes` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `account_id` bigint unsigned NOT NULL,
+    `previous_limit` decimal(19,2),
+    `new_limit` decimal(19,2),
+    `reason` varchar(255),
+    PRIMARY KEY (`id`),
+    INDEX `idx_credit_limit_changes_account_id` (`account_id`),
+    INDEX `idx_credit_limit_changes_deleted_at` (`deleted_at`)
+);
+
+CREATE TABLE IF NOT EXISTS `account_status_changes` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `account_id` bigint unsigned NOT NULL,
+    `from_status` varchar(16) NOT NULL,
+    `to_status` varchar(16) NOT NULL,
+    `reason_code` varchar(32) NOT NULL,
+    `changed_by` varchar(255) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_account_status_changes_deleted_at` (`deleted_at`),
+    INDEX `idx_account_status_changes_account_id` (`account_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `outbox_events` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `event_type` varchar(64) NOT NULL,
+    `aggregate_type` varchar(32) NOT NULL,
+    `aggregate_id` bigint unsigned NOT NULL,
+    `payload` longblob NOT NULL,
+    `attempts` bigint NOT NULL DEFAULT 0,
+    `last_error` text,
+    `published_at` datetime(3) NULL,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_outbox_events_published_at` (`published_at`)
+);
+
+CREATE TABLE IF NOT EXISTS `webhook_subscriptions` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `url` varchar(2048) NOT NULL,
+    `event_types` varchar(512) NOT NULL,
+    `secret` varchar(128) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_webhook_subscriptions_deleted_at` (`deleted_at`)
+);
+
+CREATE TABLE IF NOT EXISTS `webhook_deliveries` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `subscription_id` bigint unsigned NOT NULL,
+    `event_id` bigint unsigned NOT NULL,
+    `event_type` varchar(64) NOT NULL,
+    `payload` longblob NOT NULL,
+    `status` varchar(16) NOT NULL,
+    `attempts` bigint NOT NULL DEFAULT 0,
+    `next_attempt_at` datetime(3) NULL,
+    `last_error` text,
+    `delivered_at` datetime(3) NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_webhook_deliveries_subscription_event` (`subscription_id`,`event_id`),
+    INDEX `idx_webhook_deliveries_due` (`status`,`next_attempt_at`),
+    CONSTRAINT `fk_webhook_deliveries_subscription` FOREIGN KEY (`subscription_id`) REFERENCES `webhook_subscriptions`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `webhook_attempts` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `delivery_id` bigint unsigned NOT NULL,
+    `attempt_number` bigint NOT NULL,
+    `status_code` bigint NOT NULL DEFAULT 0,
+    `error` text,
+    `duration_ms` bigint NOT NULL DEFAULT 0,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_webhook_attempts_delivery_id` (`delivery_id`),
+    CONSTRAINT `fk_webhook_deliveries_attempt_log` FOREIGN KEY (`delivery_id`) REFERENCES `webhook_deliveries`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `statements` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `account_id` bigint unsigned NOT NULL,
+    `period_start` datetime(3) NOT NULL,
+    `period_end` datetime(3) NOT NULL,
+    `due_date` datetime(3) NOT NULL,
+    `opening_balance` decimal(19,2) NOT NULL,
+    `purchases` decimal(19,2) NOT NULL,
+    `withdrawals` decimal(19,2) NOT NULL,
+    `vouchers` decimal(19,2) NOT NULL,
+    `reversals` decimal(19,2) NOT NULL,
+    `interest` decimal(19,2) NOT NULL,
+    `fees` decimal(19,2) NOT NULL,
+    `other_debits` decimal(19,2) NOT NULL,
+    `other_credits` decimal(19,2) NOT NULL,
+    `closing_balance` decimal(19,2) NOT NULL,
+    `transaction_count` bigint NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_statements_deleted_at` (`deleted_at`),
+    UNIQUE INDEX `idx_statements_account_period` (`account_id`,`period_start`)
+);
+
+CREATE TABLE IF NOT EXISTS `accrual_rates` (
+    `operation_type_id` bigint unsigned,
+    `interest_rate_bps` bigint unsigned NOT NULL DEFAULT 0,
+    `late_fee` decimal(19,2) NOT NULL DEFAULT 0,
+    `grace_days` bigint unsigned NOT NULL DEFAULT 0,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`operation_type_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `accruals` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `debt_id` bigint unsigned NOT NULL,
+    `kind` varchar(16) NOT NULL,
+    `accrual_date` varchar(10) NOT NULL,
+    `transaction_id` bigint unsigned NOT NULL,
+    `amount` decimal(19,2) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_accruals_transaction_id` (`transaction_id`),
+    INDEX `idx_accruals_deleted_at` (`deleted_at`),
+    UNIQUE INDEX `idx_accruals_debt_kind_date` (`debt_id`,`kind`,`accrual_date`)
+);
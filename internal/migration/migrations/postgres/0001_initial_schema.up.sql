@@ -0,0 +1,217 @@
+CREATE TABLE IF NOT EXISTS "operation_types" (
+    "id" bigint,
+    "description" varchar(255) NOT NULL,
+    "amount_sign" bigint NOT NULL,
+    "discharge_eligible" boolean NOT NULL DEFAULT false,
+    "allows_installments" boolean NOT NULL DEFAULT false,
+    "internal" boolean NOT NULL DEFAULT false,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "accounts" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "document_number" varchar(255) NOT NULL,
+    "document_type" varchar(4) NOT NULL DEFAULT 'CPF',
+    "status" varchar(16) NOT NULL DEFAULT 'active',
+    "billing_day" bigint NOT NULL DEFAULT 1,
+    "credit_limit" decimal(19,2),
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_accounts_document_number" ON "accounts" ("document_number");
+CREATE INDEX IF NOT EXISTS "idx_accounts_deleted_at" ON "accounts" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "transactions" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "account_id" bigint NOT NULL,
+    "amount" decimal(19,2) NOT NULL,
+    "balance" decimal(19,2) NOT NULL,
+    "operation_type_id" bigint NOT NULL,
+    "event_date" timestamp(6) NOT NULL,
+    "reversed_transaction_id" bigint,
+    "installments" bigint NOT NULL DEFAULT 0,
+    "installment_number" bigint NOT NULL DEFAULT 0,
+    "parent_transaction_id" bigint,
+    "due_date" timestamptz,
+    "accrued_transaction_id" bigint,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_transactions_accrued_transaction_id" ON "transactions" ("accrued_transaction_id");
+CREATE INDEX IF NOT EXISTS "idx_transactions_due_date" ON "transactions" ("due_date");
+CREATE INDEX IF NOT EXISTS "idx_transactions_parent_transaction_id" ON "transactions" ("parent_transaction_id");
+CREATE INDEX IF NOT EXISTS "idx_transactions_reversed_transaction_id" ON "transactions" ("reversed_transaction_id");
+CREATE INDEX IF NOT EXISTS "idx_transactions_account_event_date" ON "transactions" ("account_id","event_date");
+CREATE INDEX IF NOT EXISTS "idx_transactions_deleted_at" ON "transactions" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "allocations" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "voucher_id" bigint NOT NULL,
+    "debit_id" bigint NOT NULL,
+    "amount" decimal(19,2) NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_allocations_debit_id" ON "allocations" ("debit_id");
+CREATE INDEX IF NOT EXISTS "idx_allocations_voucher_id" ON "allocations" ("voucher_id");
+CREATE INDEX IF NOT EXISTS "idx_allocations_deleted_at" ON "allocations" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "idempotency_keys" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "key" varchar(255) NOT NULL,
+    "request_hash" char(64) NOT NULL,
+    "status_code" bigint NOT NULL DEFAULT 0,
+    "response_body" bytea,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_idempotency_keys_deleted_at" ON "idempotency_keys" ("deleted_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_idempotency_keys_key" ON "idempotency_keys" ("key");
+
+CREATE TABLE IF NOT EXISTS "credit_limit_changes" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "account_id" bigint NOT NULL,
+    "previous_limit" decimal(19,2),
+    "new_limit" decimal(19,2),
+    "reason" varchar(255),
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_credit_limit_changes_account_id" ON "credit_limit_changes" ("account_id");
+CREATE INDEX IF NOT EXISTS "idx_credit_limit_changes_deleted_at" ON "credit_limit_changes" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "account_status_changes" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "account_id" bigint NOT NULL,
+    "from_status" varchar(16) NOT NULL,
+    "to_status" varchar(16) NOT NULL,
+    "reason_code" varchar(32) NOT NULL,
+    "changed_by" varchar(255) NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_account_status_changes_account_id" ON "account_status_changes" ("account_id");
+CREATE INDEX IF NOT EXISTS "idx_account_status_changes_deleted_at" ON "account_status_changes" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "outbox_events" (
+    "id" bigserial,
+    "event_type" varchar(64) NOT NULL,
+    "aggregate_type" varchar(32) NOT NULL,
+    "aggregate_id" bigint NOT NULL,
+    "payload" bytea NOT NULL,
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "last_error" text,
+    "published_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_outbox_events_published_at" ON "outbox_events" ("published_at");
+
+CREATE TABLE IF NOT EXISTS "webhook_subscriptions" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "url" varchar(2048) NOT NULL,
+    "event_types" varchar(512) NOT NULL,
+    "secret" varchar(128) NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_webhook_subscriptions_deleted_at" ON "webhook_subscriptions" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "webhook_deliveries" (
+    "id" bigserial,
+    "subscription_id" bigint NOT NULL,
+    "event_id" bigint NOT NULL,
+    "event_type" varchar(64) NOT NULL,
+    "payload" bytea NOT NULL,
+    "status" varchar(16) NOT NULL,
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "next_attempt_at" timestamptz,
+    "last_error" text,
+    "delivered_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_webhook_deliveries_subscription" FOREIGN KEY ("subscription_id") REFERENCES "webhook_subscriptions"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_due" ON "webhook_deliveries" ("status","next_attempt_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_webhook_deliveries_subscription_event" ON "webhook_deliveries" ("subscription_id","event_id");
+
+CREATE TABLE IF NOT EXISTS "webhook_attempts" (
+    "id" bigserial,
+    "delivery_id" bigint NOT NULL,
+    "attempt_number" bigint NOT NULL,
+    "status_code" bigint NOT NULL DEFAULT 0,
+    "error" text,
+    "duration_ms" bigint NOT NULL DEFAULT 0,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_webhook_deliveries_attempt_log" FOREIGN KEY ("delivery_id") REFERENCES "webhook_deliveries"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_webhook_attempts_delivery_id" ON "webhook_attempts" ("delivery_id");
+
+CREATE TABLE IF NOT EXISTS "statements" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "account_id" bigint NOT NULL,
+    "period_start" timestamptz NOT NULL,
+    "period_end" timestamptz NOT NULL,
+    "due_date" timestamptz NOT NULL,
+    "opening_balance" decimal(19,2) NOT NULL,
+    "purchases" decimal(19,2) NOT NULL,
+    "withdrawals" decimal(19,2) NOT NULL,
+    "vouchers" decimal(19,2) NOT NULL,
+    "reversals" decimal(19,2) NOT NULL,
+    "interest" decimal(19,2) NOT NULL,
+    "fees" decimal(19,2) NOT NULL,
+    "other_debits" decimal(19,2) NOT NULL,
+    "other_credits" decimal(19,2) NOT NULL,
+    "closing_balance" decimal(19,2) NOT NULL,
+    "transaction_count" bigint NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_statements_account_period" ON "statements" ("account_id","period_start");
+CREATE INDEX IF NOT EXISTS "idx_statements_deleted_at" ON "statements" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "accrual_rates" (
+    "operation_type_id" bigint,
+    "interest_rate_bps" bigint NOT NULL DEFAULT 0,
+    "late_fee" decimal(19,2) NOT NULL DEFAULT 0,
+    "grace_days" bigint NOT NULL DEFAULT 0,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("operation_type_id")
+);
+
+CREATE TABLE IF NOT EXISTS "accruals" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "debt_id" bigint NOT NULL,
+    "kind" varchar(16) NOT NULL,
+    "accrual_date" varchar(10) NOT NULL,
+    "transaction_id" bigint NOT NULL,
+    "amount" decimal(19,2) NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_accruals_debt_kind_date" ON "accruals" ("debt_id","kind","accrual_date");
+CREATE INDEX IF NOT EXISTS "idx_accruals_deleted_at" ON "accruals" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_accruals_transaction_id" ON "accruals" ("transaction_id");
@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS `accruals`;
+DROP TABLE IF EXISTS `accrual_rates`;
+DROP TABLE IF EXISTS `statements`;
+DROP TABLE IF EXISTS `webhook_attempts`;
+DROP TABLE IF EXISTS `webhook_deliveries`;
+DROP TABLE IF EXISTS `webhook_subscriptions`;
+DROP TABLE IF EXISTS `outbox_events`;
+DROP TABLE IF EXISTS `account_status_changes`;
+DROP TABLE IF EXISTS `credit_limit_changes`;
+DROP TABLE IF EXISTS `idempotency_keys`;
+DROP TABLE IF EXISTS `allocations`;
+DROP TABLE IF EXISTS `transactions`;
+DROP TABLE IF EXISTS `accounts`;
+DROP TABLE IF EXISTS `operation_types`;
@@ -0,0 +1,203 @@
+package migration
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SchemaMigration is the row written to schema_migrations for every applied migration
+type SchemaMigration struct {
+	Version   uint      `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null;type:varchar(255)"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// createSchemaMigrations is valid on every supported driver
+const createSchemaMigrations = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version bigint NOT NULL,
+    name varchar(255) NOT NULL,
+    applied_at timestamp NOT NULL,
+    PRIMARY KEY (version)
+)`
+
+// Status tells whether a migration was applied and when
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// Migrator applies and rolls back the migrations of the database's driver. Every migration runs in
+// a database transaction together with its schema_migrations row. MySQL commits DDL statements
+// implicitly, so there a migration which fails half way has to be repaired by hand.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+	now        func() time.Time
+}
+
+// New returns the migrator of the database, with the migrations of its driver
+func New(db *gorm.DB) (*Migrator, error) {
+	migrations, err := Load(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return NewWithMigrations(db, migrations), nil
+}
+
+// NewWithMigrations returns a migrator applying the given migrations, ordered by version
+func NewWithMigrations(db *gorm.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations, now: time.Now}
+}
+
+// Status returns every migration with whether it was applied
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Pending returns the migrations which were not applied yet, oldest first
+func (m *Migrator) Pending() ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+
+	return pending, nil
+}
+
+// Up applies pending migrations, oldest first. It applies at most steps migrations, or all of them
+// when steps is zero, and returns the ones it applied.
+func (m *Migrator) Up(steps int) ([]Migration, error) {
+	if err := m.createTable(); err != nil {
+		return nil, err
+	}
+
+	pending, err := m.Pending()
+	if err != nil {
+		return nil, err
+	}
+	if steps > 0 && steps < len(pending) {
+		pending = pending[:steps]
+	}
+
+	var done []Migration
+	for _, migration := range pending {
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := execute(tx, migration.Up); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: m.now()}).Error
+		})
+		if err != nil {
+			slog.Error("error while applying migration", "version", migration.Version, "name", migration.Name, "error", err)
+			return done, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		slog.Info("migration applied", "version", migration.Version, "name", migration.Name)
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Down rolls back the last steps applied migrations, newest first, and returns the ones it rolled back
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("steps should be positive")
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := execute(tx, migration.Down); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{}, migration.Version).Error
+		})
+		if err != nil {
+			slog.Error("error while rolling back migration", "version", migration.Version, "name", migration.Name, "error", err)
+			return done, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		slog.Info("migration rolled back", "version", migration.Version, "name", migration.Name)
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// createTable creates the schema_migrations table when it does not exist. Only Up does so, so that
+// looking at the status of a database never changes it.
+func (m *Migrator) createTable() error {
+	if err := m.db.Exec(createSchemaMigrations).Error; err != nil {
+		slog.Error("error while creating schema_migrations table", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applied returns the schema_migrations rows by version, none when the table does not exist yet
+func (m *Migrator) applied() (map[uint]SchemaMigration, error) {
+	if !m.db.Migrator().HasTable(&SchemaMigration{}) {
+		return map[uint]SchemaMigration{}, nil
+	}
+
+	var rows []SchemaMigration
+	if err := m.db.Order("version ASC").Find(&rows).Error; err != nil {
+		slog.Error("error while listing applied migrations", "error", err)
+		return nil, err
+	}
+
+	applied := make(map[uint]SchemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+func execute(tx *gorm.DB, content string) error {
+	for _, statement := range statements(content) {
+		if err := tx.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
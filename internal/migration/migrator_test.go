@@ -0,0 +1,118 @@
+package migration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var testMigrations = []Migration{
+	{Version: 1, Name: "create_accounts", Up: "CREATE TABLE accounts (id integer PRIMARY KEY);", Down: "DROP TABLE accounts;"},
+	{Version: 2, Name: "create_cards", Up: "CREATE TABLE cards (id integer PRIMARY KEY);\nCREATE INDEX idx_cards_id ON cards (id);", Down: "DROP TABLE cards;"},
+	{Version: 3, Name: "add_card_number", Up: "ALTER TABLE cards ADD COLUMN number varchar(19);", Down: "ALTER TABLE cards DROP COLUMN number;"},
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	return db
+}
+
+func newTestMigrator(t *testing.T, db *gorm.DB, migrations []Migration) *Migrator {
+	migrator := NewWithMigrations(db, migrations)
+	migrator.now = func() time.Time { return time.Date(2025, 2, 10, 10, 0, 0, 0, time.UTC) }
+	return migrator
+}
+
+func versions(migrations []Migration) []uint {
+	result := []uint{}
+	for _, migration := range migrations {
+		result = append(result, migration.Version)
+	}
+	return result
+}
+
+func TestMigrator_UpAndDown(t *testing.T) {
+	db := newTestDB(t)
+	migrator := newTestMigrator(t, db, testMigrations)
+
+	applied, err := migrator.Up(2)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{1, 2}, versions(applied))
+	assert.True(t, db.Migrator().HasTable("cards"))
+
+	pending, err := migrator.Pending()
+	require.NoError(t, err)
+	assert.Equal(t, []uint{3}, versions(pending))
+
+	applied, err = migrator.Up(0)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{3}, versions(applied))
+	assert.True(t, db.Migrator().HasColumn("cards", "number"))
+
+	applied, err = migrator.Up(0)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	rolledBack, err := migrator.Down(2)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{3, 2}, versions(rolledBack))
+	assert.False(t, db.Migrator().HasTable("cards"))
+	assert.True(t, db.Migrator().HasTable("accounts"))
+
+	statuses, err := migrator.Status()
+	require.NoError(t, err)
+	require.Len(t, statuses, 3)
+	assert.True(t, statuses[0].Applied)
+	assert.True(t, time.Date(2025, 2, 10, 10, 0, 0, 0, time.UTC).Equal(*statuses[0].AppliedAt))
+	assert.False(t, statuses[1].Applied)
+	assert.Nil(t, statuses[1].AppliedAt)
+	assert.False(t, statuses[2].Applied)
+
+	_, err = migrator.Down(0)
+	assert.EqualError(t, err, "steps should be positive")
+}
+
+func TestMigrator_FailedMigrationIsRolledBack(t *testing.T) {
+	db := newTestDB(t)
+	migrator := newTestMigrator(t, db, []Migration{
+		testMigrations[0],
+		{Version: 2, Name: "broken", Up: "CREATE TABLE cards (id integer PRIMARY KEY);\nCREATE TABLE accounts (id integer);", Down: "DROP TABLE cards;"},
+	})
+
+	applied, err := migrator.Up(0)
+	assert.ErrorContains(t, err, "migration 2_broken")
+	assert.Equal(t, []uint{1}, versions(applied))
+	assert.False(t, db.Migrator().HasTable("cards"))
+
+	pending, err := migrator.Pending()
+	require.NoError(t, err)
+	assert.Equal(t, []uint{2}, versions(pending))
+}
+
+func TestMigrator_InitialSchema(t *testing.T) {
+	db := newTestDB(t)
+	migrator, err := New(db)
+	require.NoError(t, err)
+
+	_, err = migrator.Up(0)
+	require.NoError(t, err)
+	for _, table := range []string{"accounts", "transactions", "outbox_events", "webhook_attempts", "statements", "accruals"} {
+		assert.True(t, db.Migrator().HasTable(table), table)
+	}
+
+	_, err = migrator.Down(len(migrator.migrations))
+	require.NoError(t, err)
+	assert.False(t, db.Migrator().HasTable("accounts"))
+	assert.True(t, db.Migrator().HasTable("schema_migrations"))
+}
@@ -1,11 +1,45 @@
 package model
 
 import (
+	"github.com/vamshi1997/pismo-assessment/internal/logging"
 	"gorm.io/gorm"
+	"log/slog"
 )
 
+// Account holds the document, the lifecycle status, the billing cycle and the credit limit of the
+// account holder. DocumentNumber is stored without formatting and DocumentType tells if it is a CPF or
+// a CNPJ. BillingDay is the day of the month its billing cycle closes on. A nil CreditLimit means the
+// account has no limit. AvailableLimit is not stored, it is computed from the limit and the account's
+// transactions.
 type Account struct {
 	gorm.Model
-	ID             uint   `json:"id" gorm:"primaryKey;autoIncrement"`
-	DocumentNumber string `json:"document_number" gorm:"uniqueIndex;not null;type:varchar(255)"`
+	ID             uint          `json:"id" gorm:"primaryKey;autoIncrement"`
+	DocumentNumber string        `json:"document_number" gorm:"uniqueIndex;not null;type:varchar(255)"`
+	DocumentType   string        `json:"document_type" gorm:"not null;type:varchar(4);default:'CPF'"`
+	Status         AccountStatus `json:"status" gorm:"not null;type:varchar(16);default:'active'"`
+	BillingDay     uint          `json:"billing_day" gorm:"not null;default:1"`
+	CreditLimit    *Money        `json:"credit_limit" gorm:"type:decimal(19,2)"`
+	AvailableLimit *Money        `json:"available_limit" gorm:"-"`
+}
+
+// AvailableLimitFor computes how much the account can still spend given the sum of the balances of its
+// transactions. Debt is negative and lowers the available limit, unapplied credit is positive and
+// raises it. It returns nil when the account has no limit.
+func (a Account) AvailableLimitFor(netBalance Money) *Money {
+	if a.CreditLimit == nil {
+		return nil
+	}
+
+	available := *a.CreditLimit + netBalance
+	return &available
+}
+
+// LogValue logs the account with its document number redacted
+func (a Account) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Uint64("id", uint64(a.ID)),
+		slog.String("document_number", logging.Redact(a.DocumentNumber)),
+		slog.String("document_type", a.DocumentType),
+		slog.String("status", string(a.Status)),
+	)
 }
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"gorm.io/gorm"
+)
+
+// AccountStatus is the lifecycle state of an account
+type AccountStatus string
+
+const (
+	// AccountActive accounts accept every kind of transaction
+	AccountActive AccountStatus = "active"
+	// AccountBlocked accounts only accept credits, so the holder can still pay what they owe
+	AccountBlocked AccountStatus = "blocked"
+	// AccountClosed accounts accept no transaction and can not be opened again
+	AccountClosed AccountStatus = "closed"
+)
+
+// Reason codes accepted when the status of an account is changed
+const (
+	ReasonCustomerRequest = "customer_request"
+	ReasonFraudSuspected  = "fraud_suspected"
+	ReasonLostOrStolen    = "lost_or_stolen"
+	ReasonDelinquency     = "delinquency"
+	ReasonIssueResolved   = "issue_resolved"
+	ReasonOther           = "other"
+)
+
+var statusReasons = map[string]bool{
+	ReasonCustomerRequest: true,
+	ReasonFraudSuspected:  true,
+	ReasonLostOrStolen:    true,
+	ReasonDelinquency:     true,
+	ReasonIssueResolved:   true,
+	ReasonOther:           true,
+}
+
+// statusTransitions lists the states an account can move to from each state
+var statusTransitions = map[AccountStatus][]AccountStatus{
+	AccountActive:  {AccountBlocked, AccountClosed},
+	AccountBlocked: {AccountActive, AccountClosed},
+	AccountClosed:  {},
+}
+
+// IsValidStatusReason tells if the reason code is one of the known reason codes
+func IsValidStatusReason(reasonCode string) bool {
+	return statusReasons[reasonCode]
+}
+
+// CanTransitionTo tells if an account in this state can be moved to the given state
+func (s AccountStatus) CanTransitionTo(to AccountStatus) bool {
+	for _, allowed := range statusTransitions[s] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsDebits tells if purchases and withdrawals can be made on an account in this state
+func (s AccountStatus) AllowsDebits() bool {
+	return s == AccountActive
+}
+
+// AllowsCredits tells if credit vouchers can be received by an account in this state
+func (s AccountStatus) AllowsCredits() bool {
+	return s == AccountActive || s == AccountBlocked
+}
+
+// AccountStatusChange records a state transition of an account, who made it and why. CreatedAt is when
+// the transition happened.
+type AccountStatusChange struct {
+	gorm.Model
+	ID         uint          `json:"id" gorm:"primaryKey;autoIncrement"`
+	AccountID  uint          `json:"account_id" gorm:"not null;index"`
+	FromStatus AccountStatus `json:"from_status" gorm:"not null;type:varchar(16)"`
+	ToStatus   AccountStatus `json:"to_status" gorm:"not null;type:varchar(16)"`
+	ReasonCode string        `json:"reason_code" gorm:"not null;type:varchar(32)"`
+	ChangedBy  string        `json:"changed_by" gorm:"not null;type:varchar(255)"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from     AccountStatus
+		to       AccountStatus
+		expected bool
+	}{
+		{from: AccountActive, to: AccountBlocked, expected: true},
+		{from: AccountActive, to: AccountClosed, expected: true},
+		{from: AccountActive, to: AccountActive, expected: false},
+		{from: AccountBlocked, to: AccountActive, expected: true},
+		{from: AccountBlocked, to: AccountClosed, expected: true},
+		{from: AccountBlocked, to: AccountBlocked, expected: false},
+		{from: AccountClosed, to: AccountActive, expected: false},
+		{from: AccountClosed, to: AccountBlocked, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestAccountStatus_Rules(t *testing.T) {
+	assert.True(t, AccountActive.AllowsDebits())
+	assert.True(t, AccountActive.AllowsCredits())
+	assert.False(t, AccountBlocked.AllowsDebits())
+	assert.True(t, AccountBlocked.AllowsCredits())
+	assert.False(t, AccountClosed.AllowsDebits())
+	assert.False(t, AccountClosed.AllowsCredits())
+}
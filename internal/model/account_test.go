@@ -0,0 +1,70 @@
+package model
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccount_AvailableLimitFor(t *testing.T) {
+	limit := MustParseMoney("1000")
+
+	tests := []struct {
+		name       string
+		account    Account
+		netBalance Money
+		expected   *Money
+	}{
+		{
+			name:       "No Limit",
+			account:    Account{ID: 1},
+			netBalance: MustParseMoney("-500"),
+			expected:   nil,
+		},
+		{
+			name:       "Debt Lowers Available Limit",
+			account:    Account{ID: 1, CreditLimit: &limit},
+			netBalance: MustParseMoney("-250.75"),
+			expected:   moneyPtr(MustParseMoney("749.25")),
+		},
+		{
+			name:       "Unapplied Credit Raises Available Limit",
+			account:    Account{ID: 1, CreditLimit: &limit},
+			netBalance: MustParseMoney("100"),
+			expected:   moneyPtr(MustParseMoney("1100")),
+		},
+		{
+			name:       "Debt Above A Lowered Limit",
+			account:    Account{ID: 1, CreditLimit: &limit},
+			netBalance: MustParseMoney("-1200"),
+			expected:   moneyPtr(MustParseMoney("-200")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.account.AvailableLimitFor(tt.netBalance))
+		})
+	}
+}
+
+func moneyPtr(m Money) *Money {
+	return &m
+}
+
+func TestAccount_LogValue(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	}))
+
+	logger.Info("account created", "account", Account{ID: 7, DocumentNumber: "12345678900", DocumentType: "CPF", Status: AccountActive})
+	assert.Equal(t, "level=INFO msg=\"account created\" account.id=7 account.document_number=*********00 account.document_type=CPF account.status=active\n", out.String())
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// AccrualInterest and AccrualLateFee are the kinds of charges the accrual job posts
+	AccrualInterest = "interest"
+	AccrualLateFee  = "late_fee"
+
+	// AccrualDateLayout is the format accrual dates are stored in, a calendar day in IST
+	AccrualDateLayout = "2006-01-02"
+
+	basisPointsPerUnit = 10000
+	daysPerYear        = 365
+)
+
+// AccrualRate configures the charges accrued on overdue debt of one operation type. InterestRateBps is
+// the yearly interest rate in basis points, charged daily on the outstanding balance. LateFee is charged
+// once, on the first day the debt is overdue. A debt is overdue GraceDays days after the day it was
+// billed on. Operation types without a rate never accrue anything.
+type AccrualRate struct {
+	OperationTypeID uint      `json:"operation_type_id" gorm:"primaryKey;autoIncrement:false"`
+	InterestRateBps uint      `json:"interest_rate_bps" gorm:"not null;default:0"`
+	LateFee         Money     `json:"late_fee" gorm:"not null;type:decimal(19,2);default:0"`
+	GraceDays       uint      `json:"grace_days" gorm:"not null;default:0"`
+	CreatedAt       time.Time `json:"-"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Accrual records a charge posted on a debt for an accrual date. A debt has at most one charge of each
+// kind per accrual date, so running the accrual job again for a day never charges twice.
+type Accrual struct {
+	gorm.Model
+	ID            uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	DebtID        uint   `json:"debt_id" gorm:"not null;uniqueIndex:idx_accruals_debt_kind_date,priority:1"`
+	Kind          string `json:"kind" gorm:"not null;type:varchar(16);uniqueIndex:idx_accruals_debt_kind_date,priority:2"`
+	AccrualDate   string `json:"accrual_date" gorm:"not null;type:varchar(10);uniqueIndex:idx_accruals_debt_kind_date,priority:3"`
+	TransactionID uint   `json:"transaction_id" gorm:"not null;index"`
+	Amount        Money  `json:"amount" gorm:"not null;type:decimal(19,2)"`
+}
+
+// Accrues tells if the rate charges anything at all
+func (r AccrualRate) Accrues() bool {
+	return r.InterestRateBps > 0 || r.LateFee > 0
+}
+
+// DailyInterest returns the interest of one day on the given outstanding debt, a positive amount
+// rounded half up to cents
+func (r AccrualRate) DailyInterest(outstanding Money) Money {
+	if outstanding <= 0 || r.InterestRateBps == 0 {
+		return 0
+	}
+
+	const denominator = basisPointsPerUnit * daysPerYear
+	return Money((int64(outstanding)*int64(r.InterestRateBps) + denominator/2) / denominator)
+}
+
+// OverdueBefore returns the billing time before which debt is overdue on the given accrual day
+func (r AccrualRate) OverdueBefore(accrualDay time.Time) time.Time {
+	return AccrualDay(accrualDay).AddDate(0, 0, -int(r.GraceDays))
+}
+
+// AccrualDay returns the start of the IST calendar day of the given time
+func AccrualDay(t time.Time) time.Time {
+	t = t.In(IST)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, IST)
+}
+
+// BilledAt returns when a transaction was billed: installments when they are due, other transactions
+// at their event date
+func (t Transaction) BilledAt() (time.Time, error) {
+	if t.DueDate != nil {
+		return *t.DueDate, nil
+	}
+	return ParseEventDate(t.EventDate)
+}
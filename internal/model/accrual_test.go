@@ -0,0 +1,52 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccrualRate_DailyInterest(t *testing.T) {
+	tests := []struct {
+		name        string
+		rateBps     uint
+		outstanding Money
+		expected    Money
+	}{
+		{name: "Rounded To Cents", rateBps: 3650, outstanding: MustParseMoney("1000"), expected: MustParseMoney("1")},
+		{name: "Rounds Half Up", rateBps: 1825, outstanding: MustParseMoney("10"), expected: MustParseMoney("0.01")},
+		{name: "Small Debt Accrues Nothing", rateBps: 2400, outstanding: MustParseMoney("0.5"), expected: MustParseMoney("0")},
+		{name: "Large Debt", rateBps: 2400, outstanding: MustParseMoney("12345.67"), expected: MustParseMoney("8.12")},
+		{name: "No Rate", rateBps: 0, outstanding: MustParseMoney("1000"), expected: 0},
+		{name: "No Debt", rateBps: 2400, outstanding: 0, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate := AccrualRate{InterestRateBps: tt.rateBps}
+			assert.Equal(t, tt.expected, rate.DailyInterest(tt.outstanding))
+		})
+	}
+}
+
+func TestAccrualRate_OverdueBefore(t *testing.T) {
+	rate := AccrualRate{GraceDays: 5}
+
+	overdueBefore := rate.OverdueBefore(time.Date(2025, 2, 10, 20, 0, 0, 0, time.UTC))
+
+	// 20:00 UTC is already the 11th in IST
+	assert.True(t, time.Date(2025, 2, 6, 0, 0, 0, 0, IST).Equal(overdueBefore))
+}
+
+func TestTransaction_BilledAt(t *testing.T) {
+	dueDate := time.Date(2025, 3, 10, 0, 0, 0, 0, IST)
+
+	billedAt, err := Transaction{EventDate: "2025-02-10T10:00:00", DueDate: &dueDate}.BilledAt()
+	assert.NoError(t, err)
+	assert.True(t, dueDate.Equal(billedAt))
+
+	billedAt, err = Transaction{EventDate: "2025-02-10T10:00:00"}.BilledAt()
+	assert.NoError(t, err)
+	assert.True(t, time.Date(2025, 2, 10, 10, 0, 0, 0, IST).Equal(billedAt))
+}
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"gorm.io/gorm"
+)
+
+// Allocation records that a credit voucher paid off part of a debit transaction. Amount is the positive
+// amount applied and CreatedAt is when it was applied, so every balance change made by a discharge can be
+// explained later.
+type Allocation struct {
+	gorm.Model
+	ID        uint  `json:"id" gorm:"primaryKey;autoIncrement"`
+	VoucherID uint  `json:"voucher_id" gorm:"not null;index"`
+	DebitID   uint  `json:"debit_id" gorm:"not null;index"`
+	Amount    Money `json:"amount" gorm:"not null;type:decimal(19,2);"`
+}
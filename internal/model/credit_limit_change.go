@@ -0,0 +1,16 @@
+package model
+
+import (
+	"gorm.io/gorm"
+)
+
+// CreditLimitChange records every change made to the credit limit of an account. A nil limit means
+// the account had, or now has, no limit.
+type CreditLimitChange struct {
+	gorm.Model
+	ID            uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	AccountID     uint   `json:"account_id" gorm:"not null;index"`
+	PreviousLimit *Money `json:"previous_limit" gorm:"type:decimal(19,2)"`
+	NewLimit      *Money `json:"new_limit" gorm:"type:decimal(19,2)"`
+	Reason        string `json:"reason" gorm:"type:varchar(255)"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"gorm.io/gorm"
+)
+
+// IdempotencyKey stores the outcome of a request sent with an Idempotency-Key header, so that a retry
+// with the same key gets the same response instead of repeating the request. StatusCode stays 0 while
+// the first request is still being processed.
+type IdempotencyKey struct {
+	gorm.Model
+	Key          string `json:"key" gorm:"uniqueIndex;not null;type:varchar(255)"`
+	RequestHash  string `json:"request_hash" gorm:"not null;type:char(64)"`
+	StatusCode   int    `json:"status_code" gorm:"not null;default:0"`
+	ResponseBody []byte `json:"response_body"`
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	// MinInstallments is the smallest number of installments of a purchase with installments
+	MinInstallments = 2
+
+	// MaxInstallments is the largest number of installments of a purchase with installments
+	MaxInstallments = 48
+)
+
+// IsValidInstallmentCount tells if a purchase can be split into the given number of installments
+func IsValidInstallmentCount(count uint) bool {
+	return count >= MinInstallments && count <= MaxInstallments
+}
+
+// BuildInstallmentSchedule splits a purchase with installments into its installment entries. The amount
+// is split evenly in minor units and the cents which do not divide evenly go to the first installments,
+// so the installments always add up to the purchase amount. The first installment is due on the
+// purchase date and every next one on the same day of the following months, or on the last day of a
+// month which is shorter.
+func BuildInstallmentSchedule(purchase Transaction, start time.Time) []Transaction {
+	count := int64(purchase.Installments)
+	if count <= 0 {
+		return nil
+	}
+
+	total := purchase.Amount.MinorUnits()
+	share := total / count
+	rest := total % count
+
+	schedule := make([]Transaction, 0, count)
+	for i := int64(0); i < count; i++ {
+		units := share
+		if i < abs(rest) {
+			if rest < 0 {
+				units--
+			} else {
+				units++
+			}
+		}
+
+		dueDate := addMonths(start, int(i))
+		schedule = append(schedule, Transaction{
+			AccountID:         purchase.AccountID,
+			OperationTypeId:   purchase.OperationTypeId,
+			Amount:            MoneyFromMinorUnits(units),
+			Balance:           MoneyFromMinorUnits(units),
+			InstallmentNumber: uint(i + 1),
+			DueDate:           &dueDate,
+		})
+	}
+
+	return schedule
+}
+
+// addMonths moves t by the given number of months, keeping its day of the month unless the target month
+// is shorter. time.AddDate would roll Jan 31 over to Mar 3 instead of Feb 28.
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+
+	// day 0 of the month after the target month is the target month's last day
+	lastDay := time.Date(year, month+time.Month(months)+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(year, month+time.Month(months), day, hour, min, sec, t.Nanosecond(), t.Location())
+}
+
+func abs(value int64) int64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,124 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildInstallmentSchedule(t *testing.T) {
+	start := time.Date(2025, time.January, 31, 10, 0, 0, 0, IST)
+
+	tests := []struct {
+		name             string
+		purchase         Transaction
+		expectedAmounts  []Money
+		expectedDueDates []time.Time
+	}{
+		{
+			name:            "Amount Splits Evenly",
+			purchase:        Transaction{AccountID: 1, OperationTypeId: 2, Amount: MustParseMoney("-300"), Installments: 3},
+			expectedAmounts: []Money{MustParseMoney("-100"), MustParseMoney("-100"), MustParseMoney("-100")},
+			expectedDueDates: []time.Time{
+				time.Date(2025, time.January, 31, 10, 0, 0, 0, IST),
+				time.Date(2025, time.February, 28, 10, 0, 0, 0, IST),
+				time.Date(2025, time.March, 31, 10, 0, 0, 0, IST),
+			},
+		},
+		{
+			name:            "Leftover Cents Go To First Installments",
+			purchase:        Transaction{AccountID: 1, OperationTypeId: 2, Amount: MustParseMoney("-100"), Installments: 3},
+			expectedAmounts: []Money{MustParseMoney("-33.34"), MustParseMoney("-33.33"), MustParseMoney("-33.33")},
+		},
+		{
+			name:            "Smallest Possible Installments",
+			purchase:        Transaction{AccountID: 1, OperationTypeId: 2, Amount: MustParseMoney("-0.05"), Installments: 4},
+			expectedAmounts: []Money{MustParseMoney("-0.02"), MustParseMoney("-0.01"), MustParseMoney("-0.01"), MustParseMoney("-0.01")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := BuildInstallmentSchedule(tt.purchase, start)
+
+			assert.Len(t, schedule, len(tt.expectedAmounts))
+
+			var total Money
+			for i, installment := range schedule {
+				total += installment.Amount
+
+				assert.Equal(t, tt.expectedAmounts[i], installment.Amount)
+				assert.Equal(t, installment.Amount, installment.Balance)
+				assert.Equal(t, uint(i+1), installment.InstallmentNumber)
+				assert.Equal(t, tt.purchase.AccountID, installment.AccountID)
+				assert.Equal(t, tt.purchase.OperationTypeId, installment.OperationTypeId)
+				if tt.expectedDueDates != nil {
+					assert.Equal(t, tt.expectedDueDates[i], *installment.DueDate)
+				}
+			}
+			assert.Equal(t, tt.purchase.Amount, total)
+		})
+	}
+}
+
+func TestBuildInstallmentSchedule_DueDates(t *testing.T) {
+	tests := []struct {
+		name             string
+		start            time.Time
+		installments     uint
+		expectedDueDates []time.Time
+	}{
+		{
+			name:         "Leap Year February",
+			start:        time.Date(2024, time.January, 30, 10, 0, 0, 0, IST),
+			installments: 3,
+			expectedDueDates: []time.Time{
+				time.Date(2024, time.January, 30, 10, 0, 0, 0, IST),
+				time.Date(2024, time.February, 29, 10, 0, 0, 0, IST),
+				time.Date(2024, time.March, 30, 10, 0, 0, 0, IST),
+			},
+		},
+		{
+			name:         "Thirty Day Months And Year End",
+			start:        time.Date(2025, time.October, 31, 23, 59, 59, 0, IST),
+			installments: 4,
+			expectedDueDates: []time.Time{
+				time.Date(2025, time.October, 31, 23, 59, 59, 0, IST),
+				time.Date(2025, time.November, 30, 23, 59, 59, 0, IST),
+				time.Date(2025, time.December, 31, 23, 59, 59, 0, IST),
+				time.Date(2026, time.January, 31, 23, 59, 59, 0, IST),
+			},
+		},
+		{
+			name:         "Day Every Month Has",
+			start:        time.Date(2025, time.December, 15, 10, 0, 0, 0, IST),
+			installments: 3,
+			expectedDueDates: []time.Time{
+				time.Date(2025, time.December, 15, 10, 0, 0, 0, IST),
+				time.Date(2026, time.January, 15, 10, 0, 0, 0, IST),
+				time.Date(2026, time.February, 15, 10, 0, 0, 0, IST),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			purchase := Transaction{AccountID: 1, OperationTypeId: 2, Amount: MustParseMoney("-300"), Installments: tt.installments}
+			schedule := BuildInstallmentSchedule(purchase, tt.start)
+
+			dueDates := make([]time.Time, 0, len(schedule))
+			for _, installment := range schedule {
+				dueDates = append(dueDates, *installment.DueDate)
+			}
+			assert.Equal(t, tt.expectedDueDates, dueDates)
+		})
+	}
+}
+
+func TestIsValidInstallmentCount(t *testing.T) {
+	assert.False(t, IsValidInstallmentCount(1))
+	assert.True(t, IsValidInstallmentCount(2))
+	assert.True(t, IsValidInstallmentCount(48))
+	assert.False(t, IsValidInstallmentCount(49))
+}
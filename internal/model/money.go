@@ -0,0 +1,190 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MoneyScale is the number of decimal places kept for every amount
+const MoneyScale = 2
+
+// MoneyColumnType is the column type used for every money column
+const MoneyColumnType = "decimal(19,2)"
+
+const minorUnitsPerMajor = 100
+
+// Money is an exact monetary amount held in minor units (cents), so that adding and subtracting
+// amounts never builds up binary rounding error. Values with more than MoneyScale decimal places
+// are rounded half away from zero to the nearest minor unit, e.g. 1.005 becomes 1.01 and -1.005
+// becomes -1.01. In JSON it is a plain number such as -50.25, and in the database a DECIMAL column.
+type Money int64
+
+// MoneyFromMinorUnits builds an amount from minor units, e.g. 1050 is 10.50
+func MoneyFromMinorUnits(units int64) Money {
+	return Money(units)
+}
+
+// MoneyFromFloat converts a float to money using the rounding rule of Money. It is only meant for
+// reading legacy floating point values and should not be used for arithmetic.
+func MoneyFromFloat(value float64) Money {
+	return Money(math.Round(value * minorUnitsPerMajor))
+}
+
+// ParseMoney parses a decimal string such as "-50", "10.5" or "1.005" without going through float
+func ParseMoney(value string) (Money, error) {
+	s := strings.TrimSpace(value)
+	if s == "" {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+
+	// keep one extra digit to decide the rounding of the last minor unit
+	fraction = (fraction + strings.Repeat("0", MoneyScale+1))[:MoneyScale+1]
+
+	units, err := strconv.ParseInt(whole+fraction[:MoneyScale], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("amount %q is out of range", value)
+	}
+	if fraction[MoneyScale] >= '5' {
+		if units == math.MaxInt64 {
+			return 0, fmt.Errorf("amount %q is out of range", value)
+		}
+		units++
+	}
+
+	if negative {
+		units = -units
+	}
+	return Money(units), nil
+}
+
+// MustParseMoney is like ParseMoney but panics when the value is not a valid amount
+func MustParseMoney(value string) Money {
+	m, err := ParseMoney(value)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// MinorUnits returns the amount in minor units
+func (m Money) MinorUnits() int64 {
+	return int64(m)
+}
+
+// String formats the amount with exactly MoneyScale decimal places, e.g. -50.00
+func (m Money) String() string {
+	sign := ""
+	units := int64(m)
+	if units < 0 {
+		sign = "-"
+	}
+	abs := uint64(units)
+	if units < 0 {
+		abs = uint64(-units)
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, abs/minorUnitsPerMajor, MoneyScale, abs%minorUnitsPerMajor)
+}
+
+// MarshalJSON writes the amount as a JSON number
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON reads the amount from a JSON number or a quoted decimal string
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	if strings.ContainsAny(s, "eE") {
+		return fmt.Errorf("invalid amount %s: exponent notation is not supported", data)
+	}
+
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value stores the amount as an exact decimal string
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan reads the amount from a DECIMAL column, or from a legacy floating point column
+func (m *Money) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	case int64:
+		*m = Money(v * minorUnitsPerMajor)
+		return nil
+	case float64:
+		*m = MoneyFromFloat(v)
+		return nil
+	default:
+		return fmt.Errorf("can not scan %T into money", value)
+	}
+}
+
+func (m *Money) scanString(value string) error {
+	if strings.ContainsAny(value, "eE") {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		*m = MoneyFromFloat(f)
+		return nil
+	}
+
+	parsed, err := ParseMoney(value)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// GormDataType maps money to a DECIMAL column
+func (Money) GormDataType() string {
+	return MoneyColumnType
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      Money
+		expectedError bool
+	}{
+		{name: "Whole Number", input: "50", expected: MoneyFromMinorUnits(5000)},
+		{name: "Negative Amount", input: "-50.25", expected: MoneyFromMinorUnits(-5025)},
+		{name: "Single Decimal", input: "10.5", expected: MoneyFromMinorUnits(1050)},
+		{name: "Leading Dot", input: ".5", expected: MoneyFromMinorUnits(50)},
+		{name: "Rounds Half Away From Zero", input: "1.005", expected: MoneyFromMinorUnits(101)},
+		{name: "Rounds Negative Half Away From Zero", input: "-1.005", expected: MoneyFromMinorUnits(-101)},
+		{name: "Rounds Down Below Half", input: "1.0049", expected: MoneyFromMinorUnits(100)},
+		{name: "Empty", input: "", expectedError: true},
+		{name: "Sign Only", input: "-", expectedError: true},
+		{name: "Letters", input: "12a", expectedError: true},
+		{name: "Two Dots", input: "1.2.3", expectedError: true},
+		{name: "Out Of Range", input: "999999999999999999999", expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ParseMoney(tt.input)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, m)
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "0.00", MoneyFromMinorUnits(0).String())
+	assert.Equal(t, "0.05", MoneyFromMinorUnits(5).String())
+	assert.Equal(t, "-0.05", MoneyFromMinorUnits(-5).String())
+	assert.Equal(t, "-1234.50", MoneyFromMinorUnits(-123450).String())
+}
+
+func TestMoney_JSON(t *testing.T) {
+	var transaction Transaction
+	err := json.Unmarshal([]byte(`{"amount": -0.1, "balance": "0.2"}`), &transaction)
+	assert.NoError(t, err)
+	assert.Equal(t, MoneyFromMinorUnits(-10), transaction.Amount)
+	assert.Equal(t, MoneyFromMinorUnits(20), transaction.Balance)
+	assert.Equal(t, MoneyFromMinorUnits(10), transaction.Amount+transaction.Balance)
+
+	data, err := json.Marshal(MoneyFromMinorUnits(-5025))
+	assert.NoError(t, err)
+	assert.Equal(t, "-50.25", string(data))
+
+	err = json.Unmarshal([]byte(`{"amount": 1e3}`), &transaction)
+	assert.Error(t, err)
+}
+
+func TestMoney_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected Money
+	}{
+		{name: "Decimal Bytes", input: []byte("-150.75"), expected: MoneyFromMinorUnits(-15075)},
+		{name: "Decimal String", input: "20.10", expected: MoneyFromMinorUnits(2010)},
+		{name: "Integer", input: int64(7), expected: MoneyFromMinorUnits(700)},
+		{name: "Legacy Float", input: 0.1 + 0.2, expected: MoneyFromMinorUnits(30)},
+		{name: "Null", input: nil, expected: MoneyFromMinorUnits(0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Money
+			assert.NoError(t, m.Scan(tt.input))
+			assert.Equal(t, tt.expected, m)
+		})
+	}
+
+	value, err := MoneyFromMinorUnits(-15075).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "-150.75", value)
+}
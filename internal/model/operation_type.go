@@ -1,27 +1,55 @@
 package model
 
-type OperationType uint
+import (
+	"time"
+)
 
+// IDs of the seeded operation types which the service refers to itself
 const (
-	NormalPurchase       OperationType = iota + 1 // 1
-	PurchaseInstallments                          // 2
-	Withdrawal                                    // 3
-	CreditVoucher                                 // 4
+	NormalPurchase       uint = iota + 1 // 1
+	PurchaseInstallments                 // 2
+	Withdrawal                           // 3
+	CreditVoucher                        // 4
+	Reversal                             // 5
+	Interest                             // 6
+	LateFee                              // 7
 )
 
-// Map for converting enum to string
-var transactionTypeToString = map[OperationType]string{
-	NormalPurchase:       "Normal Purchase",
-	PurchaseInstallments: "Purchase with Installments",
-	Withdrawal:           "Withdrawal",
-	CreditVoucher:        "Credit Voucher",
+// OperationType describes a kind of transaction and the rules transactions of that kind follow. Operation
+// types live in the operation_types table, so a new one can be added without any code change.
+//
+// AmountSign is -1 for debits such as purchases, whose amount is negative and whose balance is the debt
+// still to be paid, and 1 for credits such as vouchers, whose amount is positive and which discharge the
+// account's debt. DischargeEligible tells if debits of this type can be discharged by credits.
+// Internal types are only created by the service itself, never through POST /transactions.
+type OperationType struct {
+	ID                 uint      `json:"id" gorm:"primaryKey;autoIncrement:false"`
+	Description        string    `json:"description" gorm:"not null;type:varchar(255)"`
+	AmountSign         int       `json:"amount_sign" gorm:"not null"`
+	DischargeEligible  bool      `json:"discharge_eligible" gorm:"not null;default:false"`
+	AllowsInstallments bool      `json:"allows_installments" gorm:"not null;default:false"`
+	Internal           bool      `json:"internal" gorm:"not null;default:false"`
+	CreatedAt          time.Time `json:"-"`
+	UpdatedAt          time.Time `json:"-"`
+}
+
+// DefaultOperationTypes are seeded into the operation_types table when they do not exist yet
+var DefaultOperationTypes = []OperationType{
+	{ID: NormalPurchase, Description: "Normal Purchase", AmountSign: -1, DischargeEligible: true},
+	{ID: PurchaseInstallments, Description: "Purchase with Installments", AmountSign: -1, DischargeEligible: true, AllowsInstallments: true},
+	{ID: Withdrawal, Description: "Withdrawal", AmountSign: -1, DischargeEligible: true},
+	{ID: CreditVoucher, Description: "Credit Voucher", AmountSign: 1},
+	{ID: Reversal, Description: "Reversal", AmountSign: 1, Internal: true},
+	{ID: Interest, Description: "Interest", AmountSign: -1, DischargeEligible: true, Internal: true},
+	{ID: LateFee, Description: "Late Fee", AmountSign: -1, DischargeEligible: true, Internal: true},
 }
 
-// String method for pretty printing
-func (t OperationType) String() string {
-	return transactionTypeToString[t]
+// IsDebit tells if transactions of this type take money, i.e. have a negative amount
+func (o OperationType) IsDebit() bool {
+	return o.AmountSign < 0
 }
 
-func IsValidOperationType(id uint) bool {
-	return id == uint(NormalPurchase) || id == uint(PurchaseInstallments) || id == uint(Withdrawal) || id == uint(CreditVoucher)
+// IsCredit tells if transactions of this type give money, i.e. have a positive amount
+func (o OperationType) IsCredit() bool {
+	return o.AmountSign > 0
 }
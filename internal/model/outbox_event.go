@@ -0,0 +1,99 @@
+package model
+
+import (
+	"time"
+)
+
+// Event types written to the outbox
+const (
+	EventAccountCreated            = "account.created"
+	EventTransactionCreated        = "transaction.created"
+	EventTransactionBalanceChanged = "transaction.balance_changed"
+)
+
+// EventTypes lists every event type written to the outbox
+var EventTypes = []string{EventAccountCreated, EventTransactionCreated, EventTransactionBalanceChanged}
+
+// IsKnownEventType tells if events of the given type are written to the outbox
+func IsKnownEventType(eventType string) bool {
+	for _, known := range EventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Aggregate types, i.e. the kind of record an event is about
+const (
+	AggregateAccount     = "account"
+	AggregateTransaction = "transaction"
+)
+
+// OutboxEvent is a domain event written in the same database transaction as the change it describes,
+// so an event exists if and only if the change was committed. The relay publishes pending events and
+// sets PublishedAt once the publisher accepted them. ClaimedUntil keeps an event from other relays
+// while one is publishing it. Payload is the JSON encoded event data.
+type OutboxEvent struct {
+	ID            uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	EventType     string     `json:"event_type" gorm:"not null;type:varchar(64)"`
+	AggregateType string     `json:"aggregate_type" gorm:"not null;type:varchar(32)"`
+	AggregateID   uint       `json:"aggregate_id" gorm:"not null"`
+	Payload       []byte     `json:"payload" gorm:"not null"`
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	LastError     string     `json:"last_error" gorm:"type:text"`
+	PublishedAt   *time.Time `json:"published_at" gorm:"index"`
+	ClaimedUntil  *time.Time `json:"claimed_until"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// AccountCreatedPayload is the data of an account.created event
+type AccountCreatedPayload struct {
+	AccountID      uint          `json:"account_id"`
+	DocumentNumber string        `json:"document_number"`
+	DocumentType   string        `json:"document_type"`
+	Status         AccountStatus `json:"status"`
+	CreditLimit    *Money        `json:"credit_limit"`
+}
+
+// TransactionCreatedPayload is the data of a transaction.created event
+type TransactionCreatedPayload struct {
+	TransactionID         uint       `json:"transaction_id"`
+	AccountID             uint       `json:"account_id"`
+	OperationTypeID       uint       `json:"operation_type_id"`
+	Amount                Money      `json:"amount"`
+	Balance               Money      `json:"balance"`
+	EventDate             string     `json:"event_date"`
+	ReversedTransactionID *uint      `json:"reversed_transaction_id,omitempty"`
+	ParentTransactionID   *uint      `json:"parent_transaction_id,omitempty"`
+	InstallmentNumber     uint       `json:"installment_number,omitempty"`
+	DueDate               *time.Time `json:"due_date,omitempty"`
+	AccruedTransactionID  *uint      `json:"accrued_transaction_id,omitempty"`
+}
+
+// TransactionBalanceChangedPayload is the data of a transaction.balance_changed event
+type TransactionBalanceChangedPayload struct {
+	TransactionID   uint  `json:"transaction_id"`
+	AccountID       uint  `json:"account_id"`
+	PreviousBalance Money `json:"previous_balance"`
+	Balance         Money `json:"balance"`
+	// CausedBy is the transaction whose creation changed the balance, e.g. a credit voucher or a reversal
+	CausedBy uint `json:"caused_by,omitempty"`
+}
+
+// NewTransactionCreatedPayload builds the transaction.created data of a stored transaction
+func NewTransactionCreatedPayload(transaction Transaction) TransactionCreatedPayload {
+	return TransactionCreatedPayload{
+		TransactionID:         transaction.ID,
+		AccountID:             transaction.AccountID,
+		OperationTypeID:       transaction.OperationTypeId,
+		Amount:                transaction.Amount,
+		Balance:               transaction.Balance,
+		EventDate:             transaction.EventDate,
+		ReversedTransactionID: transaction.ReversedTransactionID,
+		ParentTransactionID:   transaction.ParentTransactionID,
+		InstallmentNumber:     transaction.InstallmentNumber,
+		DueDate:               transaction.DueDate,
+		AccruedTransactionID:  transaction.AccruedTransactionID,
+	}
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// MinBillingDay and MaxBillingDay bound the day of the month a billing cycle closes on. Days after
+	// the 28th are not allowed so every month has a closing date.
+	MinBillingDay = 1
+	MaxBillingDay = 28
+
+	// DefaultBillingDay is the closing day of accounts which did not choose one
+	DefaultBillingDay = 1
+
+	// PaymentDueDays is how many days after the closing date a statement is due
+	PaymentDueDays = 10
+)
+
+// Statement is the snapshot of one closed billing cycle of an account, from PeriodStart (included) to
+// PeriodEnd (excluded). Amounts are signed like transaction amounts, so debits are negative and
+// credits positive, and ClosingBalance is OpeningBalance plus every amount of the cycle. Installments
+// are billed in the cycle they are due in, other transactions in the cycle of their event date.
+type Statement struct {
+	gorm.Model
+	ID               uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	AccountID        uint      `json:"account_id" gorm:"not null;uniqueIndex:idx_statements_account_period,priority:1"`
+	PeriodStart      time.Time `json:"period_start" gorm:"not null;uniqueIndex:idx_statements_account_period,priority:2"`
+	PeriodEnd        time.Time `json:"period_end" gorm:"not null"`
+	DueDate          time.Time `json:"due_date" gorm:"not null"`
+	OpeningBalance   Money     `json:"opening_balance" gorm:"not null;type:decimal(19,2)"`
+	Purchases        Money     `json:"purchases" gorm:"not null;type:decimal(19,2)"`
+	Withdrawals      Money     `json:"withdrawals" gorm:"not null;type:decimal(19,2)"`
+	Vouchers         Money     `json:"vouchers" gorm:"not null;type:decimal(19,2)"`
+	Reversals        Money     `json:"reversals" gorm:"not null;type:decimal(19,2)"`
+	Interest         Money     `json:"interest" gorm:"not null;type:decimal(19,2)"`
+	Fees             Money     `json:"fees" gorm:"not null;type:decimal(19,2)"`
+	OtherDebits      Money     `json:"other_debits" gorm:"not null;type:decimal(19,2)"`
+	OtherCredits     Money     `json:"other_credits" gorm:"not null;type:decimal(19,2)"`
+	ClosingBalance   Money     `json:"closing_balance" gorm:"not null;type:decimal(19,2)"`
+	TransactionCount int64     `json:"transaction_count" gorm:"not null"`
+}
+
+// StatementTotal is the sum and count of the amounts of one operation type in a billing cycle
+type StatementTotal struct {
+	OperationTypeID uint
+	Amount          Money
+	Count           int64
+}
+
+// IsValidBillingDay tells if a billing cycle can close on the given day of the month
+func IsValidBillingDay(day uint) bool {
+	return day >= MinBillingDay && day <= MaxBillingDay
+}
+
+// NextCycleClose returns the first closing date strictly after the given time: midnight IST at the
+// start of the billing day
+func NextCycleClose(after time.Time, billingDay uint) time.Time {
+	after = after.In(IST)
+	closing := time.Date(after.Year(), after.Month(), int(billingDay), 0, 0, 0, 0, IST)
+	if !closing.After(after) {
+		closing = closing.AddDate(0, 1, 0)
+	}
+	return closing
+}
+
+// BuildStatement snapshots a billing cycle from the opening balance and the totals per operation type.
+// Seeded operation types have their own line, operation types added later are counted as other debits
+// or other credits depending on their sign.
+func BuildStatement(accountID uint, periodStart, periodEnd time.Time, openingBalance Money, totals []StatementTotal) Statement {
+	statement := Statement{
+		AccountID:      accountID,
+		PeriodStart:    periodStart,
+		PeriodEnd:      periodEnd,
+		DueDate:        periodEnd.AddDate(0, 0, PaymentDueDays),
+		OpeningBalance: openingBalance,
+		ClosingBalance: openingBalance,
+	}
+
+	for _, total := range totals {
+		switch {
+		case total.OperationTypeID == NormalPurchase || total.OperationTypeID == PurchaseInstallments:
+			statement.Purchases += total.Amount
+		case total.OperationTypeID == Withdrawal:
+			statement.Withdrawals += total.Amount
+		case total.OperationTypeID == CreditVoucher:
+			statement.Vouchers += total.Amount
+		case total.OperationTypeID == Reversal:
+			statement.Reversals += total.Amount
+		case total.OperationTypeID == Interest:
+			statement.Interest += total.Amount
+		case total.OperationTypeID == LateFee:
+			statement.Fees += total.Amount
+		case total.Amount < 0:
+			statement.OtherDebits += total.Amount
+		default:
+			statement.OtherCredits += total.Amount
+		}
+
+		statement.ClosingBalance += total.Amount
+		statement.TransactionCount += total.Count
+	}
+
+	return statement
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextCycleClose(t *testing.T) {
+	tests := []struct {
+		name       string
+		after      time.Time
+		billingDay uint
+		expected   time.Time
+	}{
+		{
+			name:       "Later This Month",
+			after:      time.Date(2025, 2, 3, 15, 0, 0, 0, IST),
+			billingDay: 10,
+			expected:   time.Date(2025, 2, 10, 0, 0, 0, 0, IST),
+		},
+		{
+			name:       "Next Month",
+			after:      time.Date(2025, 2, 12, 15, 0, 0, 0, IST),
+			billingDay: 10,
+			expected:   time.Date(2025, 3, 10, 0, 0, 0, 0, IST),
+		},
+		{
+			name:       "Exactly On Closing",
+			after:      time.Date(2025, 2, 10, 0, 0, 0, 0, IST),
+			billingDay: 10,
+			expected:   time.Date(2025, 3, 10, 0, 0, 0, 0, IST),
+		},
+		{
+			name:       "Across The Year",
+			after:      time.Date(2025, 12, 20, 0, 0, 0, 0, IST),
+			billingDay: 1,
+			expected:   time.Date(2026, 1, 1, 0, 0, 0, 0, IST),
+		},
+		{
+			name:       "UTC Time Already In Next IST Day",
+			after:      time.Date(2025, 2, 9, 20, 0, 0, 0, time.UTC),
+			billingDay: 10,
+			expected:   time.Date(2025, 3, 10, 0, 0, 0, 0, IST),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.expected.Equal(NextCycleClose(tt.after, tt.billingDay)))
+		})
+	}
+}
+
+func TestBuildStatement(t *testing.T) {
+	start := time.Date(2025, 1, 10, 0, 0, 0, 0, IST)
+	end := time.Date(2025, 2, 10, 0, 0, 0, 0, IST)
+
+	statement := BuildStatement(1, start, end, MustParseMoney("-100"), []StatementTotal{
+		{OperationTypeID: NormalPurchase, Amount: MustParseMoney("-50.25"), Count: 2},
+		{OperationTypeID: PurchaseInstallments, Amount: MustParseMoney("-33.34"), Count: 1},
+		{OperationTypeID: Withdrawal, Amount: MustParseMoney("-20"), Count: 1},
+		{OperationTypeID: CreditVoucher, Amount: MustParseMoney("150"), Count: 1},
+		{OperationTypeID: Reversal, Amount: MustParseMoney("10"), Count: 1},
+		{OperationTypeID: Interest, Amount: MustParseMoney("-1.2"), Count: 3},
+		{OperationTypeID: LateFee, Amount: MustParseMoney("-10"), Count: 1},
+		{OperationTypeID: 8, Amount: MustParseMoney("-5"), Count: 1},
+		{OperationTypeID: 9, Amount: MustParseMoney("2.5"), Count: 1},
+	})
+
+	assert.Equal(t, uint(1), statement.AccountID)
+	assert.Equal(t, time.Date(2025, 2, 20, 0, 0, 0, 0, IST), statement.DueDate)
+	assert.Equal(t, MustParseMoney("-100"), statement.OpeningBalance)
+	assert.Equal(t, MustParseMoney("-83.59"), statement.Purchases)
+	assert.Equal(t, MustParseMoney("-20"), statement.Withdrawals)
+	assert.Equal(t, MustParseMoney("150"), statement.Vouchers)
+	assert.Equal(t, MustParseMoney("10"), statement.Reversals)
+	assert.Equal(t, MustParseMoney("-1.2"), statement.Interest)
+	assert.Equal(t, MustParseMoney("-10"), statement.Fees)
+	assert.Equal(t, MustParseMoney("-5"), statement.OtherDebits)
+	assert.Equal(t, MustParseMoney("2.5"), statement.OtherCredits)
+	assert.Equal(t, MustParseMoney("-57.29"), statement.ClosingBalance)
+	assert.Equal(t, int64(12), statement.TransactionCount)
+}
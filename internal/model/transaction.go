@@ -1,23 +1,92 @@
 package model
 
 import (
+	"fmt"
 	"gorm.io/gorm"
 	"time"
 )
 
 var IST = time.FixedZone("IST", 5*3600+1800)
 
+// EventDateLayout is the format event dates are written in, always in IST
+const EventDateLayout = "2006-01-02T15:04:05.999999"
+
+// dayLayout is the format of plain dates accepted as event date bounds
+const dayLayout = "2006-01-02"
+
 type Transaction struct {
 	gorm.Model
-	ID              uint    `json:"id" gorm:"primaryKey;autoIncrement"`
-	AccountID       uint    `json:"account_id" gorm:"not null;"`
-	Amount          float64 `json:"amount" gorm:"not null;"`
-	Balance         float64 `json:"balance" gorm:"not null;"`
-	OperationTypeId uint    `json:"operation_type_id" gorm:"not null;"`
-	EventDate       string  `json:"event_date" gorm:"not null;type:timestamp(6);"`
+	ID              uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	AccountID       uint   `json:"account_id" gorm:"not null;index:idx_transactions_account_event_date,priority:1;"`
+	Amount          Money  `json:"amount" gorm:"not null;type:decimal(19,2);"`
+	Balance         Money  `json:"balance" gorm:"not null;type:decimal(19,2);"`
+	OperationTypeId uint   `json:"operation_type_id" gorm:"not null;"`
+	EventDate       string `json:"event_date" gorm:"not null;type:timestamp(6);index:idx_transactions_account_event_date,priority:2;"`
+
+	// ReversedTransactionID links a reversal to the transaction it compensates
+	ReversedTransactionID *uint `json:"reversed_transaction_id,omitempty" gorm:"index"`
+
+	// Installments is the number of installments of a purchase with installments. The purchase itself
+	// keeps a zero balance and the debt lives in its installment entries, which link back to it through
+	// ParentTransactionID and only count as debt from their DueDate on.
+	Installments        uint       `json:"installments,omitempty" gorm:"not null;default:0"`
+	InstallmentNumber   uint       `json:"installment_number,omitempty" gorm:"not null;default:0"`
+	ParentTransactionID *uint      `json:"parent_transaction_id,omitempty" gorm:"index"`
+	DueDate             *time.Time `json:"due_date,omitempty" gorm:"index"`
+
+	// AccruedTransactionID links an interest or late fee charge to the overdue debt it was charged on
+	AccruedTransactionID *uint `json:"accrued_transaction_id,omitempty" gorm:"index"`
 }
 
 func (t *Transaction) BeforeCreate(tx *gorm.DB) (err error) {
-	t.EventDate = time.Now().In(IST).Format("2006-01-02T15:04:05.999999")
+	t.EventDate = time.Now().In(IST).Format(EventDateLayout)
+	return
+}
+
+// AfterFind normalizes the event date read back from the database, see NormalizeEventDate
+func (t *Transaction) AfterFind(tx *gorm.DB) (err error) {
+	t.EventDate = NormalizeEventDate(t.EventDate)
 	return
 }
+
+// NormalizeEventDate returns an event date read back from the database as an RFC 3339 timestamp in IST.
+// Event dates are stored without a time zone and drivers label the wall clock they read back with UTC
+// or the local zone, so only the wall clock is kept and it is read in IST, the zone it was written in.
+// Values that are not recognized are returned unchanged.
+func NormalizeEventDate(eventDate string) string {
+	t, err := time.Parse(time.RFC3339Nano, eventDate)
+	if err != nil {
+		t, err = time.ParseInLocation(EventDateLayout, eventDate, IST)
+		if err != nil {
+			return eventDate
+		}
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), IST).
+		Format(time.RFC3339Nano)
+}
+
+// ParseEventDate reads an event date either as written by BeforeCreate or as read back from the
+// database, which returns it in RFC 3339 format
+func ParseEventDate(eventDate string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, eventDate); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(EventDateLayout, eventDate, IST)
+}
+
+// ParseEventDateBound reads a bound of an event date range, given as an RFC 3339 timestamp or a plain
+// date, and returns it in EventDateLayout. A plain date used as an upper bound covers the whole day.
+func ParseEventDateBound(value string, endOfDay bool) (string, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.In(IST).Format(EventDateLayout), nil
+	}
+
+	t, err := time.ParseInLocation(dayLayout, value, IST)
+	if err != nil {
+		return "", fmt.Errorf("expected YYYY-MM-DD or RFC 3339 timestamp")
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Microsecond)
+	}
+	return t.Format(EventDateLayout), nil
+}
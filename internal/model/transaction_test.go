@@ -0,0 +1,44 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeEventDate(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventDate string
+		expected  string
+	}{
+		{name: "Labeled UTC", eventDate: "2025-02-10T10:00:00.123456Z", expected: "2025-02-10T10:00:00.123456+05:30"},
+		{name: "Labeled Local Zone", eventDate: "2025-02-10T10:00:00-03:00", expected: "2025-02-10T10:00:00+05:30"},
+		{name: "Already IST", eventDate: "2025-02-10T10:00:00.5+05:30", expected: "2025-02-10T10:00:00.5+05:30"},
+		{name: "Event Date Layout", eventDate: "2025-02-10T10:00:00.123456", expected: "2025-02-10T10:00:00.123456+05:30"},
+		{name: "Not A Date", eventDate: "yesterday", expected: "yesterday"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeEventDate(tt.eventDate))
+		})
+	}
+}
+
+func TestParseEventDateBound(t *testing.T) {
+	from, err := ParseEventDateBound("2025-02-10", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-02-10T00:00:00", from)
+
+	to, err := ParseEventDateBound("2025-02-10", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-02-10T23:59:59.999999", to)
+
+	at, err := ParseEventDateBound("2025-02-10T04:30:00Z", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-02-10T10:00:00", at)
+
+	_, err = ParseEventDateBound("10/02/2025", false)
+	assert.Error(t, err)
+}
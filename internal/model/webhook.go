@@ -0,0 +1,67 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Statuses of a webhook delivery
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+// WebhookSubscription is a partner callback URL receiving the events of the listed types. EventTypes
+// is a comma separated list, where "*" stands for every event type. Secret signs the deliveries and is
+// only returned when the subscription is created.
+type WebhookSubscription struct {
+	gorm.Model
+	ID         uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	URL        string `json:"url" gorm:"not null;type:varchar(2048)"`
+	EventTypes string `json:"event_types" gorm:"not null;type:varchar(512)"`
+	Secret     string `json:"-" gorm:"not null;type:varchar(128)"`
+}
+
+// Subscribes tells if the subscription wants events of the given type
+func (s WebhookSubscription) Subscribes(eventType string) bool {
+	for _, subscribed := range strings.Split(s.EventTypes, ",") {
+		if subscribed == "*" || subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one outbox event to be sent to one subscription. It is retried until the partner
+// answers with a 2xx status, or dead-lettered once it ran out of attempts. EventID is the ID of the
+// outbox event, and a subscription gets each event once even when the relay hands it over again.
+type WebhookDelivery struct {
+	ID             uint                `json:"id" gorm:"primaryKey;autoIncrement"`
+	SubscriptionID uint                `json:"subscription_id" gorm:"not null;uniqueIndex:idx_webhook_deliveries_subscription_event"`
+	Subscription   WebhookSubscription `json:"-" gorm:"foreignKey:SubscriptionID"`
+	EventID        uint                `json:"event_id" gorm:"not null;uniqueIndex:idx_webhook_deliveries_subscription_event"`
+	EventType      string              `json:"event_type" gorm:"not null;type:varchar(64)"`
+	Payload        []byte              `json:"-" gorm:"not null"`
+	Status         string              `json:"status" gorm:"not null;type:varchar(16);index:idx_webhook_deliveries_due"`
+	Attempts       int                 `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  time.Time           `json:"next_attempt_at" gorm:"index:idx_webhook_deliveries_due"`
+	LastError      string              `json:"last_error" gorm:"type:text"`
+	DeliveredAt    *time.Time          `json:"delivered_at"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+	AttemptLog     []WebhookAttempt    `json:"attempt_log" gorm:"foreignKey:DeliveryID"`
+}
+
+// WebhookAttempt records one HTTP call made for a delivery. StatusCode is 0 when no response was received.
+type WebhookAttempt struct {
+	ID            uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	DeliveryID    uint      `json:"delivery_id" gorm:"not null;index"`
+	AttemptNumber int       `json:"attempt_number" gorm:"not null"`
+	StatusCode    int       `json:"status_code" gorm:"not null;default:0"`
+	Error         string    `json:"error" gorm:"type:text"`
+	DurationMs    int64     `json:"duration_ms" gorm:"not null;default:0"`
+	CreatedAt     time.Time `json:"created_at"`
+}
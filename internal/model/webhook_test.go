@@ -0,0 +1,17 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookSubscription_Subscribes(t *testing.T) {
+	all := WebhookSubscription{EventTypes: "*"}
+	some := WebhookSubscription{EventTypes: EventAccountCreated + "," + EventTransactionBalanceChanged}
+
+	assert.True(t, all.Subscribes(EventTransactionCreated))
+	assert.True(t, some.Subscribes(EventAccountCreated))
+	assert.True(t, some.Subscribes(EventTransactionBalanceChanged))
+	assert.False(t, some.Subscribes(EventTransactionCreated))
+}
@@ -1,26 +1,62 @@
 package repo
 
 import (
-	"log"
-
+	"context"
+	"errors"
+	"github.com/vamshi1997/pismo-assessment/internal/logging"
+	"github.com/vamshi1997/pismo-assessment/internal/metrics"
 	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"gorm.io/gorm"
 )
 
-func (r *Repository) CreateAccount(account model.Account) (model.Account, error) {
-	if err := r.db.Create(&account); err.Error != nil {
-		log.Println("Error while creating account: ", err.Error)
-		return account, err.Error
+// ErrDocumentNumberTaken is returned when another account has the document number of a new account
+var ErrDocumentNumberTaken = errors.New("an account with this document number already exists")
+
+func (r *Repository) CreateAccount(ctx context.Context, account model.Account) (model.Account, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&account).Error; err != nil {
+			return err
+		}
+
+		if err := recordEvent(tx, model.EventAccountCreated, model.AggregateAccount, account.ID,
+			model.AccountCreatedPayload{
+				AccountID:      account.ID,
+				DocumentNumber: account.DocumentNumber,
+				DocumentType:   account.DocumentType,
+				Status:         account.Status,
+				CreditLimit:    account.CreditLimit,
+			}); err != nil {
+			return err
+		}
+
+		// the initial credit limit is the first entry of the limit history
+		if account.CreditLimit == nil {
+			return nil
+		}
+		return tx.Create(&model.CreditLimitChange{
+			AccountID: account.ID,
+			NewLimit:  account.CreditLimit,
+			Reason:    "account created",
+		}).Error
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		err = ErrDocumentNumberTaken
+	}
+	if err != nil {
+		r.logError(ctx, "error while creating account", err)
+		return account, err
 	}
 
-	log.Println("account created successfully")
+	logging.FromContext(ctx).Info("account created", "account_id", account.ID)
+	metrics.AccountCreated()
 	return account, nil
 }
 
-func (r *Repository) GetAccount(accountId uint) (*model.Account, error) {
+func (r *Repository) GetAccount(ctx context.Context, accountId uint) (*model.Account, error) {
 	var accountInfo model.Account
 
-	if err := r.db.Where("id = ?", accountId).First(&accountInfo); err.Error != nil {
-		log.Println("Error while fetching account info: ", err.Error)
+	if err := r.db.WithContext(ctx).Where("id = ?", accountId).First(&accountInfo); err.Error != nil {
+		r.logError(ctx, "error while fetching account", err.Error, "account_id", accountId)
 		return nil, err.Error
 	}
 
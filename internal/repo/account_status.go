@@ -0,0 +1,94 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrInvalidStatusTransition is returned when the account can not move from its current state to the
+	// requested one, e.g. when unblocking an active account or reopening a closed one
+	ErrInvalidStatusTransition = errors.New("account can not move to the requested status")
+
+	// ErrAccountHasDebt is returned when closing an account which still owes money
+	ErrAccountHasDebt = errors.New("account with outstanding debt can not be closed")
+
+	// ErrTransactionNotAllowed is returned when the status of the account does not allow the transaction
+	ErrTransactionNotAllowed = errors.New("account status does not allow this transaction")
+)
+
+// ChangeAccountStatus moves an account to a new lifecycle state and records the transition, with the
+// reason and who made it, in the same database transaction. An account can only be closed when it owes
+// nothing, including installments which are not due yet.
+func (r *Repository) ChangeAccountStatus(ctx context.Context, accountId uint, status model.AccountStatus, reasonCode string, changedBy string) (*model.Account, error) {
+	var account model.Account
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", accountId).
+			First(&account).Error; err != nil {
+			return err
+		}
+
+		if !account.Status.CanTransitionTo(status) {
+			return ErrInvalidStatusTransition
+		}
+
+		if status == model.AccountClosed {
+			var debt model.Money
+			if err := tx.Model(&model.Transaction{}).
+				Select("COALESCE(SUM(balance), 0)").
+				Where("account_id = ?", account.ID).
+				Where("balance < ?", 0).
+				Scan(&debt).Error; err != nil {
+				return err
+			}
+			if debt < 0 {
+				return ErrAccountHasDebt
+			}
+		}
+
+		change := model.AccountStatusChange{
+			AccountID:  account.ID,
+			FromStatus: account.Status,
+			ToStatus:   status,
+			ReasonCode: reasonCode,
+			ChangedBy:  changedBy,
+		}
+		if err := tx.Create(&change).Error; err != nil {
+			return err
+		}
+
+		account.Status = status
+		return tx.Model(&model.Account{}).
+			Where("id = ?", account.ID).
+			Update("status", status).Error
+	})
+	if err != nil {
+		r.logError(ctx, "error while changing account status", err, "account_id", accountId, "status", status)
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// ListAccountStatusChanges returns the status transitions of an account, oldest first
+func (r *Repository) ListAccountStatusChanges(ctx context.Context, accountId uint) ([]model.AccountStatusChange, error) {
+	var changes []model.AccountStatusChange
+
+	result := r.db.WithContext(ctx).
+		Where("account_id = ?", accountId).
+		Order("id ASC").
+		Find(&changes)
+
+	if result.Error != nil {
+		r.logError(ctx, "error while fetching account status history", result.Error, "account_id", accountId)
+		return nil, result.Error
+	}
+
+	return changes, nil
+}
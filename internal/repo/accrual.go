@@ -0,0 +1,187 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vamshi1997/pismo-assessment/internal/metrics"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ListAccrualRates returns the accrual rate of every operation type which has one, ordered by operation type
+func (r *Repository) ListAccrualRates(ctx context.Context) ([]model.AccrualRate, error) {
+	var rates []model.AccrualRate
+
+	if err := r.db.WithContext(ctx).Order("operation_type_id ASC").Find(&rates).Error; err != nil {
+		r.logError(ctx, "error while fetching accrual rates", err)
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+// SaveAccrualRate creates or replaces the accrual rate of an operation type. The new rate applies from
+// the next accrual on, charges already posted are kept.
+func (r *Repository) SaveAccrualRate(ctx context.Context, rate model.AccrualRate) (*model.AccrualRate, error) {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "operation_type_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"interest_rate_bps", "late_fee", "grace_days", "updated_at"}),
+	}).Create(&rate).Error
+	if err != nil {
+		r.logError(ctx, "error while saving accrual rate", err, "operation_type_id", rate.OperationTypeID)
+		return nil, err
+	}
+
+	return &rate, nil
+}
+
+// DeleteAccrualRate removes the accrual rate of an operation type, so its debt stops accruing
+func (r *Repository) DeleteAccrualRate(ctx context.Context, operationTypeId uint) error {
+	result := r.db.WithContext(ctx).Where("operation_type_id = ?", operationTypeId).Delete(&model.AccrualRate{})
+	if result.Error != nil {
+		r.logError(ctx, "error while deleting accrual rate", result.Error, "operation_type_id", operationTypeId)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// ListOverdueDebts returns up to limit IDs greater than afterId of the debts of the rate's operation type
+// which are still outstanding and overdue on the accrual day, in ascending order
+func (r *Repository) ListOverdueDebts(ctx context.Context, rate model.AccrualRate, accrualDay time.Time, afterId uint, limit int) ([]uint, error) {
+	var ids []uint
+
+	overdueBefore := rate.OverdueBefore(accrualDay)
+	if err := r.db.WithContext(ctx).Model(&model.Transaction{}).
+		Where("operation_type_id = ?", rate.OperationTypeID).
+		Where("balance < ?", 0).
+		Where("installments = ?", 0).
+		Where("(due_date IS NULL AND event_date < ?) OR (due_date IS NOT NULL AND due_date < ?)",
+			overdueBefore.Format(model.EventDateLayout), overdueBefore).
+		Where("id > ?", afterId).
+		Order("id ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		r.logError(ctx, "error while listing overdue debts", err, "operation_type_id", rate.OperationTypeID)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// AccrueDebt posts the interest and late fee due on an overdue debt for the accrual day, as new
+// transactions linked to the debt. Charges already posted for that day are not posted again, and the
+// late fee is only ever charged once per debt. The debt is locked while charging, and a debt which was
+// paid or is not overdue anymore is left alone.
+func (r *Repository) AccrueDebt(ctx context.Context, debtId uint, rate model.AccrualRate, accrualDay time.Time) ([]model.Transaction, error) {
+	var charges []model.Transaction
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var debt model.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", debtId).
+			First(&debt).Error; err != nil {
+			return err
+		}
+
+		billedAt, err := debt.BilledAt()
+		if err != nil {
+			return err
+		}
+		if debt.Balance >= 0 || debt.OperationTypeId != rate.OperationTypeID || !billedAt.Before(rate.OverdueBefore(accrualDay)) {
+			return nil
+		}
+
+		accrualDate := model.AccrualDay(accrualDay).Format(model.AccrualDateLayout)
+
+		if rate.LateFee > 0 {
+			var charged int64
+			if err = tx.Model(&model.Accrual{}).
+				Where("debt_id = ? AND kind = ?", debt.ID, model.AccrualLateFee).
+				Count(&charged).Error; err != nil {
+				return err
+			}
+			if charged == 0 {
+				charge, err := postAccrual(tx, debt, model.AccrualLateFee, accrualDate, rate.LateFee)
+				if err != nil {
+					return err
+				}
+				charges = append(charges, charge)
+			}
+		}
+
+		if interest := rate.DailyInterest(-debt.Balance); interest > 0 {
+			var accrual model.Accrual
+			err = tx.Where("debt_id = ? AND kind = ? AND accrual_date = ?", debt.ID, model.AccrualInterest, accrualDate).
+				First(&accrual).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				charge, err := postAccrual(tx, debt, model.AccrualInterest, accrualDate, interest)
+				if err != nil {
+					return err
+				}
+				charges = append(charges, charge)
+			} else if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		r.logError(ctx, "error while accruing charges", err, "transaction_id", debtId)
+		return nil, err
+	}
+
+	metrics.TransactionsCreated(charges...)
+	return charges, nil
+}
+
+// ListAccruals returns the charges posted on a debt, oldest first
+func (r *Repository) ListAccruals(ctx context.Context, debtId uint) ([]model.Accrual, error) {
+	var accruals []model.Accrual
+
+	if err := r.db.WithContext(ctx).Where("debt_id = ?", debtId).Order("id ASC").Find(&accruals).Error; err != nil {
+		r.logError(ctx, "error while fetching accruals", err, "transaction_id", debtId)
+		return nil, err
+	}
+
+	return accruals, nil
+}
+
+// postAccrual stores a charge of the given kind on a debt as a new transaction owing the whole amount,
+// and records it against the accrual date
+func postAccrual(tx *gorm.DB, debt model.Transaction, kind string, accrualDate string, amount model.Money) (model.Transaction, error) {
+	operationTypeId := model.Interest
+	if kind == model.AccrualLateFee {
+		operationTypeId = model.LateFee
+	}
+
+	charge := model.Transaction{
+		AccountID:            debt.AccountID,
+		OperationTypeId:      operationTypeId,
+		Amount:               -amount,
+		Balance:              -amount,
+		AccruedTransactionID: &debt.ID,
+	}
+	if err := tx.Create(&charge).Error; err != nil {
+		return charge, err
+	}
+
+	if err := tx.Create(&model.Accrual{
+		DebtID:        debt.ID,
+		Kind:          kind,
+		AccrualDate:   accrualDate,
+		TransactionID: charge.ID,
+		Amount:        -amount,
+	}).Error; err != nil {
+		return charge, err
+	}
+
+	return charge, recordTransactionCreated(tx, charge)
+}
@@ -0,0 +1,52 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+)
+
+// StreamTransactions passes the transactions matching the filter to fn in batches of at most batchSize,
+// ordered by account and ID, until every transaction was passed or fn returns an error. Each batch is a
+// new query continuing after the last transaction of the previous one, so only one batch is held in
+// memory however many transactions match.
+func (r *Repository) StreamTransactions(ctx context.Context, filter ExportFilter, batchSize int, fn func(batch []model.Transaction) error) error {
+	var afterAccountID, afterID uint
+
+	for {
+		query := r.db.WithContext(ctx).Model(&model.Transaction{})
+		if filter.AccountID != 0 {
+			query = query.Where("account_id = ?", filter.AccountID)
+		}
+		if filter.FromEventDate != "" {
+			query = query.Where("event_date >= ?", filter.FromEventDate)
+		}
+		if filter.ToEventDate != "" {
+			query = query.Where("event_date <= ?", filter.ToEventDate)
+		}
+
+		var batch []model.Transaction
+		if err := query.
+			Where("(account_id > ? OR (account_id = ? AND id > ?))", afterAccountID, afterAccountID, afterID).
+			Order("account_id ASC").
+			Order("id ASC").
+			Limit(batchSize).
+			Find(&batch).Error; err != nil {
+			r.logError(ctx, "error while streaming transactions", err, "account_id", filter.AccountID)
+			return err
+		}
+
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+
+		last := batch[len(batch)-1]
+		afterAccountID, afterID = last.AccountID, last.ID
+	}
+}
@@ -0,0 +1,28 @@
+package repo
+
+import (
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+)
+
+// TransactionFilter narrows down the transactions of an account. Zero values mean no restriction.
+// Results are ordered by event date and ID, and AfterEventDate/AfterID continue after the last
+// transaction of a previous page.
+type TransactionFilter struct {
+	AccountID       uint
+	OperationTypeID uint
+	FromEventDate   string
+	ToEventDate     string
+	MinAmount       *model.Money
+	MaxAmount       *model.Money
+	AfterEventDate  string
+	AfterID         uint
+	Limit           int
+}
+
+// ExportFilter selects the transactions of a bulk export. Zero values mean no restriction, so an empty
+// filter exports every transaction.
+type ExportFilter struct {
+	AccountID     uint
+	FromEventDate string
+	ToEventDate   string
+}
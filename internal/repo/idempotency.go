@@ -0,0 +1,58 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"gorm.io/gorm"
+)
+
+// CreateIdempotencyKey reserves the key for a new request. It fails if the key is already taken.
+func (r *Repository) CreateIdempotencyKey(ctx context.Context, key model.IdempotencyKey) (*model.IdempotencyKey, error) {
+	if err := r.db.WithContext(ctx).Create(&key); err.Error != nil {
+		r.logError(ctx, "error while creating idempotency key", err.Error)
+		return nil, err.Error
+	}
+
+	return &key, nil
+}
+
+// GetIdempotencyKey returns the stored key, or nil if the key was never used
+func (r *Repository) GetIdempotencyKey(ctx context.Context, key string) (*model.IdempotencyKey, error) {
+	var idempotencyKey model.IdempotencyKey
+
+	if err := r.db.WithContext(ctx).Where(&model.IdempotencyKey{Key: key}).First(&idempotencyKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logError(ctx, "error while fetching idempotency key", err)
+		return nil, err
+	}
+
+	return &idempotencyKey, nil
+}
+
+// CompleteIdempotencyKey stores the response of the request that reserved the key
+func (r *Repository) CompleteIdempotencyKey(ctx context.Context, key string, statusCode int, responseBody []byte) error {
+	result := r.db.WithContext(ctx).Model(&model.IdempotencyKey{}).
+		Where(&model.IdempotencyKey{Key: key}).
+		Updates(map[string]interface{}{"status_code": statusCode, "response_body": responseBody})
+
+	if result.Error != nil {
+		r.logError(ctx, "error while completing idempotency key", result.Error)
+		return result.Error
+	}
+
+	return nil
+}
+
+// DeleteIdempotencyKey removes the key for good so that it can be used again
+func (r *Repository) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	if err := r.db.WithContext(ctx).Unscoped().Where(&model.IdempotencyKey{Key: key}).Delete(&model.IdempotencyKey{}).Error; err != nil {
+		r.logError(ctx, "error while deleting idempotency key", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/vamshi1997/pismo-assessment/internal/metrics"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"gorm.io/gorm"
+)
+
+// CreateInstallmentPurchase stores a purchase with installments together with its schedule of
+// installment entries in one database transaction. The purchase keeps the full amount with a zero
+// balance, and each installment carries its own share of the debt and its due date.
+func (r *Repository) CreateInstallmentPurchase(ctx context.Context, purchase model.Transaction) (*model.Transaction, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the whole purchase uses the credit limit right away, not one installment at a time
+		if err := reserveCreditLimit(tx, purchase.AccountID, purchase.Amount); err != nil {
+			return err
+		}
+
+		purchase.Balance = 0
+		if err := tx.Create(&purchase).Error; err != nil {
+			return err
+		}
+
+		schedule := model.BuildInstallmentSchedule(purchase, time.Now().In(model.IST))
+		for i := range schedule {
+			schedule[i].ParentTransactionID = &purchase.ID
+		}
+		if err := tx.Create(&schedule).Error; err != nil {
+			return err
+		}
+
+		return recordTransactionCreated(tx, append([]model.Transaction{purchase}, schedule...)...)
+	})
+	if err != nil {
+		r.logError(ctx, "error while creating installment purchase", err, "account_id", purchase.AccountID)
+		return nil, err
+	}
+
+	// the installments are entries of the purchase, not transactions of their own
+	metrics.TransactionsCreated(purchase)
+	return &purchase, nil
+}
+
+// ListInstallments returns the installment entries of a purchase with installments in due order
+func (r *Repository) ListInstallments(ctx context.Context, purchaseId uint) ([]model.Transaction, error) {
+	var installments []model.Transaction
+
+	result := r.db.WithContext(ctx).
+		Where("parent_transaction_id = ?", purchaseId).
+		Order("installment_number ASC").
+		Find(&installments)
+
+	if result.Error != nil {
+		r.logError(ctx, "error while fetching installments", result.Error, "transaction_id", purchaseId)
+		return nil, result.Error
+	}
+
+	return installments, nil
+}
@@ -1,6 +1,9 @@
 package repo
 
 import (
+	"context"
+	"time"
+
 	"github.com/vamshi1997/pismo-assessment/internal/model"
 	"gorm.io/gorm"
 )
@@ -10,13 +13,57 @@ type Repository struct {
 }
 
 type IRepository interface {
-	CreateAccount(account model.Account) (model.Account, error)
-	GetAccount(accountId uint) (*model.Account, error)
-	CreateTransaction(transaction model.Transaction) (*model.Transaction, error)
-	GetPreviousTransactions() ([]model.Transaction, error)
-	UpdateTransactionBalance(balance float64, transactionId uint) (*model.Transaction, error)
+	CreateAccount(ctx context.Context, account model.Account) (model.Account, error)
+	GetAccount(ctx context.Context, accountId uint) (*model.Account, error)
+	CreateTransaction(ctx context.Context, transaction model.Transaction) (*model.Transaction, error)
+	GetTransaction(ctx context.Context, transactionId uint) (*model.Transaction, error)
+	GetOutstandingTransactions(ctx context.Context, accountId uint) ([]model.Transaction, error)
+	UpdateTransactionBalance(ctx context.Context, balance model.Money, transactionId uint) (*model.Transaction, error)
+	DischargeCreditVoucher(ctx context.Context, voucher model.Transaction) (*model.Transaction, error)
+	CreateInstallmentPurchase(ctx context.Context, purchase model.Transaction) (*model.Transaction, error)
+	ListInstallments(ctx context.Context, purchaseId uint) ([]model.Transaction, error)
+	ListAccountTransactions(ctx context.Context, filter TransactionFilter) ([]model.Transaction, error)
+	StreamTransactions(ctx context.Context, filter ExportFilter, batchSize int, fn func(batch []model.Transaction) error) error
+	GetAccountBalances(ctx context.Context, accountId uint) ([]OperationTypeBalance, error)
+	ListTransactionAllocations(ctx context.Context, transactionId uint) ([]model.Allocation, error)
+	ReverseTransaction(ctx context.Context, transactionId uint, amount *model.Money) (*model.Transaction, error)
+	ChangeAccountStatus(ctx context.Context, accountId uint, status model.AccountStatus, reasonCode string, changedBy string) (*model.Account, error)
+	ListAccountStatusChanges(ctx context.Context, accountId uint) ([]model.AccountStatusChange, error)
+	GetAccountNetBalance(ctx context.Context, accountId uint) (model.Money, error)
+	UpdateCreditLimit(ctx context.Context, accountId uint, creditLimit *model.Money, reason string) (*model.Account, error)
+	ListCreditLimitChanges(ctx context.Context, accountId uint) ([]model.CreditLimitChange, error)
+	ListOperationTypes(ctx context.Context) ([]model.OperationType, error)
+	GetOperationType(ctx context.Context, operationTypeId uint) (*model.OperationType, error)
+	PublishPendingEvents(ctx context.Context, limit int, publish func(event model.OutboxEvent) error) (int, error)
+	CloseStatements(ctx context.Context, accountId uint, now time.Time) ([]model.Statement, error)
+	ListStatements(ctx context.Context, accountId uint) ([]model.Statement, error)
+	GetStatement(ctx context.Context, accountId uint, statementId uint) (*model.Statement, error)
+	ListStatementTransactions(ctx context.Context, statement model.Statement) ([]model.Transaction, error)
+	UpdateBillingDay(ctx context.Context, accountId uint, billingDay uint) (*model.Account, error)
+	ListAccountIDs(ctx context.Context, afterId uint, limit int) ([]uint, error)
+	ListAccrualRates(ctx context.Context) ([]model.AccrualRate, error)
+	SaveAccrualRate(ctx context.Context, rate model.AccrualRate) (*model.AccrualRate, error)
+	DeleteAccrualRate(ctx context.Context, operationTypeId uint) error
+	ListOverdueDebts(ctx context.Context, rate model.AccrualRate, accrualDay time.Time, afterId uint, limit int) ([]uint, error)
+	AccrueDebt(ctx context.Context, debtId uint, rate model.AccrualRate, accrualDay time.Time) ([]model.Transaction, error)
+	ListAccruals(ctx context.Context, debtId uint) ([]model.Accrual, error)
+	CreateWebhookSubscription(ctx context.Context, subscription model.WebhookSubscription) (*model.WebhookSubscription, error)
+	ListWebhookSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error)
+	GetWebhookSubscription(ctx context.Context, subscriptionId uint) (*model.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, subscriptionId uint) error
+	EnqueueWebhookDeliveries(ctx context.Context, eventId uint, eventType string, payload []byte) (int, error)
+	ClaimDueWebhookDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]model.WebhookDelivery, error)
+	RecordWebhookAttempt(ctx context.Context, attempt model.WebhookAttempt, status string, nextAttemptAt time.Time) error
+	ListWebhookDeliveries(ctx context.Context, subscriptionId uint, status string, limit int) ([]model.WebhookDelivery, error)
+	RequeueWebhookDelivery(ctx context.Context, subscriptionId uint, deliveryId uint) (*model.WebhookDelivery, error)
+	CreateIdempotencyKey(ctx context.Context, key model.IdempotencyKey) (*model.IdempotencyKey, error)
+	GetIdempotencyKey(ctx context.Context, key string) (*model.IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, key string, statusCode int, responseBody []byte) error
+	DeleteIdempotencyKey(ctx context.Context, key string) error
 }
 
+// NewRepository returns the repository of the database. Every method runs its queries with the given
+// context, so they are cancelled with it and log through the logger it carries.
 func NewRepository(db *gorm.DB) IRepository {
 	return &Repository{
 		db: db,
@@ -0,0 +1,121 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrCreditLimitExceeded is returned when a debit is larger than the available limit of the account
+var ErrCreditLimitExceeded = errors.New("amount exceeds the available credit limit")
+
+// GetAccountNetBalance sums the balances of all transactions of an account. Debt is negative and
+// unapplied credit positive, so the result is what the account's available limit moves by.
+func (r *Repository) GetAccountNetBalance(ctx context.Context, accountId uint) (model.Money, error) {
+	balance, err := netBalance(r.db.WithContext(ctx), accountId)
+	if err != nil {
+		r.logError(ctx, "error while summing account balances", err, "account_id", accountId)
+		return 0, err
+	}
+	return balance, nil
+}
+
+// UpdateCreditLimit sets the credit limit of an account, or removes it when creditLimit is nil, and
+// records the change in the limit history in the same database transaction
+func (r *Repository) UpdateCreditLimit(ctx context.Context, accountId uint, creditLimit *model.Money, reason string) (*model.Account, error) {
+	var account model.Account
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", accountId).
+			First(&account).Error; err != nil {
+			return err
+		}
+
+		change := model.CreditLimitChange{
+			AccountID:     account.ID,
+			PreviousLimit: account.CreditLimit,
+			NewLimit:      creditLimit,
+			Reason:        reason,
+		}
+		if err := tx.Create(&change).Error; err != nil {
+			return err
+		}
+
+		account.CreditLimit = creditLimit
+		return tx.Model(&model.Account{}).
+			Where("id = ?", account.ID).
+			Update("credit_limit", creditLimit).Error
+	})
+	if err != nil {
+		r.logError(ctx, "error while updating credit limit", err, "account_id", accountId)
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// ListCreditLimitChanges returns the credit limit history of an account, oldest change first
+func (r *Repository) ListCreditLimitChanges(ctx context.Context, accountId uint) ([]model.CreditLimitChange, error) {
+	var changes []model.CreditLimitChange
+
+	result := r.db.WithContext(ctx).
+		Where("account_id = ?", accountId).
+		Order("id ASC").
+		Find(&changes)
+
+	if result.Error != nil {
+		r.logError(ctx, "error while fetching credit limit history", result.Error, "account_id", accountId)
+		return nil, result.Error
+	}
+
+	return changes, nil
+}
+
+// reserveCreditLimit locks the account row and checks that its status allows debits and that a debit
+// of the given (negative) amount fits in its available limit. It should run inside the database
+// transaction creating the debit, so two debits of the same account can not both use the same part of
+// the limit, and a debit can not slip in while the account is being blocked.
+func reserveCreditLimit(tx *gorm.DB, accountId uint, amount model.Money) error {
+	var account model.Account
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", accountId).
+		First(&account).Error; err != nil {
+		return err
+	}
+
+	if !account.Status.AllowsDebits() {
+		return ErrTransactionNotAllowed
+	}
+
+	if account.CreditLimit == nil {
+		return nil
+	}
+
+	balance, err := netBalance(tx, accountId)
+	if err != nil {
+		return err
+	}
+
+	if available := account.AvailableLimitFor(balance); *available+amount < 0 {
+		return ErrCreditLimitExceeded
+	}
+	return nil
+}
+
+func netBalance(db *gorm.DB, accountId uint) (model.Money, error) {
+	var balance model.Money
+
+	err := db.Model(&model.Transaction{}).
+		Select("COALESCE(SUM(balance), 0)").
+		Where("account_id = ?", accountId).
+		Scan(&balance).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return balance, nil
+}
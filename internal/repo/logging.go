@@ -0,0 +1,25 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/vamshi1997/pismo-assessment/internal/logging"
+	"gorm.io/gorm"
+)
+
+// logError logs a failed repository call with the error and the given attributes, through the logger
+// of ctx. Records which are not found are expected, e.g. when a client asks for an unknown account, so
+// they are logged at debug level. A document number which is taken is a client mistake too, logged at
+// info level.
+func (r *Repository) logError(ctx context.Context, msg string, err error, args ...any) {
+	level := slog.LevelError
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		level = slog.LevelDebug
+	case errors.Is(err, ErrDocumentNumberTaken):
+		level = slog.LevelInfo
+	}
+	logging.FromContext(ctx).Log(ctx, level, msg, append(args, "error", err)...)
+}
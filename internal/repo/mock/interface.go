@@ -5,10 +5,13 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/vamshi1997/pismo-assessment/internal/model"
+	repo "github.com/vamshi1997/pismo-assessment/internal/repo"
 )
 
 // MockIRepository is a mock of IRepository interface.
@@ -34,77 +37,701 @@ func (m *MockIRepository) EXPECT() *MockIRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AccrueDebt mocks base method.
+func (m *MockIRepository) AccrueDebt(ctx context.Context, debtId uint, rate model.AccrualRate, accrualDay time.Time) ([]model.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrueDebt", ctx, debtId, rate, accrualDay)
+	ret0, _ := ret[0].([]model.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccrueDebt indicates an expected call of AccrueDebt.
+func (mr *MockIRepositoryMockRecorder) AccrueDebt(ctx, debtId, rate, accrualDay interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrueDebt", reflect.TypeOf((*MockIRepository)(nil).AccrueDebt), ctx, debtId, rate, accrualDay)
+}
+
+// ChangeAccountStatus mocks base method.
+func (m *MockIRepository) ChangeAccountStatus(ctx context.Context, accountId uint, status model.AccountStatus, reasonCode, changedBy string) (*model.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeAccountStatus", ctx, accountId, status, reasonCode, changedBy)
+	ret0, _ := ret[0].(*model.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeAccountStatus indicates an expected call of ChangeAccountStatus.
+func (mr *MockIRepositoryMockRecorder) ChangeAccountStatus(ctx, accountId, status, reasonCode, changedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeAccountStatus", reflect.TypeOf((*MockIRepository)(nil).ChangeAccountStatus), ctx, accountId, status, reasonCode, changedBy)
+}
+
+// ClaimDueWebhookDeliveries mocks base method.
+func (m *MockIRepository) ClaimDueWebhookDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueWebhookDeliveries", ctx, limit, leaseUntil)
+	ret0, _ := ret[0].([]model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueWebhookDeliveries indicates an expected call of ClaimDueWebhookDeliveries.
+func (mr *MockIRepositoryMockRecorder) ClaimDueWebhookDeliveries(ctx, limit, leaseUntil interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueWebhookDeliveries", reflect.TypeOf((*MockIRepository)(nil).ClaimDueWebhookDeliveries), ctx, limit, leaseUntil)
+}
+
+// CloseStatements mocks base method.
+func (m *MockIRepository) CloseStatements(ctx context.Context, accountId uint, now time.Time) ([]model.Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseStatements", ctx, accountId, now)
+	ret0, _ := ret[0].([]model.Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseStatements indicates an expected call of CloseStatements.
+func (mr *MockIRepositoryMockRecorder) CloseStatements(ctx, accountId, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseStatements", reflect.TypeOf((*MockIRepository)(nil).CloseStatements), ctx, accountId, now)
+}
+
+// CompleteIdempotencyKey mocks base method.
+func (m *MockIRepository) CompleteIdempotencyKey(ctx context.Context, key string, statusCode int, responseBody []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteIdempotencyKey", ctx, key, statusCode, responseBody)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteIdempotencyKey indicates an expected call of CompleteIdempotencyKey.
+func (mr *MockIRepositoryMockRecorder) CompleteIdempotencyKey(ctx, key, statusCode, responseBody interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteIdempotencyKey", reflect.TypeOf((*MockIRepository)(nil).CompleteIdempotencyKey), ctx, key, statusCode, responseBody)
+}
+
 // CreateAccount mocks base method.
-func (m *MockIRepository) CreateAccount(account model.Account) (model.Account, error) {
+func (m *MockIRepository) CreateAccount(ctx context.Context, account model.Account) (model.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAccount", account)
+	ret := m.ctrl.Call(m, "CreateAccount", ctx, account)
 	ret0, _ := ret[0].(model.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateAccount indicates an expected call of CreateAccount.
-func (mr *MockIRepositoryMockRecorder) CreateAccount(account interface{}) *gomock.Call {
+func (mr *MockIRepositoryMockRecorder) CreateAccount(ctx, account interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockIRepository)(nil).CreateAccount), ctx, account)
+}
+
+// CreateIdempotencyKey mocks base method.
+func (m *MockIRepository) CreateIdempotencyKey(ctx context.Context, key model.IdempotencyKey) (*model.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdempotencyKey", ctx, key)
+	ret0, _ := ret[0].(*model.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIdempotencyKey indicates an expected call of CreateIdempotencyKey.
+func (mr *MockIRepositoryMockRecorder) CreateIdempotencyKey(ctx, key interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockIRepository)(nil).CreateAccount), account)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdempotencyKey", reflect.TypeOf((*MockIRepository)(nil).CreateIdempotencyKey), ctx, key)
+}
+
+// CreateInstallmentPurchase mocks base method.
+func (m *MockIRepository) CreateInstallmentPurchase(ctx context.Context, purchase model.Transaction) (*model.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInstallmentPurchase", ctx, purchase)
+	ret0, _ := ret[0].(*model.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInstallmentPurchase indicates an expected call of CreateInstallmentPurchase.
+func (mr *MockIRepositoryMockRecorder) CreateInstallmentPurchase(ctx, purchase interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInstallmentPurchase", reflect.TypeOf((*MockIRepository)(nil).CreateInstallmentPurchase), ctx, purchase)
 }
 
 // CreateTransaction mocks base method.
-func (m *MockIRepository) CreateTransaction(transaction model.Transaction) (*model.Transaction, error) {
+func (m *MockIRepository) CreateTransaction(ctx context.Context, transaction model.Transaction) (*model.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTransaction", transaction)
+	ret := m.ctrl.Call(m, "CreateTransaction", ctx, transaction)
 	ret0, _ := ret[0].(*model.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateTransaction indicates an expected call of CreateTransaction.
-func (mr *MockIRepositoryMockRecorder) CreateTransaction(transaction interface{}) *gomock.Call {
+func (mr *MockIRepositoryMockRecorder) CreateTransaction(ctx, transaction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockIRepository)(nil).CreateTransaction), ctx, transaction)
+}
+
+// CreateWebhookSubscription mocks base method.
+func (m *MockIRepository) CreateWebhookSubscription(ctx context.Context, subscription model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookSubscription", ctx, subscription)
+	ret0, _ := ret[0].(*model.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookSubscription indicates an expected call of CreateWebhookSubscription.
+func (mr *MockIRepositoryMockRecorder) CreateWebhookSubscription(ctx, subscription interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookSubscription", reflect.TypeOf((*MockIRepository)(nil).CreateWebhookSubscription), ctx, subscription)
+}
+
+// DeleteAccrualRate mocks base method.
+func (m *MockIRepository) DeleteAccrualRate(ctx context.Context, operationTypeId uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccrualRate", ctx, operationTypeId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccrualRate indicates an expected call of DeleteAccrualRate.
+func (mr *MockIRepositoryMockRecorder) DeleteAccrualRate(ctx, operationTypeId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccrualRate", reflect.TypeOf((*MockIRepository)(nil).DeleteAccrualRate), ctx, operationTypeId)
+}
+
+// DeleteIdempotencyKey mocks base method.
+func (m *MockIRepository) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdempotencyKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIdempotencyKey indicates an expected call of DeleteIdempotencyKey.
+func (mr *MockIRepositoryMockRecorder) DeleteIdempotencyKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdempotencyKey", reflect.TypeOf((*MockIRepository)(nil).DeleteIdempotencyKey), ctx, key)
+}
+
+// DeleteWebhookSubscription mocks base method.
+func (m *MockIRepository) DeleteWebhookSubscription(ctx context.Context, subscriptionId uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookSubscription", ctx, subscriptionId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookSubscription indicates an expected call of DeleteWebhookSubscription.
+func (mr *MockIRepositoryMockRecorder) DeleteWebhookSubscription(ctx, subscriptionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookSubscription", reflect.TypeOf((*MockIRepository)(nil).DeleteWebhookSubscription), ctx, subscriptionId)
+}
+
+// DischargeCreditVoucher mocks base method.
+func (m *MockIRepository) DischargeCreditVoucher(ctx context.Context, voucher model.Transaction) (*model.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DischargeCreditVoucher", ctx, voucher)
+	ret0, _ := ret[0].(*model.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DischargeCreditVoucher indicates an expected call of DischargeCreditVoucher.
+func (mr *MockIRepositoryMockRecorder) DischargeCreditVoucher(ctx, voucher interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockIRepository)(nil).CreateTransaction), transaction)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DischargeCreditVoucher", reflect.TypeOf((*MockIRepository)(nil).DischargeCreditVoucher), ctx, voucher)
+}
+
+// EnqueueWebhookDeliveries mocks base method.
+func (m *MockIRepository) EnqueueWebhookDeliveries(ctx context.Context, eventId uint, eventType string, payload []byte) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueWebhookDeliveries", ctx, eventId, eventType, payload)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueWebhookDeliveries indicates an expected call of EnqueueWebhookDeliveries.
+func (mr *MockIRepositoryMockRecorder) EnqueueWebhookDeliveries(ctx, eventId, eventType, payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueWebhookDeliveries", reflect.TypeOf((*MockIRepository)(nil).EnqueueWebhookDeliveries), ctx, eventId, eventType, payload)
 }
 
 // GetAccount mocks base method.
-func (m *MockIRepository) GetAccount(accountId uint) (*model.Account, error) {
+func (m *MockIRepository) GetAccount(ctx context.Context, accountId uint) (*model.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccount", accountId)
+	ret := m.ctrl.Call(m, "GetAccount", ctx, accountId)
 	ret0, _ := ret[0].(*model.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAccount indicates an expected call of GetAccount.
-func (mr *MockIRepositoryMockRecorder) GetAccount(accountId interface{}) *gomock.Call {
+func (mr *MockIRepositoryMockRecorder) GetAccount(ctx, accountId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockIRepository)(nil).GetAccount), ctx, accountId)
+}
+
+// GetAccountBalances mocks base method.
+func (m *MockIRepository) GetAccountBalances(ctx context.Context, accountId uint) ([]repo.OperationTypeBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountBalances", ctx, accountId)
+	ret0, _ := ret[0].([]repo.OperationTypeBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountBalances indicates an expected call of GetAccountBalances.
+func (mr *MockIRepositoryMockRecorder) GetAccountBalances(ctx, accountId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountBalances", reflect.TypeOf((*MockIRepository)(nil).GetAccountBalances), ctx, accountId)
+}
+
+// GetAccountNetBalance mocks base method.
+func (m *MockIRepository) GetAccountNetBalance(ctx context.Context, accountId uint) (model.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountNetBalance", ctx, accountId)
+	ret0, _ := ret[0].(model.Money)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountNetBalance indicates an expected call of GetAccountNetBalance.
+func (mr *MockIRepositoryMockRecorder) GetAccountNetBalance(ctx, accountId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountNetBalance", reflect.TypeOf((*MockIRepository)(nil).GetAccountNetBalance), ctx, accountId)
+}
+
+// GetIdempotencyKey mocks base method.
+func (m *MockIRepository) GetIdempotencyKey(ctx context.Context, key string) (*model.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotencyKey", ctx, key)
+	ret0, _ := ret[0].(*model.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotencyKey indicates an expected call of GetIdempotencyKey.
+func (mr *MockIRepositoryMockRecorder) GetIdempotencyKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyKey", reflect.TypeOf((*MockIRepository)(nil).GetIdempotencyKey), ctx, key)
+}
+
+// GetOperationType mocks base method.
+func (m *MockIRepository) GetOperationType(ctx context.Context, operationTypeId uint) (*model.OperationType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOperationType", ctx, operationTypeId)
+	ret0, _ := ret[0].(*model.OperationType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOperationType indicates an expected call of GetOperationType.
+func (mr *MockIRepositoryMockRecorder) GetOperationType(ctx, operationTypeId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockIRepository)(nil).GetAccount), accountId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOperationType", reflect.TypeOf((*MockIRepository)(nil).GetOperationType), ctx, operationTypeId)
 }
 
-// GetPreviousTransactions mocks base method.
-func (m *MockIRepository) GetPreviousTransactions() ([]model.Transaction, error) {
+// GetOutstandingTransactions mocks base method.
+func (m *MockIRepository) GetOutstandingTransactions(ctx context.Context, accountId uint) ([]model.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPreviousTransactions")
+	ret := m.ctrl.Call(m, "GetOutstandingTransactions", ctx, accountId)
 	ret0, _ := ret[0].([]model.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPreviousTransactions indicates an expected call of GetPreviousTransactions.
-func (mr *MockIRepositoryMockRecorder) GetPreviousTransactions() *gomock.Call {
+// GetOutstandingTransactions indicates an expected call of GetOutstandingTransactions.
+func (mr *MockIRepositoryMockRecorder) GetOutstandingTransactions(ctx, accountId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutstandingTransactions", reflect.TypeOf((*MockIRepository)(nil).GetOutstandingTransactions), ctx, accountId)
+}
+
+// GetStatement mocks base method.
+func (m *MockIRepository) GetStatement(ctx context.Context, accountId, statementId uint) (*model.Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatement", ctx, accountId, statementId)
+	ret0, _ := ret[0].(*model.Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatement indicates an expected call of GetStatement.
+func (mr *MockIRepositoryMockRecorder) GetStatement(ctx, accountId, statementId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatement", reflect.TypeOf((*MockIRepository)(nil).GetStatement), ctx, accountId, statementId)
+}
+
+// GetTransaction mocks base method.
+func (m *MockIRepository) GetTransaction(ctx context.Context, transactionId uint) (*model.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransaction", ctx, transactionId)
+	ret0, _ := ret[0].(*model.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransaction indicates an expected call of GetTransaction.
+func (mr *MockIRepositoryMockRecorder) GetTransaction(ctx, transactionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransaction", reflect.TypeOf((*MockIRepository)(nil).GetTransaction), ctx, transactionId)
+}
+
+// GetWebhookSubscription mocks base method.
+func (m *MockIRepository) GetWebhookSubscription(ctx context.Context, subscriptionId uint) (*model.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookSubscription", ctx, subscriptionId)
+	ret0, _ := ret[0].(*model.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookSubscription indicates an expected call of GetWebhookSubscription.
+func (mr *MockIRepositoryMockRecorder) GetWebhookSubscription(ctx, subscriptionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookSubscription", reflect.TypeOf((*MockIRepository)(nil).GetWebhookSubscription), ctx, subscriptionId)
+}
+
+// ListAccountIDs mocks base method.
+func (m *MockIRepository) ListAccountIDs(ctx context.Context, afterId uint, limit int) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountIDs", ctx, afterId, limit)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountIDs indicates an expected call of ListAccountIDs.
+func (mr *MockIRepositoryMockRecorder) ListAccountIDs(ctx, afterId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountIDs", reflect.TypeOf((*MockIRepository)(nil).ListAccountIDs), ctx, afterId, limit)
+}
+
+// ListAccountStatusChanges mocks base method.
+func (m *MockIRepository) ListAccountStatusChanges(ctx context.Context, accountId uint) ([]model.AccountStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountStatusChanges", ctx, accountId)
+	ret0, _ := ret[0].([]model.AccountStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountStatusChanges indicates an expected call of ListAccountStatusChanges.
+func (mr *MockIRepositoryMockRecorder) ListAccountStatusChanges(ctx, accountId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountStatusChanges", reflect.TypeOf((*MockIRepository)(nil).ListAccountStatusChanges), ctx, accountId)
+}
+
+// ListAccountTransactions mocks base method.
+func (m *MockIRepository) ListAccountTransactions(ctx context.Context, filter repo.TransactionFilter) ([]model.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountTransactions", ctx, filter)
+	ret0, _ := ret[0].([]model.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountTransactions indicates an expected call of ListAccountTransactions.
+func (mr *MockIRepositoryMockRecorder) ListAccountTransactions(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountTransactions", reflect.TypeOf((*MockIRepository)(nil).ListAccountTransactions), ctx, filter)
+}
+
+// ListAccrualRates mocks base method.
+func (m *MockIRepository) ListAccrualRates(ctx context.Context) ([]model.AccrualRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccrualRates", ctx)
+	ret0, _ := ret[0].([]model.AccrualRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccrualRates indicates an expected call of ListAccrualRates.
+func (mr *MockIRepositoryMockRecorder) ListAccrualRates(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccrualRates", reflect.TypeOf((*MockIRepository)(nil).ListAccrualRates), ctx)
+}
+
+// ListAccruals mocks base method.
+func (m *MockIRepository) ListAccruals(ctx context.Context, debtId uint) ([]model.Accrual, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccruals", ctx, debtId)
+	ret0, _ := ret[0].([]model.Accrual)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccruals indicates an expected call of ListAccruals.
+func (mr *MockIRepositoryMockRecorder) ListAccruals(ctx, debtId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccruals", reflect.TypeOf((*MockIRepository)(nil).ListAccruals), ctx, debtId)
+}
+
+// ListCreditLimitChanges mocks base method.
+func (m *MockIRepository) ListCreditLimitChanges(ctx context.Context, accountId uint) ([]model.CreditLimitChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCreditLimitChanges", ctx, accountId)
+	ret0, _ := ret[0].([]model.CreditLimitChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCreditLimitChanges indicates an expected call of ListCreditLimitChanges.
+func (mr *MockIRepositoryMockRecorder) ListCreditLimitChanges(ctx, accountId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCreditLimitChanges", reflect.TypeOf((*MockIRepository)(nil).ListCreditLimitChanges), ctx, accountId)
+}
+
+// ListInstallments mocks base method.
+func (m *MockIRepository) ListInstallments(ctx context.Context, purchaseId uint) ([]model.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInstallments", ctx, purchaseId)
+	ret0, _ := ret[0].([]model.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInstallments indicates an expected call of ListInstallments.
+func (mr *MockIRepositoryMockRecorder) ListInstallments(ctx, purchaseId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInstallments", reflect.TypeOf((*MockIRepository)(nil).ListInstallments), ctx, purchaseId)
+}
+
+// ListOperationTypes mocks base method.
+func (m *MockIRepository) ListOperationTypes(ctx context.Context) ([]model.OperationType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOperationTypes", ctx)
+	ret0, _ := ret[0].([]model.OperationType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOperationTypes indicates an expected call of ListOperationTypes.
+func (mr *MockIRepositoryMockRecorder) ListOperationTypes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOperationTypes", reflect.TypeOf((*MockIRepository)(nil).ListOperationTypes), ctx)
+}
+
+// ListOverdueDebts mocks base method.
+func (m *MockIRepository) ListOverdueDebts(ctx context.Context, rate model.AccrualRate, accrualDay time.Time, afterId uint, limit int) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverdueDebts", ctx, rate, accrualDay, afterId, limit)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverdueDebts indicates an expected call of ListOverdueDebts.
+func (mr *MockIRepositoryMockRecorder) ListOverdueDebts(ctx, rate, accrualDay, afterId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverdueDebts", reflect.TypeOf((*MockIRepository)(nil).ListOverdueDebts), ctx, rate, accrualDay, afterId, limit)
+}
+
+// ListStatementTransactions mocks base method.
+func (m *MockIRepository) ListStatementTransactions(ctx context.Context, statement model.Statement) ([]model.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStatementTransactions", ctx, statement)
+	ret0, _ := ret[0].([]model.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStatementTransactions indicates an expected call of ListStatementTransactions.
+func (mr *MockIRepositoryMockRecorder) ListStatementTransactions(ctx, statement interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStatementTransactions", reflect.TypeOf((*MockIRepository)(nil).ListStatementTransactions), ctx, statement)
+}
+
+// ListStatements mocks base method.
+func (m *MockIRepository) ListStatements(ctx context.Context, accountId uint) ([]model.Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStatements", ctx, accountId)
+	ret0, _ := ret[0].([]model.Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStatements indicates an expected call of ListStatements.
+func (mr *MockIRepositoryMockRecorder) ListStatements(ctx, accountId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStatements", reflect.TypeOf((*MockIRepository)(nil).ListStatements), ctx, accountId)
+}
+
+// ListTransactionAllocations mocks base method.
+func (m *MockIRepository) ListTransactionAllocations(ctx context.Context, transactionId uint) ([]model.Allocation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactionAllocations", ctx, transactionId)
+	ret0, _ := ret[0].([]model.Allocation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactionAllocations indicates an expected call of ListTransactionAllocations.
+func (mr *MockIRepositoryMockRecorder) ListTransactionAllocations(ctx, transactionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactionAllocations", reflect.TypeOf((*MockIRepository)(nil).ListTransactionAllocations), ctx, transactionId)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockIRepository) ListWebhookDeliveries(ctx context.Context, subscriptionId uint, status string, limit int) ([]model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", ctx, subscriptionId, status, limit)
+	ret0, _ := ret[0].([]model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockIRepositoryMockRecorder) ListWebhookDeliveries(ctx, subscriptionId, status, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockIRepository)(nil).ListWebhookDeliveries), ctx, subscriptionId, status, limit)
+}
+
+// ListWebhookSubscriptions mocks base method.
+func (m *MockIRepository) ListWebhookSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookSubscriptions", ctx)
+	ret0, _ := ret[0].([]model.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookSubscriptions indicates an expected call of ListWebhookSubscriptions.
+func (mr *MockIRepositoryMockRecorder) ListWebhookSubscriptions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookSubscriptions", reflect.TypeOf((*MockIRepository)(nil).ListWebhookSubscriptions), ctx)
+}
+
+// PublishPendingEvents mocks base method.
+func (m *MockIRepository) PublishPendingEvents(ctx context.Context, limit int, publish func(model.OutboxEvent) error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishPendingEvents", ctx, limit, publish)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishPendingEvents indicates an expected call of PublishPendingEvents.
+func (mr *MockIRepositoryMockRecorder) PublishPendingEvents(ctx, limit, publish interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishPendingEvents", reflect.TypeOf((*MockIRepository)(nil).PublishPendingEvents), ctx, limit, publish)
+}
+
+// RecordWebhookAttempt mocks base method.
+func (m *MockIRepository) RecordWebhookAttempt(ctx context.Context, attempt model.WebhookAttempt, status string, nextAttemptAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWebhookAttempt", ctx, attempt, status, nextAttemptAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordWebhookAttempt indicates an expected call of RecordWebhookAttempt.
+func (mr *MockIRepositoryMockRecorder) RecordWebhookAttempt(ctx, attempt, status, nextAttemptAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookAttempt", reflect.TypeOf((*MockIRepository)(nil).RecordWebhookAttempt), ctx, attempt, status, nextAttemptAt)
+}
+
+// RequeueWebhookDelivery mocks base method.
+func (m *MockIRepository) RequeueWebhookDelivery(ctx context.Context, subscriptionId, deliveryId uint) (*model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueWebhookDelivery", ctx, subscriptionId, deliveryId)
+	ret0, _ := ret[0].(*model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequeueWebhookDelivery indicates an expected call of RequeueWebhookDelivery.
+func (mr *MockIRepositoryMockRecorder) RequeueWebhookDelivery(ctx, subscriptionId, deliveryId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueWebhookDelivery", reflect.TypeOf((*MockIRepository)(nil).RequeueWebhookDelivery), ctx, subscriptionId, deliveryId)
+}
+
+// ReverseTransaction mocks base method.
+func (m *MockIRepository) ReverseTransaction(ctx context.Context, transactionId uint, amount *model.Money) (*model.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverseTransaction", ctx, transactionId, amount)
+	ret0, _ := ret[0].(*model.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReverseTransaction indicates an expected call of ReverseTransaction.
+func (mr *MockIRepositoryMockRecorder) ReverseTransaction(ctx, transactionId, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseTransaction", reflect.TypeOf((*MockIRepository)(nil).ReverseTransaction), ctx, transactionId, amount)
+}
+
+// SaveAccrualRate mocks base method.
+func (m *MockIRepository) SaveAccrualRate(ctx context.Context, rate model.AccrualRate) (*model.AccrualRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAccrualRate", ctx, rate)
+	ret0, _ := ret[0].(*model.AccrualRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveAccrualRate indicates an expected call of SaveAccrualRate.
+func (mr *MockIRepositoryMockRecorder) SaveAccrualRate(ctx, rate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAccrualRate", reflect.TypeOf((*MockIRepository)(nil).SaveAccrualRate), ctx, rate)
+}
+
+// StreamTransactions mocks base method.
+func (m *MockIRepository) StreamTransactions(ctx context.Context, filter repo.ExportFilter, batchSize int, fn func([]model.Transaction) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamTransactions", ctx, filter, batchSize, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamTransactions indicates an expected call of StreamTransactions.
+func (mr *MockIRepositoryMockRecorder) StreamTransactions(ctx, filter, batchSize, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamTransactions", reflect.TypeOf((*MockIRepository)(nil).StreamTransactions), ctx, filter, batchSize, fn)
+}
+
+// UpdateBillingDay mocks base method.
+func (m *MockIRepository) UpdateBillingDay(ctx context.Context, accountId, billingDay uint) (*model.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBillingDay", ctx, accountId, billingDay)
+	ret0, _ := ret[0].(*model.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateBillingDay indicates an expected call of UpdateBillingDay.
+func (mr *MockIRepositoryMockRecorder) UpdateBillingDay(ctx, accountId, billingDay interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBillingDay", reflect.TypeOf((*MockIRepository)(nil).UpdateBillingDay), ctx, accountId, billingDay)
+}
+
+// UpdateCreditLimit mocks base method.
+func (m *MockIRepository) UpdateCreditLimit(ctx context.Context, accountId uint, creditLimit *model.Money, reason string) (*model.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCreditLimit", ctx, accountId, creditLimit, reason)
+	ret0, _ := ret[0].(*model.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCreditLimit indicates an expected call of UpdateCreditLimit.
+func (mr *MockIRepositoryMockRecorder) UpdateCreditLimit(ctx, accountId, creditLimit, reason interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreviousTransactions", reflect.TypeOf((*MockIRepository)(nil).GetPreviousTransactions))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCreditLimit", reflect.TypeOf((*MockIRepository)(nil).UpdateCreditLimit), ctx, accountId, creditLimit, reason)
 }
 
 // UpdateTransactionBalance mocks base method.
-func (m *MockIRepository) UpdateTransactionBalance(balance float64, transactionId uint) (*model.Transaction, error) {
+func (m *MockIRepository) UpdateTransactionBalance(ctx context.Context, balance model.Money, transactionId uint) (*model.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTransactionBalance", balance, transactionId)
+	ret := m.ctrl.Call(m, "UpdateTransactionBalance", ctx, balance, transactionId)
 	ret0, _ := ret[0].(*model.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateTransactionBalance indicates an expected call of UpdateTransactionBalance.
-func (mr *MockIRepositoryMockRecorder) UpdateTransactionBalance(balance, transactionId interface{}) *gomock.Call {
+func (mr *MockIRepositoryMockRecorder) UpdateTransactionBalance(ctx, balance, transactionId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionBalance", reflect.TypeOf((*MockIRepository)(nil).UpdateTransactionBalance), balance, transactionId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionBalance", reflect.TypeOf((*MockIRepository)(nil).UpdateTransactionBalance), ctx, balance, transactionId)
 }
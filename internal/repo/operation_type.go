@@ -0,0 +1,31 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+)
+
+// ListOperationTypes returns every operation type ordered by ID
+func (r *Repository) ListOperationTypes(ctx context.Context) ([]model.OperationType, error) {
+	var operationTypes []model.OperationType
+
+	if err := r.db.WithContext(ctx).Order("id ASC").Find(&operationTypes).Error; err != nil {
+		r.logError(ctx, "error while fetching operation types", err)
+		return nil, err
+	}
+
+	return operationTypes, nil
+}
+
+// GetOperationType returns a single operation type by its ID
+func (r *Repository) GetOperationType(ctx context.Context, operationTypeId uint) (*model.OperationType, error) {
+	var operationType model.OperationType
+
+	if err := r.db.WithContext(ctx).Where("id = ?", operationTypeId).First(&operationType); err.Error != nil {
+		r.logError(ctx, "error while fetching operation type", err.Error, "operation_type_id", operationTypeId)
+		return nil, err.Error
+	}
+
+	return &operationType, nil
+}
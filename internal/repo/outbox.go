@@ -0,0 +1,153 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/vamshi1997/pismo-assessment/internal/logging"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// outboxClaimLease is how long events handed to a relay are kept from other relays. A relay which dies
+// while publishing leaves its events to be published again once the lease ran out.
+const outboxClaimLease = 5 * time.Minute
+
+// PublishPendingEvents hands up to limit unpublished outbox events, oldest first, to publish and marks
+// the accepted ones as published. The events are first claimed in a transaction of their own, so
+// several relays can run side by side without publishing the same event twice at the same time, and
+// publish runs without holding a database connection, so it can use the database itself. An event is
+// only marked after publish returned, so it is published at least once, and again if marking it fails.
+// Publishing stops at the first failure to keep the events in order; the failure is recorded on the
+// event, the claim on the remaining events is released and they are retried on the next call. It
+// returns the number of events published.
+func (r *Repository) PublishPendingEvents(ctx context.Context, limit int, publish func(event model.OutboxEvent) error) (int, error) {
+	db := r.db.WithContext(ctx)
+
+	events, err := claimPendingEvents(db, limit, time.Now().Add(outboxClaimLease))
+	if err != nil {
+		r.logError(ctx, "error while claiming outbox events", err)
+		return 0, err
+	}
+
+	published := 0
+	for i, event := range events {
+		if publishErr := publish(event); publishErr != nil {
+			logging.FromContext(ctx).Warn("error while publishing outbox event", "event_id", event.ID, "error", publishErr)
+			if err := db.Model(&model.OutboxEvent{}).
+				Where("id = ?", event.ID).
+				Updates(map[string]interface{}{
+					"attempts":   gorm.Expr("attempts + 1"),
+					"last_error": publishErr.Error(),
+				}).Error; err != nil {
+				r.logError(ctx, "error while recording outbox event failure", err, "event_id", event.ID)
+				return published, err
+			}
+			if err := db.Model(&model.OutboxEvent{}).
+				Where("id IN ?", eventIDs(events[i:])).
+				Update("claimed_until", nil).Error; err != nil {
+				r.logError(ctx, "error while releasing outbox events", err, "event_id", event.ID)
+				return published, err
+			}
+			break
+		}
+
+		if err := db.Model(&model.OutboxEvent{}).
+			Where("id = ?", event.ID).
+			Updates(map[string]interface{}{
+				"attempts":      gorm.Expr("attempts + 1"),
+				"last_error":    "",
+				"published_at":  time.Now(),
+				"claimed_until": nil,
+			}).Error; err != nil {
+			r.logError(ctx, "error while marking outbox event as published", err, "event_id", event.ID)
+			return published, err
+		}
+		published++
+	}
+
+	return published, nil
+}
+
+// claimPendingEvents returns up to limit unpublished events, oldest first, which no other relay holds a
+// claim on, and claims them until claimUntil
+func claimPendingEvents(db *gorm.DB, limit int, claimUntil time.Time) ([]model.OutboxEvent, error) {
+	var events []model.OutboxEvent
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL").
+			Where("claimed_until IS NULL OR claimed_until <= ?", time.Now()).
+			Order("id ASC").
+			Limit(limit).
+			Find(&events).Error; err != nil {
+			return err
+		}
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		return tx.Model(&model.OutboxEvent{}).
+			Where("id IN ?", eventIDs(events)).
+			Update("claimed_until", claimUntil).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func eventIDs(events []model.OutboxEvent) []uint {
+	ids := make([]uint, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+// recordEvent writes a domain event to the outbox. It should be called with the database transaction
+// making the change the event describes.
+func recordEvent(tx *gorm.DB, eventType string, aggregateType string, aggregateID uint, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&model.OutboxEvent{
+		EventType:     eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       data,
+	}).Error
+}
+
+// recordTransactionCreated writes a transaction.created event for each of the stored transactions
+func recordTransactionCreated(tx *gorm.DB, transactions ...model.Transaction) error {
+	for _, transaction := range transactions {
+		if err := recordEvent(tx, model.EventTransactionCreated, model.AggregateTransaction, transaction.ID,
+			model.NewTransactionCreatedPayload(transaction)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordBalanceChanged writes a transaction.balance_changed event when the balance actually changed
+func recordBalanceChanged(tx *gorm.DB, transaction model.Transaction, previousBalance model.Money, causedBy uint) error {
+	if transaction.Balance == previousBalance {
+		return nil
+	}
+
+	return recordEvent(tx, model.EventTransactionBalanceChanged, model.AggregateTransaction, transaction.ID,
+		model.TransactionBalanceChangedPayload{
+			TransactionID:   transaction.ID,
+			AccountID:       transaction.AccountID,
+			PreviousBalance: previousBalance,
+			Balance:         transaction.Balance,
+			CausedBy:        causedBy,
+		})
+}
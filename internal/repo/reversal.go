@@ -0,0 +1,226 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/vamshi1997/pismo-assessment/internal/metrics"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrNotReversible is returned when the transaction is a credit voucher, a reversal itself or the
+	// purchase holding an installment schedule
+	ErrNotReversible = errors.New("transaction can not be reversed")
+
+	// ErrReversalExceedsOriginal is returned when the reversal is larger than what is left to reverse
+	ErrReversalExceedsOriginal = errors.New("reversal amount exceeds the amount left to reverse")
+
+	// ErrInvalidReversalAmount is returned when the requested reversal amount is not positive
+	ErrInvalidReversalAmount = errors.New("reversal amount should be positive")
+)
+
+// ReverseTransaction creates a reversal compensating the given debit, such as a purchase or withdrawal, either for the
+// whole amount left to reverse (amount nil) or for part of it. The reversal first reduces what is still
+// outstanding on the original. If credit vouchers already paid part of the original, the rest of the
+// reversal gives that credit back to the vouchers, newest first, and records it as negative allocations.
+// Credit that can not be traced back to a voucher is kept as the reversal's own balance. Everything runs
+// in one database transaction which locks the account first, then the original and its vouchers in ID
+// order.
+func (r *Repository) ReverseTransaction(ctx context.Context, transactionId uint, amount *model.Money) (*model.Transaction, error) {
+	var reversal model.Transaction
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the account of a transaction never changes, so it can be read before anything is locked
+		var original model.Transaction
+		if err := tx.Select("id", "account_id").
+			Where("id = ?", transactionId).
+			First(&original).Error; err != nil {
+			return err
+		}
+
+		// lock the account row first, like vouchers and new transactions do, so they are serialized
+		var account model.Account
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", original.AccountID).
+			First(&account).Error; err != nil {
+			return err
+		}
+
+		// allocations of the account's debits only change while its row is locked
+		var allocations []model.Allocation
+		if err := tx.Where("debit_id = ?", original.ID).
+			Order("id ASC").
+			Find(&allocations).Error; err != nil {
+			return err
+		}
+
+		// then the original and the vouchers which paid it, in ID order
+		ids := []uint{original.ID}
+		for _, allocation := range allocations {
+			ids = append(ids, allocation.VoucherID)
+		}
+		var locked []model.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", ids).
+			Order("id ASC").
+			Find(&locked).Error; err != nil {
+			return err
+		}
+		lockedById := make(map[uint]model.Transaction, len(locked))
+		for _, transaction := range locked {
+			lockedById[transaction.ID] = transaction
+		}
+		original = lockedById[original.ID]
+
+		var operationType model.OperationType
+		if err := tx.Where("id = ?", original.OperationTypeId).First(&operationType).Error; err != nil {
+			return err
+		}
+
+		// only debits can be reversed, and a purchase with installments one installment at a time
+		if !operationType.IsDebit() || original.ReversedTransactionID != nil || original.Installments > 0 {
+			return ErrNotReversible
+		}
+
+		var reversed model.Money
+		if err := tx.Model(&model.Transaction{}).
+			Select("COALESCE(SUM(amount), 0)").
+			Where("reversed_transaction_id = ?", original.ID).
+			Scan(&reversed).Error; err != nil {
+			return err
+		}
+
+		reversible := -original.Amount - reversed
+		reversalAmount := reversible
+		if amount != nil {
+			reversalAmount = *amount
+		}
+		if reversalAmount <= 0 {
+			return ErrInvalidReversalAmount
+		}
+		if reversalAmount > reversible {
+			return ErrReversalExceedsOriginal
+		}
+
+		previousBalance := original.Balance
+		releases, unreleased := unwindDischarge(&original, reversalAmount, allocations)
+
+		if err := tx.Model(&model.Transaction{}).
+			Where("id = ?", original.ID).
+			Update("balance", original.Balance).Error; err != nil {
+			return err
+		}
+
+		changed := []model.Transaction{original}
+		previousBalances := map[uint]model.Money{original.ID: previousBalance}
+
+		for _, release := range releases {
+			voucher, ok := lockedById[release.VoucherID]
+			if !ok {
+				return fmt.Errorf("no transaction found with ID: %d", release.VoucherID)
+			}
+			previousBalances[voucher.ID] = voucher.Balance
+
+			// the release is recorded as a negative allocation, so giving the credit back is positive
+			voucher.Balance = voucher.Balance - release.Amount
+			if err := tx.Model(&model.Transaction{}).
+				Where("id = ?", voucher.ID).
+				Update("balance", voucher.Balance).Error; err != nil {
+				return err
+			}
+			changed = append(changed, voucher)
+		}
+
+		// credit which can not be given back to a voucher stays on the reversal as unapplied credit
+		reversal = model.Transaction{
+			AccountID:             original.AccountID,
+			OperationTypeId:       model.Reversal,
+			Amount:                reversalAmount,
+			Balance:               unreleased,
+			ReversedTransactionID: &original.ID,
+		}
+		if err := tx.Create(&reversal).Error; err != nil {
+			return err
+		}
+
+		if err := recordTransactionCreated(tx, reversal); err != nil {
+			return err
+		}
+		for _, transaction := range changed {
+			if err := recordBalanceChanged(tx, transaction, previousBalances[transaction.ID], reversal.ID); err != nil {
+				return err
+			}
+		}
+
+		if len(releases) == 0 {
+			return nil
+		}
+		return tx.Create(&releases).Error
+	})
+	if err != nil {
+		r.logError(ctx, "error while reversing transaction", err, "transaction_id", transactionId)
+		return nil, err
+	}
+
+	metrics.TransactionsCreated(reversal)
+	return &reversal, nil
+}
+
+// unwindDischarge applies a reversal of the given amount to the original debit. The outstanding balance of
+// the original is reduced first. Whatever is left was already paid by credit vouchers, and is given back
+// to them starting with the most recent one. It returns one negative allocation per voucher that gets
+// credit back, and the part of the amount that no allocation explains, e.g. for debits paid before
+// allocations were recorded.
+func unwindDischarge(original *model.Transaction, amount model.Money, allocations []model.Allocation) ([]model.Allocation, model.Money) {
+	outstanding := -original.Balance
+	if outstanding < 0 {
+		outstanding = 0
+	}
+
+	applied := amount
+	if applied > outstanding {
+		applied = outstanding
+	}
+	original.Balance = original.Balance + applied
+	remaining := amount - applied
+
+	// net amount each voucher has paid on this debit, taking earlier reversals into account
+	paid := map[uint]model.Money{}
+	for _, allocation := range allocations {
+		paid[allocation.VoucherID] += allocation.Amount
+	}
+
+	// voucher IDs grow over time, so the highest ID is the most recent voucher
+	vouchers := make([]uint, 0, len(paid))
+	for voucherID := range paid {
+		vouchers = append(vouchers, voucherID)
+	}
+	sort.Slice(vouchers, func(i, j int) bool {
+		return vouchers[i] > vouchers[j]
+	})
+
+	var releases []model.Allocation
+	for _, voucherID := range vouchers {
+		if remaining <= 0 {
+			break
+		}
+		if paid[voucherID] <= 0 {
+			continue
+		}
+
+		release := paid[voucherID]
+		if remaining < release {
+			release = remaining
+		}
+		remaining = remaining - release
+
+		releases = append(releases, model.Allocation{VoucherID: voucherID, DebitID: original.ID, Amount: -release})
+	}
+
+	return releases, remaining
+}
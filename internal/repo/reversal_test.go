@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+)
+
+func TestUnwindDischarge(t *testing.T) {
+	tests := []struct {
+		name               string
+		original           model.Transaction
+		amount             model.Money
+		allocations        []model.Allocation
+		expectedBalance    model.Money
+		expectedReleases   []model.Allocation
+		expectedUnreleased model.Money
+	}{
+		{
+			name:            "Reversal Of Unpaid Debit",
+			original:        model.Transaction{ID: 1, Amount: model.MustParseMoney("-100"), Balance: model.MustParseMoney("-100")},
+			amount:          model.MustParseMoney("100"),
+			expectedBalance: model.MustParseMoney("0"),
+		},
+		{
+			name:            "Partial Reversal Reduces Outstanding Balance Only",
+			original:        model.Transaction{ID: 1, Amount: model.MustParseMoney("-100"), Balance: model.MustParseMoney("-40")},
+			amount:          model.MustParseMoney("30"),
+			allocations:     []model.Allocation{{VoucherID: 5, DebitID: 1, Amount: model.MustParseMoney("60")}},
+			expectedBalance: model.MustParseMoney("-10"),
+		},
+		{
+			name:     "Full Reversal Gives Credit Back To Vouchers Newest First",
+			original: model.Transaction{ID: 1, Amount: model.MustParseMoney("-100"), Balance: model.MustParseMoney("-40")},
+			amount:   model.MustParseMoney("100"),
+			allocations: []model.Allocation{
+				{VoucherID: 5, DebitID: 1, Amount: model.MustParseMoney("20")},
+				{VoucherID: 8, DebitID: 1, Amount: model.MustParseMoney("40")},
+			},
+			expectedBalance: model.MustParseMoney("0"),
+			expectedReleases: []model.Allocation{
+				{VoucherID: 8, DebitID: 1, Amount: model.MustParseMoney("-40")},
+				{VoucherID: 5, DebitID: 1, Amount: model.MustParseMoney("-20")},
+			},
+		},
+		{
+			name:     "Partial Reversal Of Fully Paid Debit",
+			original: model.Transaction{ID: 1, Amount: model.MustParseMoney("-100"), Balance: model.MustParseMoney("0")},
+			amount:   model.MustParseMoney("30"),
+			allocations: []model.Allocation{
+				{VoucherID: 5, DebitID: 1, Amount: model.MustParseMoney("70")},
+				{VoucherID: 8, DebitID: 1, Amount: model.MustParseMoney("30")},
+				{VoucherID: 8, DebitID: 1, Amount: model.MustParseMoney("-10")},
+			},
+			expectedBalance: model.MustParseMoney("0"),
+			expectedReleases: []model.Allocation{
+				{VoucherID: 8, DebitID: 1, Amount: model.MustParseMoney("-20")},
+				{VoucherID: 5, DebitID: 1, Amount: model.MustParseMoney("-10")},
+			},
+		},
+		{
+			name:               "Paid Debit Without Allocations Keeps Credit On Reversal",
+			original:           model.Transaction{ID: 1, Amount: model.MustParseMoney("-100"), Balance: model.MustParseMoney("-25")},
+			amount:             model.MustParseMoney("100"),
+			expectedBalance:    model.MustParseMoney("0"),
+			expectedUnreleased: model.MustParseMoney("75"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.original
+
+			releases, unreleased := unwindDischarge(&original, tt.amount, tt.allocations)
+
+			assert.Equal(t, tt.expectedBalance, original.Balance)
+			assert.Equal(t, tt.expectedReleases, releases)
+			assert.Equal(t, tt.expectedUnreleased, unreleased)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package repo
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vamshi1997/pismo-assessment/internal/boot"
+	"github.com/vamshi1997/pismo-assessment/internal/logging"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+)
+
+// newSQLiteRepository returns a repository on a migrated in-memory SQLite database
+func newSQLiteRepository(t *testing.T) IRepository {
+	db, err := boot.OpenDB(boot.DBConfig{Driver: boot.DriverSQLite, Path: ":memory:"})
+	require.NoError(t, err)
+	require.NoError(t, boot.Migrate(db))
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	return NewRepository(db)
+}
+
+func TestRepository_SQLite(t *testing.T) {
+	r := newSQLiteRepository(t)
+	ctx := context.Background()
+
+	operationTypes, err := r.ListOperationTypes(ctx)
+	require.NoError(t, err)
+	assert.Len(t, operationTypes, len(model.DefaultOperationTypes))
+
+	account, err := r.CreateAccount(ctx, model.Account{DocumentNumber: "12345678900", BillingDay: model.DefaultBillingDay})
+	require.NoError(t, err)
+
+	purchase, err := r.CreateTransaction(ctx, model.Transaction{
+		AccountID:       account.ID,
+		OperationTypeId: model.NormalPurchase,
+		Amount:          model.MustParseMoney("-50.25"),
+		Balance:         model.MustParseMoney("-50.25"),
+	})
+	require.NoError(t, err)
+
+	voucher, err := r.DischargeCreditVoucher(ctx, model.Transaction{
+		AccountID:       account.ID,
+		OperationTypeId: model.CreditVoucher,
+		Amount:          model.MustParseMoney("20"),
+		Balance:         model.MustParseMoney("20"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, model.Money(0), voucher.Balance)
+
+	stored, err := r.GetTransaction(ctx, purchase.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.MustParseMoney("-30.25"), stored.Balance)
+	assert.Equal(t, model.NormalizeEventDate(purchase.EventDate), stored.EventDate)
+
+	from, err := model.ParseEventDateBound(stored.EventDate, false)
+	require.NoError(t, err)
+	transactions, err := r.ListAccountTransactions(ctx, TransactionFilter{AccountID: account.ID, FromEventDate: from})
+	require.NoError(t, err)
+	require.Len(t, transactions, 2)
+	assert.Equal(t, purchase.ID, transactions[0].ID)
+
+	netBalance, err := r.GetAccountNetBalance(ctx, account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.MustParseMoney("-30.25"), netBalance)
+
+	outstanding, err := r.GetOutstandingTransactions(ctx, account.ID)
+	require.NoError(t, err)
+	require.Len(t, outstanding, 1)
+	assert.Equal(t, purchase.ID, outstanding[0].ID)
+
+	allocations, err := r.ListTransactionAllocations(ctx, voucher.ID)
+	require.NoError(t, err)
+	require.Len(t, allocations, 1)
+	assert.Equal(t, purchase.ID, allocations[0].DebitID)
+	assert.Equal(t, model.MustParseMoney("20"), allocations[0].Amount)
+
+	// reversing the purchase clears what is outstanding and gives the voucher its credit back
+	reversal, err := r.ReverseTransaction(ctx, purchase.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, model.MustParseMoney("50.25"), reversal.Amount)
+	assert.Equal(t, model.Money(0), reversal.Balance)
+
+	stored, err = r.GetTransaction(ctx, purchase.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.Money(0), stored.Balance)
+	stored, err = r.GetTransaction(ctx, voucher.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.MustParseMoney("20"), stored.Balance)
+}
+
+func TestRepository_SQLite_CancelledContext(t *testing.T) {
+	r := newSQLiteRepository(t)
+
+	account, err := r.CreateAccount(context.Background(), model.Account{DocumentNumber: "12345678900", BillingDay: model.DefaultBillingDay})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = r.GetAccount(ctx, account.ID)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = r.CreateTransaction(ctx, model.Transaction{
+		AccountID:       account.ID,
+		OperationTypeId: model.NormalPurchase,
+		Amount:          model.MustParseMoney("-50.25"),
+		Balance:         model.MustParseMoney("-50.25"),
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	transactions, err := r.ListAccountTransactions(context.Background(), TransactionFilter{AccountID: account.ID})
+	require.NoError(t, err)
+	assert.Empty(t, transactions)
+}
+
+func TestRepository_SQLite_DocumentNumberTaken(t *testing.T) {
+	r := newSQLiteRepository(t)
+
+	var out bytes.Buffer
+	ctx := logging.WithLogger(context.Background(), slog.New(slog.NewJSONHandler(&out, nil)))
+
+	_, err := r.CreateAccount(ctx, model.Account{DocumentNumber: "52998224725", BillingDay: model.DefaultBillingDay})
+	require.NoError(t, err)
+
+	_, err = r.CreateAccount(ctx, model.Account{DocumentNumber: "52998224725", BillingDay: model.DefaultBillingDay})
+	assert.ErrorIs(t, err, ErrDocumentNumberTaken)
+	assert.NotContains(t, err.Error(), "52998224725")
+
+	// the database's own error names the duplicated value, it must not reach the logs
+	assert.Contains(t, out.String(), `"error":"an account with this document number already exists"`)
+	assert.Contains(t, out.String(), `"level":"INFO"`)
+	assert.NotContains(t, out.String(), "52998224725")
+}
@@ -0,0 +1,184 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CloseStatements closes every billing cycle of an account which ended before now and has no statement
+// yet, oldest first, and stores their snapshots. The first cycle of an account starts when the account
+// was created, and each following cycle where the previous one ended. Cycles of the same account are
+// closed one caller at a time, so a cycle gets exactly one statement.
+func (r *Repository) CloseStatements(ctx context.Context, accountId uint, now time.Time) ([]model.Statement, error) {
+	var statements []model.Statement
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var account model.Account
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", accountId).
+			First(&account).Error; err != nil {
+			return err
+		}
+
+		periodStart := account.CreatedAt
+		var openingBalance model.Money
+
+		var last model.Statement
+		err := tx.Where("account_id = ?", account.ID).Order("period_end DESC").First(&last).Error
+		switch {
+		case err == nil:
+			periodStart = last.PeriodEnd
+			openingBalance = last.ClosingBalance
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			// transactions stored before the first cycle, if any, open the first statement
+			if err = billedBefore(tx, account.ID, periodStart).
+				Select("COALESCE(SUM(amount), 0)").
+				Scan(&openingBalance).Error; err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+
+		billingDay := account.BillingDay
+		if !model.IsValidBillingDay(billingDay) {
+			billingDay = model.DefaultBillingDay
+		}
+
+		for {
+			periodEnd := model.NextCycleClose(periodStart, billingDay)
+			if periodEnd.After(now) {
+				return nil
+			}
+
+			var totals []model.StatementTotal
+			if err = billedBetween(tx, account.ID, periodStart, periodEnd).
+				Select("operation_type_id, COALESCE(SUM(amount), 0) AS amount, COUNT(*) AS count").
+				Group("operation_type_id").
+				Order("operation_type_id ASC").
+				Scan(&totals).Error; err != nil {
+				return err
+			}
+
+			statement := model.BuildStatement(account.ID, periodStart, periodEnd, openingBalance, totals)
+			if err = tx.Create(&statement).Error; err != nil {
+				return err
+			}
+			statements = append(statements, statement)
+
+			periodStart = periodEnd
+			openingBalance = statement.ClosingBalance
+		}
+	})
+	if err != nil {
+		r.logError(ctx, "error while closing statements", err, "account_id", accountId)
+		return nil, err
+	}
+
+	return statements, nil
+}
+
+// ListStatements returns the statements of an account, latest cycle first
+func (r *Repository) ListStatements(ctx context.Context, accountId uint) ([]model.Statement, error) {
+	var statements []model.Statement
+
+	if err := r.db.WithContext(ctx).Where("account_id = ?", accountId).Order("period_start DESC").Find(&statements).Error; err != nil {
+		r.logError(ctx, "error while fetching statements", err, "account_id", accountId)
+		return nil, err
+	}
+
+	return statements, nil
+}
+
+// GetStatement returns a single statement of an account
+func (r *Repository) GetStatement(ctx context.Context, accountId uint, statementId uint) (*model.Statement, error) {
+	var statement model.Statement
+
+	if err := r.db.WithContext(ctx).Where("id = ? AND account_id = ?", statementId, accountId).First(&statement).Error; err != nil {
+		r.logError(ctx, "error while fetching statement", err, "account_id", accountId, "statement_id", statementId)
+		return nil, err
+	}
+
+	return &statement, nil
+}
+
+// ListStatementTransactions returns the transactions billed in the cycle of a statement, in billing order
+func (r *Repository) ListStatementTransactions(ctx context.Context, statement model.Statement) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+
+	if err := billedBetween(r.db.WithContext(ctx), statement.AccountID, statement.PeriodStart, statement.PeriodEnd).
+		Order("COALESCE(due_date, event_date) ASC").
+		Order("id ASC").
+		Find(&transactions).Error; err != nil {
+		r.logError(ctx, "error while fetching statement transactions", err, "statement_id", statement.ID)
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// UpdateBillingDay changes the day of the month the billing cycle of an account closes on. The cycle
+// already running closes on the new day.
+func (r *Repository) UpdateBillingDay(ctx context.Context, accountId uint, billingDay uint) (*model.Account, error) {
+	var account model.Account
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", accountId).
+			First(&account).Error; err != nil {
+			return err
+		}
+
+		account.BillingDay = billingDay
+		return tx.Model(&model.Account{}).
+			Where("id = ?", account.ID).
+			Update("billing_day", billingDay).Error
+	})
+	if err != nil {
+		r.logError(ctx, "error while updating billing day", err, "account_id", accountId)
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// ListAccountIDs returns up to limit account IDs greater than afterId, in ascending order
+func (r *Repository) ListAccountIDs(ctx context.Context, afterId uint, limit int) ([]uint, error) {
+	var ids []uint
+
+	if err := r.db.WithContext(ctx).Model(&model.Account{}).
+		Where("id > ?", afterId).
+		Order("id ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		r.logError(ctx, "error while listing account ids", err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// billedBetween selects the transactions of an account billed in [from, to). Installments are billed
+// when they are due, other transactions at their event date, and a purchase with installments is only
+// billed through its installments.
+func billedBetween(db *gorm.DB, accountId uint, from, to time.Time) *gorm.DB {
+	return db.Model(&model.Transaction{}).
+		Where("account_id = ?", accountId).
+		Where("installments = ?", 0).
+		Where("(due_date IS NULL AND event_date >= ? AND event_date < ?) OR (due_date IS NOT NULL AND due_date >= ? AND due_date < ?)",
+			from.In(model.IST).Format(model.EventDateLayout), to.In(model.IST).Format(model.EventDateLayout), from, to)
+}
+
+// billedBefore selects the transactions of an account billed before the given time
+func billedBefore(db *gorm.DB, accountId uint, before time.Time) *gorm.DB {
+	return db.Model(&model.Transaction{}).
+		Where("account_id = ?", accountId).
+		Where("installments = ?", 0).
+		Where("(due_date IS NULL AND event_date < ?) OR (due_date IS NOT NULL AND due_date < ?)",
+			before.In(model.IST).Format(model.EventDateLayout), before)
+}
@@ -1,60 +1,326 @@
 package repo
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"github.com/vamshi1997/pismo-assessment/internal/metrics"
 	"github.com/vamshi1997/pismo-assessment/internal/model"
-	"log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
 var IST = time.FixedZone("IST", 5*3600+1800)
 
-func (r *Repository) CreateTransaction(transaction model.Transaction) (*model.Transaction, error) {
-	if err := r.db.Create(&transaction); err.Error != nil {
-		log.Println("Error while creating transaction: ", err.Error)
-		return nil, err.Error
+func (r *Repository) CreateTransaction(ctx context.Context, transaction model.Transaction) (*model.Transaction, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// debt taken by the transaction has to fit in the credit limit of the account
+		if transaction.Balance < 0 {
+			if err := reserveCreditLimit(tx, transaction.AccountID, transaction.Balance); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(&transaction).Error; err != nil {
+			return err
+		}
+		return recordTransactionCreated(tx, transaction)
+	})
+	if err != nil {
+		r.logError(ctx, "error while creating transaction", err, "account_id", transaction.AccountID, "operation_type_id", transaction.OperationTypeId)
+		return nil, err
 	}
 
+	metrics.TransactionsCreated(transaction)
 	return &transaction, nil
 }
 
-func (r *Repository) UpdateTransactionBalance(balance float64, transactionId uint) (*model.Transaction, error) {
-	result := r.db.Model(&model.Transaction{}).
-		Where("id = ?", transactionId).
-		Update("balance", balance)
+func (r *Repository) UpdateTransactionBalance(ctx context.Context, balance model.Money, transactionId uint) (*model.Transaction, error) {
+	var updatedTransaction model.Transaction
 
-	if result.Error != nil {
-		log.Printf("Error updating transaction balance: %v", result.Error)
-		return nil, result.Error
-	}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var previous model.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&previous, transactionId).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("no transaction found with ID: %d", transactionId)
+			}
+			return err
+		}
 
-	if result.RowsAffected == 0 {
-		return nil, fmt.Errorf("no transaction found with ID: %d", transactionId)
-	}
+		if err := tx.Model(&model.Transaction{}).
+			Where("id = ?", transactionId).
+			Update("balance", balance).Error; err != nil {
+			return err
+		}
 
-	// Fetch the updated transaction
-	var updatedTransaction model.Transaction
-	if err := r.db.First(&updatedTransaction, transactionId).Error; err != nil {
-		return nil, fmt.Errorf("error fetching updated transaction: %w", err)
+		// Fetch the updated transaction
+		if err := tx.First(&updatedTransaction, transactionId).Error; err != nil {
+			return fmt.Errorf("error fetching updated transaction: %w", err)
+		}
+
+		return recordBalanceChanged(tx, updatedTransaction, previous.Balance, 0)
+	})
+	if err != nil {
+		r.logError(ctx, "error while updating transaction balance", err, "transaction_id", transactionId)
+		return nil, err
 	}
 
 	return &updatedTransaction, nil
 }
 
-func (r *Repository) GetPreviousTransactions() ([]model.Transaction, error) {
+// GetOutstandingTransactions returns the transactions of the given account which still have
+// a negative balance and are due, oldest first, so that a credit voucher only discharges its own
+// account's debt
+func (r *Repository) GetOutstandingTransactions(ctx context.Context, accountId uint) ([]model.Transaction, error) {
+	db := r.db.WithContext(ctx)
 	var transactions []model.Transaction
 	currentDate := time.Now().In(IST)
 
-	result := r.db.
+	result := db.
+		Where("account_id = ?", accountId).
 		Where("created_at < ?", currentDate).
 		Where("balance < ?", 0).
+		Where("(due_date IS NULL OR due_date <= ?)", currentDate).
+		Where("operation_type_id IN (?)", db.Model(&model.OperationType{}).
+			Select("id").
+			Where("discharge_eligible = ?", true)).
+		Order("event_date ASC").
+		Find(&transactions)
+
+	if result.Error != nil {
+		r.logError(ctx, "error while fetching outstanding transactions", result.Error, "account_id", accountId)
+		return nil, result.Error
+	}
+
+	return transactions, nil
+}
+
+// ListAccountTransactions returns one page of an account's transactions matching the filter,
+// ordered by event date
+func (r *Repository) ListAccountTransactions(ctx context.Context, filter TransactionFilter) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+
+	query := r.db.WithContext(ctx).Where("account_id = ?", filter.AccountID)
+
+	if filter.OperationTypeID != 0 {
+		query = query.Where("operation_type_id = ?", filter.OperationTypeID)
+	}
+	if filter.FromEventDate != "" {
+		query = query.Where("event_date >= ?", filter.FromEventDate)
+	}
+	if filter.ToEventDate != "" {
+		query = query.Where("event_date <= ?", filter.ToEventDate)
+	}
+	if filter.MinAmount != nil {
+		query = query.Where("amount >= ?", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		query = query.Where("amount <= ?", *filter.MaxAmount)
+	}
+	if filter.AfterEventDate != "" {
+		query = query.Where("(event_date > ? OR (event_date = ? AND id > ?))",
+			filter.AfterEventDate, filter.AfterEventDate, filter.AfterID)
+	}
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	result := query.
 		Order("event_date ASC").
+		Order("id ASC").
 		Find(&transactions)
 
 	if result.Error != nil {
-		log.Printf("Error while fetching previous transactions: %v", result.Error)
+		r.logError(ctx, "error while listing transactions", result.Error, "account_id", filter.AccountID)
 		return nil, result.Error
 	}
 
 	return transactions, nil
 }
+
+// GetTransaction returns a single transaction by its ID
+func (r *Repository) GetTransaction(ctx context.Context, transactionId uint) (*model.Transaction, error) {
+	var transaction model.Transaction
+
+	if err := r.db.WithContext(ctx).Where("id = ?", transactionId).First(&transaction); err.Error != nil {
+		r.logError(ctx, "error while fetching transaction", err.Error, "transaction_id", transactionId)
+		return nil, err.Error
+	}
+
+	return &transaction, nil
+}
+
+// ListTransactionAllocations returns the allocations a transaction took part in, either as the voucher
+// which paid or as the debit which was paid, oldest first
+func (r *Repository) ListTransactionAllocations(ctx context.Context, transactionId uint) ([]model.Allocation, error) {
+	var allocations []model.Allocation
+
+	result := r.db.WithContext(ctx).
+		Where("voucher_id = ? OR debit_id = ?", transactionId, transactionId).
+		Order("id ASC").
+		Find(&allocations)
+
+	if result.Error != nil {
+		r.logError(ctx, "error while fetching allocations", result.Error, "transaction_id", transactionId)
+		return nil, result.Error
+	}
+
+	return allocations, nil
+}
+
+// OperationTypeBalance is the aggregated balance of an account's transactions of one operation type.
+// OutstandingDebt sums the negative balances which are due and UnappliedCredit the positive ones.
+// ScheduledDebt sums the installments which are not due yet.
+type OperationTypeBalance struct {
+	OperationTypeID  uint
+	OutstandingDebt  model.Money
+	ScheduledDebt    model.Money
+	UnappliedCredit  model.Money
+	TransactionCount int64
+}
+
+// GetAccountBalances aggregates the balance column of an account's transactions per operation type
+// in the database, without loading the transactions themselves
+func (r *Repository) GetAccountBalances(ctx context.Context, accountId uint) ([]OperationTypeBalance, error) {
+	var balances []OperationTypeBalance
+
+	now := time.Now()
+
+	result := r.db.WithContext(ctx).Model(&model.Transaction{}).
+		Select("operation_type_id, "+
+			"COALESCE(SUM(CASE WHEN balance < 0 AND (due_date IS NULL OR due_date <= ?) THEN balance ELSE 0 END), 0) AS outstanding_debt, "+
+			"COALESCE(SUM(CASE WHEN balance < 0 AND due_date > ? THEN balance ELSE 0 END), 0) AS scheduled_debt, "+
+			"COALESCE(SUM(CASE WHEN balance > 0 THEN balance ELSE 0 END), 0) AS unapplied_credit, "+
+			"COUNT(*) AS transaction_count", now, now).
+		Where("account_id = ?", accountId).
+		Group("operation_type_id").
+		Order("operation_type_id ASC").
+		Scan(&balances)
+
+	if result.Error != nil {
+		r.logError(ctx, "error while aggregating balances", result.Error, "account_id", accountId)
+		return nil, result.Error
+	}
+
+	return balances, nil
+}
+
+// DischargeCreditVoucher settles the outstanding debt of the voucher's account, oldest first, and
+// stores the voucher with whatever amount is left over. Every balance update and the voucher insert
+// run in one database transaction with the account and debt rows locked, so a failure or a
+// concurrent voucher for the same account can never leave balances half-applied or applied twice.
+func (r *Repository) DischargeCreditVoucher(ctx context.Context, voucher model.Transaction) (*model.Transaction, error) {
+	db := r.db.WithContext(ctx)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// lock the account row first so vouchers of the same account are serialized
+		var account model.Account
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", voucher.AccountID).
+			First(&account).Error; err != nil {
+			return err
+		}
+		if !account.Status.AllowsCredits() {
+			return ErrTransactionNotAllowed
+		}
+
+		// installments which are not due yet and operation types not eligible for discharge are left alone
+		var debts []model.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("account_id = ?", voucher.AccountID).
+			Where("balance < ?", 0).
+			Where("(due_date IS NULL OR due_date <= ?)", time.Now()).
+			Where("operation_type_id IN (?)", db.Model(&model.OperationType{}).
+				Select("id").
+				Where("discharge_eligible = ?", true)).
+			Order("event_date ASC").
+			Order("id ASC").
+			Find(&debts).Error; err != nil {
+			return err
+		}
+
+		previousBalances := make(map[uint]model.Money, len(debts))
+		for _, debt := range debts {
+			previousBalances[debt.ID] = debt.Balance
+		}
+
+		changed, allocations := dischargeDebts(&voucher, debts)
+
+		for _, debt := range changed {
+			result := tx.Model(&model.Transaction{}).
+				Where("id = ?", debt.ID).
+				Update("balance", debt.Balance)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return fmt.Errorf("no transaction found with ID: %d", debt.ID)
+			}
+		}
+
+		if err := tx.Create(&voucher).Error; err != nil {
+			return err
+		}
+
+		if err := recordTransactionCreated(tx, voucher); err != nil {
+			return err
+		}
+		for _, debt := range changed {
+			if err := recordBalanceChanged(tx, debt, previousBalances[debt.ID], voucher.ID); err != nil {
+				return err
+			}
+		}
+
+		// record which debt the voucher paid, in the same unit of work as the balance updates
+		if len(allocations) == 0 {
+			return nil
+		}
+		for i := range allocations {
+			allocations[i].VoucherID = voucher.ID
+		}
+		return tx.Create(&allocations).Error
+	})
+	if err != nil {
+		r.logError(ctx, "error while discharging credit voucher", err, "account_id", voucher.AccountID)
+		return nil, err
+	}
+
+	metrics.TransactionsCreated(voucher)
+	metrics.Discharged(voucher.Amount - voucher.Balance)
+	return &voucher, nil
+}
+
+// dischargeDebts applies the voucher amount to the given debts in order and returns the debts whose
+// balance changed together with the amount applied to each of them. The voucher balance is set to the
+// amount that could not be applied. Debts which belong to another account or are already settled are
+// skipped.
+func dischargeDebts(voucher *model.Transaction, debts []model.Transaction) ([]model.Transaction, []model.Allocation) {
+	var (
+		remaining   = voucher.Amount
+		changed     []model.Transaction
+		allocations []model.Allocation
+	)
+
+	for _, debt := range debts {
+		if remaining <= 0 {
+			break
+		}
+		if debt.AccountID != voucher.AccountID || debt.Balance >= 0 {
+			continue
+		}
+
+		applied := -debt.Balance
+		if remaining < applied {
+			applied = remaining
+		}
+
+		debt.Balance = debt.Balance + applied
+		remaining = remaining - applied
+
+		changed = append(changed, debt)
+		allocations = append(allocations, model.Allocation{DebitID: debt.ID, Amount: applied})
+	}
+
+	voucher.Balance = remaining
+	return changed, allocations
+}
@@ -0,0 +1,122 @@
+package repo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+)
+
+func TestDischargeDebts(t *testing.T) {
+	tests := []struct {
+		name                string
+		voucher             model.Transaction
+		debts               []model.Transaction
+		expectedChanged     []model.Transaction
+		expectedAllocations []model.Allocation
+		expectedBalance     model.Money
+	}{
+		{
+			name:    "Voucher Partially Settles Oldest Debt",
+			voucher: model.Transaction{AccountID: 1, OperationTypeId: 4, Amount: model.MustParseMoney("100")},
+			debts: []model.Transaction{
+				{ID: 1, AccountID: 1, Balance: model.MustParseMoney("-150")},
+			},
+			expectedChanged: []model.Transaction{
+				{ID: 1, AccountID: 1, Balance: model.MustParseMoney("-50")},
+			},
+			expectedAllocations: []model.Allocation{
+				{DebitID: 1, Amount: model.MustParseMoney("100")},
+			},
+			expectedBalance: model.MustParseMoney("0"),
+		},
+		{
+			name:    "Voucher Settles Debts In Order And Keeps Leftover",
+			voucher: model.Transaction{AccountID: 1, OperationTypeId: 4, Amount: model.MustParseMoney("100")},
+			debts: []model.Transaction{
+				{ID: 1, AccountID: 1, Balance: model.MustParseMoney("-40")},
+				{ID: 2, AccountID: 1, Balance: model.MustParseMoney("-30")},
+			},
+			expectedChanged: []model.Transaction{
+				{ID: 1, AccountID: 1, Balance: model.MustParseMoney("0")},
+				{ID: 2, AccountID: 1, Balance: model.MustParseMoney("0")},
+			},
+			expectedAllocations: []model.Allocation{
+				{DebitID: 1, Amount: model.MustParseMoney("40")},
+				{DebitID: 2, Amount: model.MustParseMoney("30")},
+			},
+			expectedBalance: model.MustParseMoney("30"),
+		},
+		{
+			name:    "Voucher Stops Once Amount Is Used",
+			voucher: model.Transaction{AccountID: 2, OperationTypeId: 4, Amount: model.MustParseMoney("60")},
+			debts: []model.Transaction{
+				{ID: 3, AccountID: 2, Balance: model.MustParseMoney("-40")},
+				{ID: 4, AccountID: 2, Balance: model.MustParseMoney("-50")},
+				{ID: 5, AccountID: 2, Balance: model.MustParseMoney("-10")},
+			},
+			expectedChanged: []model.Transaction{
+				{ID: 3, AccountID: 2, Balance: model.MustParseMoney("0")},
+				{ID: 4, AccountID: 2, Balance: model.MustParseMoney("-30")},
+			},
+			expectedAllocations: []model.Allocation{
+				{DebitID: 3, Amount: model.MustParseMoney("40")},
+				{DebitID: 4, Amount: model.MustParseMoney("20")},
+			},
+			expectedBalance: model.MustParseMoney("0"),
+		},
+		{
+			name:    "Voucher Never Settles Other Accounts",
+			voucher: model.Transaction{AccountID: 1, OperationTypeId: 4, Amount: model.MustParseMoney("100")},
+			debts: []model.Transaction{
+				{ID: 6, AccountID: 2, Balance: model.MustParseMoney("-80")},
+				{ID: 7, AccountID: 1, Balance: model.MustParseMoney("-30")},
+				{ID: 8, AccountID: 3, Balance: model.MustParseMoney("-20")},
+			},
+			expectedChanged: []model.Transaction{
+				{ID: 7, AccountID: 1, Balance: model.MustParseMoney("0")},
+			},
+			expectedAllocations: []model.Allocation{
+				{DebitID: 7, Amount: model.MustParseMoney("30")},
+			},
+			expectedBalance: model.MustParseMoney("70"),
+		},
+		{
+			name:    "Voucher Settles Fractional Debts Exactly",
+			voucher: model.Transaction{AccountID: 1, OperationTypeId: 4, Amount: model.MustParseMoney("0.3")},
+			debts: []model.Transaction{
+				{ID: 9, AccountID: 1, Balance: model.MustParseMoney("-0.1")},
+				{ID: 10, AccountID: 1, Balance: model.MustParseMoney("-0.2")},
+			},
+			expectedChanged: []model.Transaction{
+				{ID: 9, AccountID: 1, Balance: model.MustParseMoney("0")},
+				{ID: 10, AccountID: 1, Balance: model.MustParseMoney("0")},
+			},
+			expectedAllocations: []model.Allocation{
+				{DebitID: 9, Amount: model.MustParseMoney("0.1")},
+				{DebitID: 10, Amount: model.MustParseMoney("0.2")},
+			},
+			expectedBalance: model.MustParseMoney("0"),
+		},
+		{
+			name:                "Voucher Without Debt",
+			voucher:             model.Transaction{AccountID: 1, OperationTypeId: 4, Amount: model.MustParseMoney("25")},
+			debts:               []model.Transaction{},
+			expectedChanged:     nil,
+			expectedAllocations: nil,
+			expectedBalance:     model.MustParseMoney("25"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			voucher := tt.voucher
+
+			changed, allocations := dischargeDebts(&voucher, tt.debts)
+
+			assert.Equal(t, tt.expectedChanged, changed)
+			assert.Equal(t, tt.expectedAllocations, allocations)
+			assert.Equal(t, tt.expectedBalance, voucher.Balance)
+		})
+	}
+}
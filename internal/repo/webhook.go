@@ -0,0 +1,245 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrDeliveryNotDead is returned when requeuing a delivery which is not dead-lettered
+var ErrDeliveryNotDead = errors.New("only dead-lettered deliveries can be requeued")
+
+// CreateWebhookSubscription stores a new webhook subscription
+func (r *Repository) CreateWebhookSubscription(ctx context.Context, subscription model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	if err := r.db.WithContext(ctx).Create(&subscription).Error; err != nil {
+		r.logError(ctx, "error while creating webhook subscription", err)
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// ListWebhookSubscriptions returns every webhook subscription ordered by ID
+func (r *Repository) ListWebhookSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	var subscriptions []model.WebhookSubscription
+
+	if err := r.db.WithContext(ctx).Order("id ASC").Find(&subscriptions).Error; err != nil {
+		r.logError(ctx, "error while fetching webhook subscriptions", err)
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// GetWebhookSubscription returns a single webhook subscription by its ID
+func (r *Repository) GetWebhookSubscription(ctx context.Context, subscriptionId uint) (*model.WebhookSubscription, error) {
+	var subscription model.WebhookSubscription
+
+	if err := r.db.WithContext(ctx).Where("id = ?", subscriptionId).First(&subscription).Error; err != nil {
+		r.logError(ctx, "error while fetching webhook subscription", err, "subscription_id", subscriptionId)
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// DeleteWebhookSubscription removes a webhook subscription. Its pending deliveries are dead-lettered by
+// the delivery worker when they come up.
+func (r *Repository) DeleteWebhookSubscription(ctx context.Context, subscriptionId uint) error {
+	result := r.db.WithContext(ctx).Where("id = ?", subscriptionId).Delete(&model.WebhookSubscription{})
+	if result.Error != nil {
+		r.logError(ctx, "error while deleting webhook subscription", result.Error, "subscription_id", subscriptionId)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// EnqueueWebhookDeliveries creates a pending delivery of the event for every subscription wanting its
+// type. An event handed over again does not create a second delivery for the same subscription.
+func (r *Repository) EnqueueWebhookDeliveries(ctx context.Context, eventId uint, eventType string, payload []byte) (int, error) {
+	subscriptions, err := r.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	var deliveries []model.WebhookDelivery
+	for _, subscription := range subscriptions {
+		if !subscription.Subscribes(eventType) {
+			continue
+		}
+		deliveries = append(deliveries, model.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        eventId,
+			EventType:      eventType,
+			Payload:        payload,
+			Status:         model.DeliveryPending,
+			NextAttemptAt:  now,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.WithContext(ctx).Omit("Subscription").Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries)
+	if result.Error != nil {
+		r.logError(ctx, "error while enqueuing webhook deliveries", result.Error, "event_id", eventId)
+		return 0, result.Error
+	}
+
+	return int(result.RowsAffected), nil
+}
+
+// ClaimDueWebhookDeliveries returns up to limit pending deliveries which are due, with their
+// subscription, and pushes their next attempt to leaseUntil. Another worker therefore does not pick
+// the same deliveries while they are being sent, and a worker which dies while sending leaves them to
+// be retried once the lease ran out.
+func (r *Repository) ClaimDueWebhookDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", model.DeliveryPending).
+			Where("next_attempt_at <= ?", time.Now()).
+			Order("next_attempt_at ASC").
+			Order("id ASC").
+			Limit(limit).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(deliveries))
+		subscriptionIds := make([]uint, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.ID)
+			subscriptionIds = append(subscriptionIds, delivery.SubscriptionID)
+		}
+		if err := tx.Model(&model.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", leaseUntil).Error; err != nil {
+			return err
+		}
+
+		// deleted subscriptions are loaded too, so the worker can tell them apart and dead-letter them
+		var subscriptions []model.WebhookSubscription
+		if err := tx.Unscoped().Where("id IN ?", subscriptionIds).Find(&subscriptions).Error; err != nil {
+			return err
+		}
+		byID := make(map[uint]model.WebhookSubscription, len(subscriptions))
+		for _, subscription := range subscriptions {
+			byID[subscription.ID] = subscription
+		}
+		for i := range deliveries {
+			deliveries[i].Subscription = byID[deliveries[i].SubscriptionID]
+		}
+		return nil
+	})
+	if err != nil {
+		r.logError(ctx, "error while claiming webhook deliveries", err)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// RecordWebhookAttempt stores the outcome of an attempt and moves the delivery to its new status. A
+// pending delivery is retried at nextAttemptAt.
+func (r *Repository) RecordWebhookAttempt(ctx context.Context, attempt model.WebhookAttempt, status string, nextAttemptAt time.Time) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&attempt).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"status":          status,
+			"attempts":        attempt.AttemptNumber,
+			"last_error":      attempt.Error,
+			"next_attempt_at": nextAttemptAt,
+		}
+		if status == model.DeliveryDelivered {
+			updates["delivered_at"] = attempt.CreatedAt
+		}
+
+		return tx.Model(&model.WebhookDelivery{}).
+			Where("id = ?", attempt.DeliveryID).
+			Updates(updates).Error
+	})
+	if err != nil {
+		r.logError(ctx, "error while recording webhook attempt", err, "delivery_id", attempt.DeliveryID)
+		return err
+	}
+
+	return nil
+}
+
+// ListWebhookDeliveries returns the deliveries of a subscription with their attempts, newest first,
+// optionally only those with the given status
+func (r *Repository) ListWebhookDeliveries(ctx context.Context, subscriptionId uint, status string, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+
+	query := r.db.WithContext(ctx).
+		Preload("AttemptLog", func(db *gorm.DB) *gorm.DB {
+			return db.Order("attempt_number ASC")
+		}).
+		Where("subscription_id = ?", subscriptionId)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Order("id DESC").Find(&deliveries).Error; err != nil {
+		r.logError(ctx, "error while fetching webhook deliveries", err, "subscription_id", subscriptionId)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// RequeueWebhookDelivery gives a dead-lettered delivery a new series of attempts, starting right away
+func (r *Repository) RequeueWebhookDelivery(ctx context.Context, subscriptionId uint, deliveryId uint) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND subscription_id = ?", deliveryId, subscriptionId).
+			First(&delivery).Error; err != nil {
+			return err
+		}
+
+		if delivery.Status != model.DeliveryDead {
+			return ErrDeliveryNotDead
+		}
+
+		delivery.Status = model.DeliveryPending
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = time.Now()
+		return tx.Model(&model.WebhookDelivery{}).
+			Where("id = ?", delivery.ID).
+			Updates(map[string]interface{}{
+				"status":          delivery.Status,
+				"attempts":        delivery.Attempts,
+				"next_attempt_at": delivery.NextAttemptAt,
+			}).Error
+	})
+	if err != nil {
+		r.logError(ctx, "error while requeuing webhook delivery", err, "subscription_id", subscriptionId, "delivery_id", deliveryId)
+		return nil, err
+	}
+
+	return &delivery, nil
+}
@@ -3,26 +3,39 @@ package router
 import (
 	"fmt"
 	"gorm.io/gorm"
-	"log"
+	"log/slog"
+	"net/http"
 
 	"github.com/vamshi1997/pismo-assessment/internal/boot"
+	"github.com/vamshi1997/pismo-assessment/internal/metrics"
+	"github.com/vamshi1997/pismo-assessment/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
-func InitiateRouter(db *gorm.DB) {
+// NewServer returns the HTTP server of the application with the timeouts and limits of the config,
+// tracing every request and serving the Prometheus metrics when they are enabled. Starting and
+// shutting it down is left to the caller.
+func NewServer(db *gorm.DB, cfg boot.ServerConfig, metricsCfg boot.MetricsConfig) *http.Server {
 	router := gin.New()
+	router.Use(middleware.RequestID(slog.Default()))
+	router.Use(middleware.Tracing())
+	router.Use(middleware.Metrics())
+	router.Use(middleware.MaxBodySize(cfg.MaxBodyBytes))
 
-	cfg := boot.GetConfig()
+	if metricsCfg.Enabled {
+		router.GET(metricsCfg.Path, gin.WrapH(metrics.Handler()))
+	}
 
 	InitAppRoutes(router, db)
 
-	serverAddr := fmt.Sprintf("%s:%v", cfg.AppConfig.Server.Host, cfg.AppConfig.Server.Port)
-	err := router.Run(serverAddr)
-	if err != nil {
-		log.Println("error while running the server:", err)
-		return
+	return &http.Server{
+		Addr:              fmt.Sprintf("%s:%v", cfg.Host, cfg.Port),
+		Handler:           router,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
 	}
-
-	log.Printf("Server Started Successfully & listening to Port: %v", cfg.AppConfig.Server.Port)
 }
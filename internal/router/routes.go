@@ -3,6 +3,7 @@ package router
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/vamshi1997/pismo-assessment/internal/controller"
+	"github.com/vamshi1997/pismo-assessment/internal/middleware"
 	"github.com/vamshi1997/pismo-assessment/internal/repo"
 	"gorm.io/gorm"
 )
@@ -14,7 +15,35 @@ func InitAppRoutes(router *gin.Engine, db *gorm.DB) {
 	newController := controller.NewController(newRepo)
 
 	router.GET("/status", controller.Status)
-	router.POST("/accounts", newController.CreateAccount)
+	router.GET("/operation-types", newController.ListOperationTypes)
+	router.PUT("/operation-types/:operationTypeId/accrual-rate", newController.SaveAccrualRate)
+	router.DELETE("/operation-types/:operationTypeId/accrual-rate", newController.DeleteAccrualRate)
+	router.GET("/accrual-rates", newController.ListAccrualRates)
+	router.POST("/accounts", middleware.Idempotency(newRepo), newController.CreateAccount)
 	router.GET("/accounts/:accountId", newController.GetAccount)
-	router.POST("/transactions", newController.CreateTransaction)
+	router.GET("/accounts/:accountId/transactions", newController.ListAccountTransactions)
+	router.GET("/accounts/:accountId/balance", newController.GetAccountBalance)
+	router.GET("/accounts/:accountId/limit", newController.GetAccountLimit)
+	router.PUT("/accounts/:accountId/limit", newController.UpdateAccountLimit)
+	router.DELETE("/accounts/:accountId/limit", newController.RemoveAccountLimit)
+	router.GET("/accounts/:accountId/limit/history", newController.ListAccountLimitChanges)
+	router.POST("/accounts/:accountId/block", newController.BlockAccount)
+	router.POST("/accounts/:accountId/unblock", newController.UnblockAccount)
+	router.POST("/accounts/:accountId/close", newController.CloseAccount)
+	router.GET("/accounts/:accountId/status-history", newController.ListAccountStatusChanges)
+	router.PUT("/accounts/:accountId/billing-cycle", newController.UpdateBillingCycle)
+	router.GET("/accounts/:accountId/statements", newController.ListStatements)
+	router.GET("/accounts/:accountId/statements/:statementId", newController.GetStatement)
+	router.POST("/webhooks", newController.CreateWebhook)
+	router.GET("/webhooks", newController.ListWebhooks)
+	router.GET("/webhooks/:webhookId", newController.GetWebhook)
+	router.DELETE("/webhooks/:webhookId", newController.DeleteWebhook)
+	router.GET("/webhooks/:webhookId/deliveries", newController.ListWebhookDeliveries)
+	router.POST("/webhooks/:webhookId/deliveries/:deliveryId/requeue", newController.RequeueWebhookDelivery)
+	router.POST("/transactions", middleware.Idempotency(newRepo), newController.CreateTransaction)
+	router.GET("/transactions/export", newController.ExportTransactions)
+	router.GET("/transactions/:transactionId/allocations", newController.ListTransactionAllocations)
+	router.GET("/transactions/:transactionId/installments", newController.GetInstallmentSchedule)
+	router.GET("/transactions/:transactionId/accruals", newController.ListTransactionAccruals)
+	router.POST("/transactions/:transactionId/reversal", middleware.Idempotency(newRepo), newController.ReverseTransaction)
 }
@@ -0,0 +1,112 @@
+package statement
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/vamshi1997/pismo-assessment/internal/logging"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+	"github.com/vamshi1997/pismo-assessment/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	defaultPollInterval = time.Hour
+	defaultBatchSize    = 100
+
+	// workerName tags the logs and spans of the generator
+	workerName = "statement_generator"
+)
+
+// Generator closes the billing cycles of all accounts in the background. Closing a cycle is idempotent,
+// so the generator can run on several instances and catch up on cycles missed while it was down.
+type Generator struct {
+	repo         repo.IRepository
+	pollInterval time.Duration
+	batchSize    int
+	now          func() time.Time
+}
+
+// NewGenerator creates a generator looking for closed billing cycles every pollInterval, going through
+// the accounts batchSize at a time. Zero values fall back to an hourly interval and batches of 100
+// accounts.
+func NewGenerator(repo repo.IRepository, pollInterval time.Duration, batchSize int) *Generator {
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	return &Generator{
+		repo:         repo,
+		pollInterval: pollInterval,
+		batchSize:    batchSize,
+		now:          time.Now,
+	}
+}
+
+// Run generates statements until stop is closed
+func (g *Generator) Run(stop <-chan struct{}) {
+	ctx := logging.WithLogger(context.Background(), slog.With("worker", workerName))
+	ticker := time.NewTicker(g.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		_, _ = g.RunOnce(ctx)
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce closes every billing cycle which ended by now and returns how many statements were generated.
+// An account whose statements can not be generated is skipped, so it does not hold back the others,
+// and the first error is returned once all accounts were visited.
+func (g *Generator) RunOnce(ctx context.Context) (int, error) {
+	ctx, span := tracing.Tracer().Start(ctx, workerName+".run_once")
+	defer span.End()
+
+	now := g.now()
+	generated := 0
+	var firstErr error
+
+	var afterID uint
+	for {
+		ids, err := g.repo.ListAccountIDs(ctx, afterID, g.batchSize)
+		if err != nil {
+			logging.FromContext(ctx).Error("error while listing accounts for statements", "error", err)
+			tracing.RecordError(span, err)
+			return generated, err
+		}
+
+		for _, id := range ids {
+			statements, err := g.repo.CloseStatements(ctx, id, now)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			generated += len(statements)
+		}
+
+		if len(ids) < g.batchSize {
+			break
+		}
+		afterID = ids[len(ids)-1]
+	}
+
+	if generated > 0 {
+		logging.FromContext(ctx).Info("statements generated", "count", generated)
+	}
+	span.SetAttributes(attribute.Int("generated", generated))
+	if firstErr != nil {
+		tracing.RecordError(span, firstErr)
+	}
+	return generated, firstErr
+}
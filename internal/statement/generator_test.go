@@ -0,0 +1,73 @@
+package statement
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo/mock"
+)
+
+func TestGenerator_RunOnce(t *testing.T) {
+	now := time.Date(2025, 3, 2, 9, 0, 0, 0, model.IST)
+
+	tests := []struct {
+		name              string
+		mockBehavior      func(m *mock.MockIRepository)
+		expectedGenerated int
+		expectedErr       error
+	}{
+		{
+			name: "Closes Cycles Of Every Account Page",
+			mockBehavior: func(m *mock.MockIRepository) {
+				gomock.InOrder(
+					m.EXPECT().ListAccountIDs(gomock.Any(), uint(0), 2).Return([]uint{1, 2}, nil),
+					m.EXPECT().CloseStatements(gomock.Any(), uint(1), now).Return([]model.Statement{{ID: 1}, {ID: 2}}, nil),
+					m.EXPECT().CloseStatements(gomock.Any(), uint(2), now).Return(nil, nil),
+					m.EXPECT().ListAccountIDs(gomock.Any(), uint(2), 2).Return([]uint{3}, nil),
+					m.EXPECT().CloseStatements(gomock.Any(), uint(3), now).Return([]model.Statement{{ID: 3}}, nil),
+				)
+			},
+			expectedGenerated: 3,
+		},
+		{
+			name: "Failing Account Does Not Stop The Others",
+			mockBehavior: func(m *mock.MockIRepository) {
+				gomock.InOrder(
+					m.EXPECT().ListAccountIDs(gomock.Any(), uint(0), 2).Return([]uint{1}, nil),
+					m.EXPECT().CloseStatements(gomock.Any(), uint(1), now).Return(nil, errors.New("lock wait timeout")),
+				)
+			},
+			expectedErr: errors.New("lock wait timeout"),
+		},
+		{
+			name: "Listing Accounts Fails",
+			mockBehavior: func(m *mock.MockIRepository) {
+				m.EXPECT().ListAccountIDs(gomock.Any(), uint(0), 2).Return(nil, errors.New("database error"))
+			},
+			expectedErr: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+
+			generator := NewGenerator(mockRepo, time.Minute, 2)
+			generator.now = func() time.Time { return now }
+
+			generated, err := generator.RunOnce(context.Background())
+
+			assert.Equal(t, tt.expectedGenerated, generated)
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
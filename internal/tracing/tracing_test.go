@@ -0,0 +1,110 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vamshi1997/pismo-assessment/internal/tracing/tracingtest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+type card struct {
+	ID     uint
+	Number string
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	values := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+func TestGormPlugin(t *testing.T) {
+	exporter := tracingtest.Install(t)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	require.NoError(t, db.Use(GormPlugin{}))
+	require.NoError(t, db.Exec("CREATE TABLE cards (id integer PRIMARY KEY, number varchar(19))").Error)
+	exporter.Reset()
+
+	ctx, parent := Tracer().Start(context.Background(), "GET /cards/:cardId")
+	tx := db.WithContext(ctx)
+	require.NoError(t, tx.Create(&card{ID: 1, Number: "4111111111111111"}).Error)
+	var found card
+	assert.ErrorIs(t, tx.First(&found, 2).Error, gorm.ErrRecordNotFound)
+	assert.Error(t, tx.Create(&card{ID: 1}).Error)
+	parent.End()
+
+	spans := exporter.GetSpans()
+	assert.Equal(t, []string{"db.create cards", "db.query cards", "db.create cards", "GET /cards/:cardId"}, tracingtest.Names(spans))
+
+	snapshots := spans.Snapshots()
+	for _, span := range snapshots[:3] {
+		assert.Equal(t, parent.SpanContext().TraceID(), span.SpanContext().TraceID())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+	}
+
+	created := attributes(snapshots[0])
+	assert.Equal(t, "sqlite", created["db.system"].AsString())
+	assert.Equal(t, "create", created["db.operation.name"].AsString())
+	assert.Equal(t, "cards", created["db.collection.name"].AsString())
+	assert.Equal(t, "INSERT INTO `cards` (`number`,`id`) VALUES (?,?) RETURNING `id`", created["db.query.text"].AsString())
+	assert.Equal(t, int64(1), created["db.rows_affected"].AsInt64())
+	assert.Equal(t, codes.Unset, snapshots[0].Status().Code)
+
+	// a record which is not found is not a failure, a broken constraint is
+	assert.Equal(t, codes.Unset, snapshots[1].Status().Code)
+	assert.Equal(t, codes.Error, snapshots[2].Status().Code)
+	require.Len(t, snapshots[2].Events(), 1)
+	assert.Equal(t, "exception", snapshots[2].Events()[0].Name)
+}
+
+func TestNewExporter(t *testing.T) {
+	exporter, err := NewExporter(context.Background(), Config{Exporter: ExporterNone}, &bytes.Buffer{})
+	assert.NoError(t, err)
+	assert.Nil(t, exporter)
+
+	exporter, err = NewExporter(context.Background(), Config{Exporter: ExporterOTLP, Endpoint: "collector:4318", Insecure: true}, &bytes.Buffer{})
+	assert.NoError(t, err)
+	assert.NotNil(t, exporter)
+	assert.NoError(t, exporter.Shutdown(context.Background()))
+
+	_, err = NewExporter(context.Background(), Config{Exporter: "jaeger"}, &bytes.Buffer{})
+	assert.EqualError(t, err, `unknown trace exporter "jaeger"`)
+}
+
+func TestNewProvider_Stdout(t *testing.T) {
+	var out bytes.Buffer
+	exporter, err := NewExporter(context.Background(), Config{Exporter: ExporterStdout}, &out)
+	require.NoError(t, err)
+
+	provider := NewProvider(Config{ServiceName: "pismo-test", SampleRatio: 1}, sdktrace.WithSyncer(exporter))
+	_, span := provider.Tracer(TracerName).Start(context.Background(), "accrual_engine.run_once")
+	span.End()
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	assert.Contains(t, out.String(), `"Name":"accrual_engine.run_once"`)
+	assert.Contains(t, out.String(), `"Value":"pismo-test"`)
+
+	// nothing is recorded with a zero sample ratio, unless the caller sampled the trace
+	provider = NewProvider(Config{SampleRatio: 0})
+	_, span = provider.Tracer(TracerName).Start(context.Background(), "outbox_relay.run_once")
+	assert.False(t, span.SpanContext().IsSampled())
+	span.End()
+}
@@ -0,0 +1,31 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/vamshi1997/pismo-assessment/internal/events"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+)
+
+// Publisher turns every published event into one pending delivery per subscription wanting it. The
+// HTTP calls are made later by the Worker, so a slow partner never holds up the outbox relay.
+type Publisher struct {
+	repo repo.IRepository
+}
+
+// NewPublisher creates a publisher enqueuing webhook deliveries
+func NewPublisher(repo repo.IRepository) *Publisher {
+	return &Publisher{repo: repo}
+}
+
+// Publish enqueues the deliveries of the event. The delivered body is the event envelope.
+func (p *Publisher) Publish(ctx context.Context, event events.Envelope) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.repo.EnqueueWebhookDeliveries(ctx, event.ID, event.Type, body)
+	return err
+}
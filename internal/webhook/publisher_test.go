@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vamshi1997/pismo-assessment/internal/boot"
+	"github.com/vamshi1997/pismo-assessment/internal/events"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+)
+
+// TestPublisher_RelaySQLite runs the relay with the webhook publisher on SQLite, which has a single
+// connection, so the publisher has to be able to use the database while events are relayed
+func TestPublisher_RelaySQLite(t *testing.T) {
+	db, err := boot.OpenDB(boot.DBConfig{Driver: boot.DriverSQLite, Path: ":memory:"})
+	require.NoError(t, err)
+	require.NoError(t, boot.Migrate(db))
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	r := repo.NewRepository(db)
+
+	_, err = r.CreateWebhookSubscription(ctx, model.WebhookSubscription{URL: "https://partner.example/hooks", EventTypes: model.EventAccountCreated, Secret: "secret"})
+	require.NoError(t, err)
+	_, err = r.CreateAccount(ctx, model.Account{DocumentNumber: "12345678900", BillingDay: model.DefaultBillingDay})
+	require.NoError(t, err)
+	_, err = r.CreateAccount(ctx, model.Account{DocumentNumber: "98765432100", BillingDay: model.DefaultBillingDay})
+	require.NoError(t, err)
+
+	// the second event fails once, so it is released and published by the next run
+	failures := 1
+	failing := events.NewMemoryPublisher()
+	failing.Subscribe("*", func(event events.Envelope) error {
+		if event.ID == 2 && failures > 0 {
+			failures--
+			return errors.New("broker unavailable")
+		}
+		return nil
+	})
+	relay := events.NewRelay(r, events.MultiPublisher{NewPublisher(r), failing}, time.Second, 10)
+
+	published, err := relay.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+
+	published, err = relay.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+
+	published, err = relay.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, published)
+
+	var deliveries []model.WebhookDelivery
+	require.NoError(t, db.Order("event_id ASC").Find(&deliveries).Error)
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, uint(1), deliveries[0].EventID)
+	assert.Equal(t, uint(2), deliveries[1].EventID)
+	assert.Equal(t, model.DeliveryPending, deliveries[0].Status)
+
+	var outbox []model.OutboxEvent
+	require.NoError(t, db.Order("id ASC").Find(&outbox).Error)
+	require.Len(t, outbox, 2)
+	for _, event := range outbox {
+		assert.NotNil(t, event.PublishedAt)
+		assert.Nil(t, event.ClaimedUntil)
+	}
+	assert.Equal(t, 2, outbox[1].Attempts)
+}
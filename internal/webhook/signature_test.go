@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	sentAt := time.Unix(1739181600, 0)
+	body := []byte(`{"id":1,"type":"account.created"}`)
+
+	header := Sign("whsec_test", sentAt, body)
+	assert.True(t, strings.HasPrefix(header, "t=1739181600,v1="))
+
+	tests := []struct {
+		name        string
+		secret      string
+		header      string
+		body        []byte
+		now         time.Time
+		expectedErr string
+	}{
+		{name: "Valid Signature", secret: "whsec_test", header: header, body: body, now: sentAt.Add(time.Minute)},
+		{name: "Wrong Secret", secret: "whsec_other", header: header, body: body, now: sentAt, expectedErr: "signature does not match"},
+		{name: "Changed Body", secret: "whsec_test", header: header, body: []byte(`{"id":2}`), now: sentAt, expectedErr: "signature does not match"},
+		{name: "Too Old", secret: "whsec_test", header: header, body: body, now: sentAt.Add(10 * time.Minute), expectedErr: "signature is too old"},
+		{name: "Malformed Header", secret: "whsec_test", header: "v1=abc", body: body, now: sentAt, expectedErr: "malformed signature header"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.header, tt.body, 5*time.Minute, tt.now)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	base := 10 * time.Second
+	max := time.Minute
+
+	assert.Equal(t, 10*time.Second, Backoff(1, base, max))
+	assert.Equal(t, 20*time.Second, Backoff(2, base, max))
+	assert.Equal(t, 40*time.Second, Backoff(3, base, max))
+	assert.Equal(t, time.Minute, Backoff(4, base, max))
+	assert.Equal(t, time.Minute, Backoff(40, base, max))
+}
+
+func TestNewSecret(t *testing.T) {
+	first, err := NewSecret()
+	assert.NoError(t, err)
+	second, err := NewSecret()
+	assert.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(first, "whsec_"))
+	assert.Len(t, first, len("whsec_")+64)
+	assert.NotEqual(t, first, second)
+}
@@ -0,0 +1,233 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vamshi1997/pismo-assessment/internal/logging"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo"
+	"github.com/vamshi1997/pismo-assessment/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WorkerConfig tunes the delivery worker. Zero values fall back to the defaults below.
+type WorkerConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	Timeout      time.Duration
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+}
+
+const (
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 50
+	defaultTimeout      = 10 * time.Second
+	defaultMaxAttempts  = 8
+	defaultBaseBackoff  = 10 * time.Second
+	defaultMaxBackoff   = time.Hour
+
+	// maxErrorBody is how much of a failed response is kept on the attempt
+	maxErrorBody = 512
+
+	// workerName tags the logs and spans of the worker
+	workerName = "webhook_worker"
+)
+
+// Worker sends due webhook deliveries. Failed deliveries are retried with exponential backoff and
+// dead-lettered after MaxAttempts attempts.
+type Worker struct {
+	repo   repo.IRepository
+	client *http.Client
+	config WorkerConfig
+	now    func() time.Time
+}
+
+// NewWorker creates a delivery worker
+func NewWorker(repo repo.IRepository, config WorkerConfig) *Worker {
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultPollInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultBatchSize
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = defaultBaseBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaultMaxBackoff
+	}
+
+	return &Worker{
+		repo:   repo,
+		client: &http.Client{Timeout: config.Timeout},
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Run sends due deliveries until stop is closed
+func (w *Worker) Run(stop <-chan struct{}) {
+	ctx := logging.WithLogger(context.Background(), slog.With("worker", workerName))
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		sent, err := w.RunOnce(ctx)
+		if err == nil && sent == w.config.BatchSize {
+			select {
+			case <-stop:
+				return
+			default:
+				continue
+			}
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce sends one batch of due deliveries and returns how many were attempted
+func (w *Worker) RunOnce(ctx context.Context) (int, error) {
+	ctx, span := tracing.Tracer().Start(ctx, workerName+".run_once")
+	defer span.End()
+
+	// the lease outlasts the HTTP calls of the whole batch, so no other worker picks them meanwhile
+	lease := w.now().Add(w.config.Timeout*time.Duration(w.config.BatchSize) + time.Minute)
+
+	deliveries, err := w.repo.ClaimDueWebhookDeliveries(ctx, w.config.BatchSize, lease)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		attempt := w.send(ctx, delivery)
+		status, nextAttemptAt := w.outcome(ctx, delivery, attempt)
+		if err = w.repo.RecordWebhookAttempt(ctx, attempt, status, nextAttemptAt); err != nil {
+			tracing.RecordError(span, err)
+			return 0, err
+		}
+	}
+
+	span.SetAttributes(attribute.Int("sent", len(deliveries)))
+	return len(deliveries), nil
+}
+
+// send makes one HTTP call for the delivery, in a client span whose trace context is passed on to the
+// subscriber in the traceparent header
+func (w *Worker) send(ctx context.Context, delivery model.WebhookDelivery) model.WebhookAttempt {
+	ctx, span := tracing.Tracer().Start(ctx, "webhook.deliver",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.Int64("webhook.delivery_id", int64(delivery.ID)),
+			attribute.String("webhook.event_type", delivery.EventType),
+		))
+	defer span.End()
+
+	attempt := w.attempt(ctx, delivery)
+	span.SetAttributes(attribute.Int("webhook.attempt", attempt.AttemptNumber))
+	if attempt.StatusCode != 0 {
+		span.SetAttributes(semconv.HTTPResponseStatusCode(attempt.StatusCode))
+	}
+	if attempt.Error != "" {
+		span.SetStatus(codes.Error, attempt.Error)
+	}
+	return attempt
+}
+
+func (w *Worker) attempt(ctx context.Context, delivery model.WebhookDelivery) model.WebhookAttempt {
+	started := w.now()
+	attempt := model.WebhookAttempt{
+		DeliveryID:    delivery.ID,
+		AttemptNumber: delivery.Attempts + 1,
+		CreatedAt:     started,
+	}
+
+	if delivery.Subscription.ID == 0 || delivery.Subscription.DeletedAt.Valid {
+		attempt.Error = "subscription was deleted"
+		return attempt
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventHeader, delivery.EventType)
+	request.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	request.Header.Set(SignatureHeader, Sign(delivery.Subscription.Secret, started, delivery.Payload))
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(request.Header))
+
+	response, err := w.client.Do(request)
+	attempt.DurationMs = w.now().Sub(started).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer response.Body.Close()
+
+	attempt.StatusCode = response.StatusCode
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorBody))
+		attempt.Error = fmt.Sprintf("unexpected status %d: %s", response.StatusCode, body)
+	}
+	return attempt
+}
+
+// outcome decides the status of the delivery after the attempt, and when it is tried again
+func (w *Worker) outcome(ctx context.Context, delivery model.WebhookDelivery, attempt model.WebhookAttempt) (string, time.Time) {
+	now := w.now()
+
+	switch {
+	case attempt.Error == "":
+		return model.DeliveryDelivered, now
+	case delivery.Subscription.ID == 0 || delivery.Subscription.DeletedAt.Valid:
+		return model.DeliveryDead, now
+	case attempt.AttemptNumber >= w.config.MaxAttempts:
+		logging.FromContext(ctx).Warn("webhook delivery dead-lettered", "delivery_id", delivery.ID, "attempts", attempt.AttemptNumber, "error", attempt.Error)
+		return model.DeliveryDead, now
+	default:
+		return model.DeliveryPending, now.Add(Backoff(attempt.AttemptNumber, w.config.BaseBackoff, w.config.MaxBackoff))
+	}
+}
+
+// Backoff is the wait after the given failed attempt: base, then twice as long after each further
+// failure, never more than max
+func Backoff(attempt int, base time.Duration, max time.Duration) time.Duration {
+	wait := base
+	for i := 1; i < attempt; i++ {
+		wait *= 2
+		if wait >= max {
+			return max
+		}
+	}
+	if wait > max {
+		return max
+	}
+	return wait
+}
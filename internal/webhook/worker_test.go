@@ -0,0 +1,146 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/vamshi1997/pismo-assessment/internal/events"
+	"github.com/vamshi1997/pismo-assessment/internal/model"
+	"github.com/vamshi1997/pismo-assessment/internal/repo/mock"
+	"gorm.io/gorm"
+)
+
+func TestWorker_RunOnce(t *testing.T) {
+	now := time.Date(2025, 2, 10, 10, 0, 0, 0, time.UTC)
+	payload := []byte(`{"id":9,"type":"transaction.created"}`)
+
+	tests := []struct {
+		name           string
+		responseStatus int
+		attempts       int
+		deleted        bool
+		expectedStatus string
+		expectedNext   time.Time
+		expectedCode   int
+		expectedCalls  int
+	}{
+		{
+			name:           "Delivered",
+			responseStatus: http.StatusNoContent,
+			expectedStatus: model.DeliveryDelivered,
+			expectedNext:   now,
+			expectedCode:   http.StatusNoContent,
+			expectedCalls:  1,
+		},
+		{
+			name:           "Failure Is Retried With Backoff",
+			responseStatus: http.StatusServiceUnavailable,
+			attempts:       2,
+			expectedStatus: model.DeliveryPending,
+			expectedNext:   now.Add(40 * time.Second),
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedCalls:  1,
+		},
+		{
+			name:           "Dead Lettered After Last Attempt",
+			responseStatus: http.StatusInternalServerError,
+			attempts:       4,
+			expectedStatus: model.DeliveryDead,
+			expectedNext:   now,
+			expectedCode:   http.StatusInternalServerError,
+			expectedCalls:  1,
+		},
+		{
+			name:           "Deleted Subscription",
+			deleted:        true,
+			expectedStatus: model.DeliveryDead,
+			expectedNext:   now,
+			expectedCalls:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				body, _ := io.ReadAll(r.Body)
+				assert.Equal(t, payload, body)
+				assert.Equal(t, model.EventTransactionCreated, r.Header.Get(EventHeader))
+				assert.Equal(t, "3", r.Header.Get(DeliveryHeader))
+				assert.NoError(t, Verify("whsec_test", r.Header.Get(SignatureHeader), body, time.Minute, now))
+				w.WriteHeader(tt.responseStatus)
+			}))
+			defer server.Close()
+
+			subscription := model.WebhookSubscription{ID: 5, URL: server.URL, EventTypes: "*", Secret: "whsec_test"}
+			if tt.deleted {
+				subscription.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+			}
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockIRepository(ctrl)
+			mockRepo.EXPECT().
+				ClaimDueWebhookDeliveries(gomock.Any(), 10, gomock.Any()).
+				Return([]model.WebhookDelivery{{
+					ID:             3,
+					SubscriptionID: 5,
+					Subscription:   subscription,
+					EventID:        9,
+					EventType:      model.EventTransactionCreated,
+					Payload:        payload,
+					Status:         model.DeliveryPending,
+					Attempts:       tt.attempts,
+				}}, nil)
+			mockRepo.EXPECT().
+				RecordWebhookAttempt(gomock.Any(), gomock.Any(), tt.expectedStatus, tt.expectedNext).
+				DoAndReturn(func(_ context.Context, attempt model.WebhookAttempt, status string, nextAttemptAt time.Time) error {
+					assert.Equal(t, uint(3), attempt.DeliveryID)
+					assert.Equal(t, tt.attempts+1, attempt.AttemptNumber)
+					assert.Equal(t, tt.expectedCode, attempt.StatusCode)
+					assert.Equal(t, tt.expectedStatus == model.DeliveryDelivered, attempt.Error == "")
+					return nil
+				})
+
+			worker := NewWorker(mockRepo, WorkerConfig{BatchSize: 10, MaxAttempts: 5, BaseBackoff: 10 * time.Second})
+			worker.now = func() time.Time { return now }
+
+			sent, err := worker.RunOnce(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, sent)
+			assert.Equal(t, tt.expectedCalls, calls)
+		})
+	}
+}
+
+func TestPublisher_Publish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockIRepository(ctrl)
+	mockRepo.EXPECT().
+		EnqueueWebhookDeliveries(gomock.Any(), uint(9), model.EventAccountCreated, gomock.Any()).
+		DoAndReturn(func(_ context.Context, eventId uint, eventType string, payload []byte) (int, error) {
+			assert.JSONEq(t, `{"id":9,"type":"account.created","aggregate_type":"account","aggregate_id":1,
+				"occurred_at":"0001-01-01T00:00:00Z","payload":{"account_id":1}}`, string(payload))
+			return 2, nil
+		})
+
+	err := NewPublisher(mockRepo).Publish(context.Background(), events.Envelope{
+		ID:            9,
+		Type:          model.EventAccountCreated,
+		AggregateType: model.AggregateAccount,
+		AggregateID:   1,
+		Payload:       []byte(`{"account_id":1}`),
+	})
+	assert.NoError(t, err)
+}